@@ -0,0 +1,313 @@
+package rbxmk
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cacheKeyHeaders are the request headers that, along with the URL, identify a
+// cached response.
+var cacheKeyHeaders = []string{
+	"Accept",
+	"Accept-Encoding",
+	"Accept-Language",
+}
+
+// cacheCredentialHeaders are the request headers that carry credentials.
+// Requests with any of these headers are not cached, so that responses
+// specific to a user are never shared through the cache.
+var cacheCredentialHeaders = []string{
+	"Authorization",
+	"Cookie",
+}
+
+// cacheVaryPrefix prefixes stored headers that record the values of request
+// headers named by the Vary header of the response.
+const cacheVaryPrefix = "X-Rbxmk-Cache-Vary-"
+
+// HttpCache is an http.RoundTripper that caches responses on disk.
+//
+// Only successful responses to GET requests are cached. Requests that carry
+// credentials and responses marked as private are not cached, and Set-Cookie
+// headers are never stored. A cached response is used directly while it is
+// fresh according to the Cache-Control or Expires headers. A stale response is
+// revalidated with the server using its ETag or Last-Modified headers. If the
+// server cannot be reached, then a stale response is used instead of returning
+// an error.
+type HttpCache struct {
+	// Dir is the directory in which responses are stored.
+	Dir string
+	// Transport is used to make requests. If nil, then http.DefaultTransport
+	// is used.
+	Transport http.RoundTripper
+	// Now returns the current time. If nil, then time.Now is used.
+	Now func() time.Time
+}
+
+// NewHttpCache returns an HttpCache that stores responses in dir, making
+// requests with transport.
+func NewHttpCache(dir string, transport http.RoundTripper) *HttpCache {
+	return &HttpCache{Dir: dir, Transport: transport}
+}
+
+func (c *HttpCache) transport() http.RoundTripper {
+	if c.Transport == nil {
+		return http.DefaultTransport
+	}
+	return c.Transport
+}
+
+func (c *HttpCache) now() time.Time {
+	if c.Now == nil {
+		return time.Now()
+	}
+	return c.Now()
+}
+
+// parseCacheControl returns the directives of the Cache-Control header in h.
+func parseCacheControl(h http.Header) map[string]string {
+	directives := map[string]string{}
+	for _, value := range h.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			directive = strings.TrimSpace(directive)
+			if directive == "" {
+				continue
+			}
+			name, arg, _ := strings.Cut(directive, "=")
+			directives[strings.ToLower(strings.TrimSpace(name))] = strings.Trim(strings.TrimSpace(arg), `"`)
+		}
+	}
+	return directives
+}
+
+// cacheable returns whether a request may be served from the cache.
+func cacheable(req *http.Request) bool {
+	if req.Method != "" && req.Method != "GET" {
+		return false
+	}
+	// Requests that manage caching or ranges themselves are passed through.
+	for _, name := range []string{"If-None-Match", "If-Modified-Since", "If-Match", "If-Unmodified-Since", "Range"} {
+		if req.Header.Get(name) != "" {
+			return false
+		}
+	}
+	for _, name := range cacheCredentialHeaders {
+		if req.Header.Get(name) != "" {
+			return false
+		}
+	}
+	_, noStore := parseCacheControl(req.Header)["no-store"]
+	return !noStore
+}
+
+// path returns the location of the file containing the cached response for a
+// request.
+func (c *HttpCache) path(req *http.Request) string {
+	h := sha256.New()
+	io.WriteString(h, req.URL.String())
+	for _, name := range cacheKeyHeaders {
+		io.WriteString(h, "\n"+name+": "+strings.Join(req.Header.Values(name), ", "))
+	}
+	return filepath.Join(c.Dir, hex.EncodeToString(h.Sum(nil)))
+}
+
+// load returns the cached response for a request, or nil if there is no
+// matching response.
+func (c *HttpCache) load(req *http.Request) *http.Response {
+	b, err := os.ReadFile(c.path(req))
+	if err != nil {
+		return nil
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(b)), req)
+	if err != nil {
+		return nil
+	}
+	for name, values := range resp.Header {
+		if !strings.HasPrefix(name, cacheVaryPrefix) {
+			continue
+		}
+		if strings.Join(req.Header.Values(strings.TrimPrefix(name, cacheVaryPrefix)), ", ") != strings.Join(values, ", ") {
+			resp.Body.Close()
+			return nil
+		}
+		delete(resp.Header, name)
+	}
+	return resp
+}
+
+// store writes resp to the cache as the response to req. The body of resp is
+// replaced with the stored content. Returns false if the response could not be
+// stored.
+func (c *HttpCache) store(req *http.Request, resp *http.Response) bool {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+
+	stored := *resp
+	stored.Header = resp.Header.Clone()
+	stored.Body = io.NopCloser(bytes.NewReader(body))
+	stored.ContentLength = int64(len(body))
+	stored.TransferEncoding = nil
+	stored.Header.Del("Transfer-Encoding")
+	// Cookies are specific to a session, and must not be replayed to others.
+	stored.Header.Del("Set-Cookie")
+	if stored.Header.Get("Date") == "" {
+		stored.Header.Set("Date", c.now().UTC().Format(http.TimeFormat))
+	}
+	for _, name := range resp.Header.Values("Vary") {
+		for _, name := range strings.Split(name, ",") {
+			name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+			stored.Header[cacheVaryPrefix+name] = []string{strings.Join(req.Header.Values(name), ", ")}
+		}
+	}
+	var buf bytes.Buffer
+	if err := stored.Write(&buf); err != nil {
+		return false
+	}
+
+	// Write to a temporary file first so that a partially written response is
+	// never read.
+	if err := os.MkdirAll(c.Dir, 0755); err != nil {
+		return false
+	}
+	f, err := os.CreateTemp(c.Dir, "tmp-*")
+	if err != nil {
+		return false
+	}
+	_, err = f.Write(buf.Bytes())
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), c.path(req))
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return false
+	}
+	return true
+}
+
+// storable returns whether resp may be stored in the cache.
+func storable(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusOK {
+		return false
+	}
+	directives := parseCacheControl(resp.Header)
+	if _, ok := directives["no-store"]; ok {
+		return false
+	}
+	// Only a shared cache is implemented, so private responses are not stored.
+	if _, ok := directives["private"]; ok {
+		return false
+	}
+	for _, vary := range resp.Header.Values("Vary") {
+		if strings.TrimSpace(vary) == "*" {
+			return false
+		}
+	}
+	// Without a freshness lifetime or validators, the response could never be
+	// reused.
+	return resp.Header.Get("ETag") != "" ||
+		resp.Header.Get("Last-Modified") != "" ||
+		resp.Header.Get("Expires") != "" ||
+		directives["max-age"] != ""
+}
+
+// fresh returns whether a cached response may be used without revalidation.
+func (c *HttpCache) fresh(req *http.Request, resp *http.Response) bool {
+	if _, ok := parseCacheControl(req.Header)["no-cache"]; ok {
+		return false
+	}
+	directives := parseCacheControl(resp.Header)
+	if _, ok := directives["no-cache"]; ok {
+		return false
+	}
+	date, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return false
+	}
+	var lifetime time.Duration
+	if maxAge, ok := directives["max-age"]; ok {
+		seconds, err := strconv.ParseInt(maxAge, 10, 64)
+		if err != nil {
+			return false
+		}
+		lifetime = time.Duration(seconds) * time.Second
+	} else if expires, err := http.ParseTime(resp.Header.Get("Expires")); err == nil {
+		lifetime = expires.Sub(date)
+	} else {
+		return false
+	}
+	return c.now().Sub(date) < lifetime
+}
+
+// RoundTrip implements http.RoundTripper.
+func (c *HttpCache) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !cacheable(req) {
+		return c.transport().RoundTrip(req)
+	}
+	cached := c.load(req)
+	if cached == nil {
+		resp, err := c.transport().RoundTrip(req)
+		if err == nil && storable(resp) {
+			c.store(req, resp)
+		}
+		return resp, err
+	}
+	if c.fresh(req, cached) {
+		return cached, nil
+	}
+
+	// Revalidate stale response.
+	revalidate := req.Clone(req.Context())
+	if etag := cached.Header.Get("ETag"); etag != "" {
+		revalidate.Header.Set("If-None-Match", etag)
+	}
+	if modified := cached.Header.Get("Last-Modified"); modified != "" {
+		revalidate.Header.Set("If-Modified-Since", modified)
+	}
+	resp, err := c.transport().RoundTrip(revalidate)
+	if err != nil {
+		// Server cannot be reached; use stale response.
+		return cached, nil
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotModified:
+		// Update cached response with new headers.
+		resp.Body.Close()
+		for _, name := range []string{"Cache-Control", "Date", "ETag", "Expires", "Last-Modified"} {
+			if values := resp.Header.Values(name); len(values) > 0 {
+				cached.Header[name] = values
+			}
+		}
+		c.store(req, cached)
+		return cached, nil
+	case resp.StatusCode >= 500:
+		// Server error; use stale response.
+		resp.Body.Close()
+		return cached, nil
+	}
+	cached.Body.Close()
+	if storable(resp) {
+		c.store(req, resp)
+	} else {
+		os.Remove(c.path(req))
+	}
+	return resp, nil
+}
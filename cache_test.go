@@ -0,0 +1,189 @@
+package rbxmk
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// cacheTester makes requests to a server through an HttpCache, counting the
+// requests that reach the server.
+type cacheTester struct {
+	t        *testing.T
+	server   *httptest.Server
+	cache    *HttpCache
+	client   *http.Client
+	requests int
+}
+
+func newCacheTester(t *testing.T, handler func(w http.ResponseWriter, r *http.Request)) *cacheTester {
+	c := &cacheTester{t: t}
+	c.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.requests++
+		handler(w, r)
+	}))
+	t.Cleanup(c.server.Close)
+	c.cache = NewHttpCache(t.TempDir(), nil)
+	c.client = &http.Client{Transport: c.cache}
+	return c
+}
+
+// get requests path with the given headers, and returns the response, whose
+// body has been read into body.
+func (c *cacheTester) get(path string, header http.Header) (resp *http.Response, body string) {
+	c.t.Helper()
+	req, err := http.NewRequest("GET", c.server.URL+path, nil)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	resp, err = c.client.Do(req)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		c.t.Fatalf("%s: unexpected status %s", path, resp.Status)
+	}
+	return resp, string(b)
+}
+
+// expect requests path, and checks the body of the response and the number of
+// requests that reached the server.
+func (c *cacheTester) expect(path string, header http.Header, wantRequests int) *http.Response {
+	c.t.Helper()
+	c.requests = 0
+	resp, body := c.get(path, header)
+	if body != "content of "+path {
+		c.t.Errorf("%s: unexpected body %q", path, body)
+	}
+	if c.requests != wantRequests {
+		c.t.Errorf("%s: expected %d requests, got %d", path, wantRequests, c.requests)
+	}
+	return resp
+}
+
+// stored returns the contents of each file in the cache directory.
+func (c *cacheTester) stored() [][]byte {
+	c.t.Helper()
+	entries, err := os.ReadDir(c.cache.Dir)
+	if err != nil && !os.IsNotExist(err) {
+		c.t.Fatal(err)
+	}
+	var files [][]byte
+	for _, entry := range entries {
+		b, err := os.ReadFile(filepath.Join(c.cache.Dir, entry.Name()))
+		if err != nil {
+			c.t.Fatal(err)
+		}
+		files = append(files, b)
+	}
+	return files
+}
+
+func TestHttpCache(t *testing.T) {
+	var revalidations int
+	c := newCacheTester(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/fresh":
+			w.Header().Set("Cache-Control", "max-age=60")
+		case "/etag":
+			w.Header().Set("ETag", `"v1"`)
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				revalidations++
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		case "/nostore":
+			w.Header().Set("Cache-Control", "no-store")
+		}
+		io.WriteString(w, "content of "+r.URL.Path)
+	})
+
+	c.expect("/fresh", nil, 1)
+	c.expect("/fresh", nil, 0)
+	c.expect("/etag", nil, 1)
+	c.expect("/etag", nil, 1)
+	if revalidations != 1 {
+		t.Errorf("expected 1 revalidation, got %d", revalidations)
+	}
+	c.expect("/nostore", nil, 1)
+	c.expect("/nostore", nil, 1)
+
+	// Stale responses are used when the server cannot be reached.
+	c.cache.Now = func() time.Time { return time.Now().Add(time.Hour) }
+	c.cache.Transport = roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return nil, errors.New("offline")
+	})
+	c.expect("/fresh", nil, 0)
+	c.expect("/etag", nil, 0)
+}
+
+func TestHttpCacheSetCookie(t *testing.T) {
+	c := newCacheTester(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Header().Set("Set-Cookie", "session=secret")
+		io.WriteString(w, "content of "+r.URL.Path)
+	})
+
+	if resp := c.expect("/cookie", nil, 1); resp.Header.Get("Set-Cookie") == "" {
+		t.Errorf("expected Set-Cookie to be passed through from server")
+	}
+	if resp := c.expect("/cookie", nil, 0); resp.Header.Get("Set-Cookie") != "" {
+		t.Errorf("unexpected Set-Cookie in cached response")
+	}
+	files := c.stored()
+	if len(files) != 1 {
+		t.Fatalf("expected 1 stored response, got %d", len(files))
+	}
+	if bytes.Contains(files[0], []byte("secret")) {
+		t.Errorf("cookie written to cache")
+	}
+}
+
+func TestHttpCacheCredentials(t *testing.T) {
+	c := newCacheTester(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=60")
+		io.WriteString(w, "content of "+r.URL.Path)
+	})
+
+	for _, header := range []http.Header{
+		{"Authorization": {"Bearer secret"}},
+		{"Cookie": {"session=secret"}},
+	} {
+		c.expect("/private", header, 1)
+		c.expect("/private", header, 1)
+	}
+	if files := c.stored(); len(files) != 0 {
+		t.Errorf("expected no stored responses, got %d", len(files))
+	}
+}
+
+func TestHttpCachePrivate(t *testing.T) {
+	c := newCacheTester(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "private, max-age=60")
+		io.WriteString(w, "content of "+r.URL.Path)
+	})
+
+	c.expect("/private", nil, 1)
+	c.expect("/private", nil, 1)
+	if files := c.stored(); len(files) != 0 {
+		t.Errorf("expected no stored responses, got %d", len(files))
+	}
+}
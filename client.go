@@ -1,9 +1,13 @@
 package rbxmk
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 )
 
 // UserAgent is the User-Agent header string sent with HTTP requests made by
@@ -11,6 +15,37 @@ import (
 // website APIs.
 const UserAgent = "RobloxStudio/WinInet rbxmk/0.0"
 
+// RetryPolicy specifies how a request is retried after receiving a response
+// that indicates a rate limit (429) or a server error (5xx).
+type RetryPolicy struct {
+	// Retries is the maximum number of times a request is retried.
+	Retries int
+	// Delay is the duration waited before the first retry. The duration is
+	// doubled for each subsequent retry. If the response has a Retry-After
+	// header, then the header is used instead.
+	Delay time.Duration
+	// MaxDelay is the maximum duration waited between retries, which does not
+	// apply to Retry-After. If zero, the duration is unbounded.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy is the RetryPolicy used by a Client returned by
+// NewClient. Requests are not retried by default.
+var DefaultRetryPolicy = RetryPolicy{
+	Retries:  0,
+	Delay:    time.Second,
+	MaxDelay: time.Minute,
+}
+
+// retryPolicyKey is the context key for a RetryPolicy.
+type retryPolicyKey struct{}
+
+// WithRetryPolicy returns a copy of ctx that causes a request made with the
+// context to use policy instead of the default policy of a Client.
+func WithRetryPolicy(ctx context.Context, policy RetryPolicy) context.Context {
+	return context.WithValue(ctx, retryPolicyKey{}, policy)
+}
+
 // Client wraps an http.Client to handle various additional behavior.
 type Client struct {
 	*http.Client
@@ -18,6 +53,12 @@ type Client struct {
 	mtx sync.Mutex
 	// Maps host name to token value.
 	csrfTokens map[string]string
+	// Default policy for retrying requests.
+	retry RetryPolicy
+	// Limits the number of concurrent requests, or nil if unlimited.
+	sem chan struct{}
+	// Default maximum duration of requests, or 0 for no limit.
+	timeout time.Duration
 }
 
 // NewClient returns an initialized Client. If *client* is nil, then
@@ -29,16 +70,122 @@ func NewClient(client *http.Client) *Client {
 	return &Client{
 		Client:     client,
 		csrfTokens: make(map[string]string, 1),
+		retry:      DefaultRetryPolicy,
+	}
+}
+
+// RetryPolicy returns the default policy for retrying requests.
+func (c *Client) RetryPolicy() RetryPolicy {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.retry
+}
+
+// SetRetryPolicy sets the default policy for retrying requests. The policy may
+// be overridden per request with WithRetryPolicy.
+func (c *Client) SetRetryPolicy(policy RetryPolicy) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.retry = policy
+}
+
+// Concurrency returns the maximum number of requests that may run concurrently
+// through HttpRequests. Returns 0 if unlimited.
+func (c *Client) Concurrency() int {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return cap(c.sem)
+}
+
+// SetConcurrency sets the maximum number of requests that may run concurrently
+// through HttpRequests. If n is less than or equal to 0, then the number is
+// unlimited. Requests that have already started are not affected.
+func (c *Client) SetConcurrency(n int) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if n <= 0 {
+		c.sem = nil
+		return
+	}
+	c.sem = make(chan struct{}, n)
+}
+
+// Timeout returns the default maximum duration of requests made through
+// HttpRequests. Returns 0 if there is no limit.
+func (c *Client) Timeout() time.Duration {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.timeout
+}
+
+// SetTimeout sets the default maximum duration of requests made through
+// HttpRequests, including reading the response body. If d is less than or
+// equal to 0, then there is no limit.
+func (c *Client) SetTimeout(d time.Duration) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if d < 0 {
+		d = 0
+	}
+	c.timeout = d
+}
+
+// acquire blocks until a request may run according to the concurrency limit,
+// or until ctx is done. On success, release must be called when the request
+// is finished.
+func (c *Client) acquire(ctx context.Context) (release func(), err error) {
+	c.mtx.Lock()
+	sem := c.sem
+	c.mtx.Unlock()
+	if sem == nil {
+		return func() {}, nil
+	}
+	select {
+	case sem <- struct{}{}:
+		return func() { <-sem }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
 	}
 }
 
+// retryAfter returns the duration indicated by the Retry-After header of h,
+// which is either a number of seconds or a date. Returns false if the header
+// is absent or invalid.
+func retryAfter(h http.Header, now time.Time) (d time.Duration, ok bool) {
+	value := strings.TrimSpace(h.Get("Retry-After"))
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d = t.Sub(now); d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
 // Do sends a request, with the following additional behaviors:
 //
 //     - Includes a configured user agent header with the request, if the header
 //       is unset.
 //     - Handles CSRF token validation.
+//     - Retries the request according to a RetryPolicy when the response
+//       indicates a rate limit or server error.
 func (c *Client) Do(req *http.Request) (resp *http.Response, err error) {
-	for i := 2; i > 0; i-- {
+	policy, ok := req.Context().Value(retryPolicyKey{}).(RetryPolicy)
+	if !ok {
+		policy = c.RetryPolicy()
+	}
+	delay := policy.Delay
+	csrf := false
+	for retries := 0; ; {
 		// Merge headers.
 		c.mtx.Lock()
 		if req.Header.Get("User-Agent") == "" {
@@ -53,9 +200,14 @@ func (c *Client) Do(req *http.Request) (resp *http.Response, err error) {
 		if resp, err = c.Client.Do(req); err != nil {
 			return resp, err
 		}
-		switch resp.StatusCode {
+		var wait time.Duration
+		switch {
 		// Check for failed CSRF token.
-		case 403:
+		case resp.StatusCode == 403:
+			if csrf {
+				// Already retried with new token.
+				return resp, err
+			}
 			token := resp.Header.Get("X-Csrf-Token")
 			if token == "" {
 				// No token; regular 403.
@@ -64,19 +216,46 @@ func (c *Client) Do(req *http.Request) (resp *http.Response, err error) {
 			c.mtx.Lock()
 			c.csrfTokens[req.URL.Host] = token
 			c.mtx.Unlock()
-			// Reset body if needed.
-			if req.Body != nil {
-				if req.GetBody == nil {
-					return nil, fmt.Errorf("retry failed: cannot reset body")
+			csrf = true
+			// Retry with new token.
+		// Check for rate limit or server error.
+		case resp.StatusCode == 429 || resp.StatusCode >= 500:
+			if retries >= policy.Retries {
+				return resp, err
+			}
+			retries++
+			var ok bool
+			if wait, ok = retryAfter(resp.Header, time.Now()); !ok {
+				wait = delay
+				if delay *= 2; policy.MaxDelay > 0 && delay > policy.MaxDelay {
+					delay = policy.MaxDelay
 				}
-				if req.Body, err = req.GetBody(); err != nil {
-					return nil, fmt.Errorf("retry failed: reset body: %w", err)
+				if policy.MaxDelay > 0 && wait > policy.MaxDelay {
+					wait = policy.MaxDelay
 				}
 			}
-			// Retry with new token.
+			// Retry after waiting.
 		default:
 			return resp, err
 		}
+		resp.Body.Close()
+		// Reset body if needed.
+		if req.Body != nil {
+			if req.GetBody == nil {
+				return nil, fmt.Errorf("retry failed: cannot reset body")
+			}
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, fmt.Errorf("retry failed: reset body: %w", err)
+			}
+		}
+		if wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-timer.C:
+			case <-req.Context().Done():
+				timer.Stop()
+				return nil, req.Context().Err()
+			}
+		}
 	}
-	return resp, err
 }
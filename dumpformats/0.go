@@ -160,3 +160,12 @@ func sortConstructors(m dump.Constructors, cb func(k string, v dump.MultiFunctio
 		cb(key, m[key])
 	}
 }
+
+func sortedEnvFields(ref *dump.EnvRef) []string {
+	keys := make([]string, 0, len(ref.Fields))
+	for key := range ref.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,541 @@
+package dumpformats
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/anaminus/rbxmk/dump"
+	"github.com/anaminus/rbxmk/dump/dt"
+)
+
+func init() { register(Luau) }
+
+var Luau = Format{
+	Name: "luau",
+	Func: func(w io.Writer, root dump.Root, opts Options) error {
+		buf := bufio.NewWriter(w)
+		l := luauWriter{buf: buf, types: root.Types}
+		buf.WriteString("-- Luau type definitions for the rbxmk environment.\n")
+
+		// Types.
+		sortTypeDefs(root.Types, func(name string, def dump.TypeDef) {
+			l.writeTypeDef(name, def)
+		})
+
+		// Globals.
+		if env := root.Environment; env != nil {
+			for _, name := range sortedEnvFields(env) {
+				if luauBuiltins[name] || !isName(name) {
+					continue
+				}
+				var t string
+				if ref := env.Fields[name]; len(ref.Fields) > 0 {
+					t = l.envType(root, ref, 0)
+				} else if t = l.valueType(root.Resolve(ref.Path...), 0); t == "" {
+					continue
+				}
+				buf.WriteString("\ndeclare ")
+				buf.WriteString(name)
+				buf.WriteString(": ")
+				buf.WriteString(t)
+				buf.WriteString("\n")
+			}
+		}
+		return buf.Flush()
+	},
+}
+
+// luauBuiltins contains the globals defined by the standard Luau environment.
+// These are already known to Luau, and cannot be redeclared.
+var luauBuiltins = map[string]bool{
+	"_G":           true,
+	"_VERSION":     true,
+	"assert":       true,
+	"bit32":        true,
+	"buffer":       true,
+	"coroutine":    true,
+	"debug":        true,
+	"error":        true,
+	"gcinfo":       true,
+	"getfenv":      true,
+	"getmetatable": true,
+	"ipairs":       true,
+	"loadstring":   true,
+	"math":         true,
+	"newproxy":     true,
+	"next":         true,
+	"os":           true,
+	"pairs":        true,
+	"pcall":        true,
+	"print":        true,
+	"rawequal":     true,
+	"rawget":       true,
+	"rawlen":       true,
+	"rawset":       true,
+	"require":      true,
+	"select":       true,
+	"setfenv":      true,
+	"setmetatable": true,
+	"string":       true,
+	"table":        true,
+	"tonumber":     true,
+	"tostring":     true,
+	"type":         true,
+	"typeof":       true,
+	"unpack":       true,
+	"utf8":         true,
+	"xpcall":       true,
+}
+
+// luauKeywords contains the reserved words of Luau, which cannot be used as
+// names.
+var luauKeywords = map[string]bool{
+	"and":      true,
+	"break":    true,
+	"do":       true,
+	"else":     true,
+	"elseif":   true,
+	"end":      true,
+	"false":    true,
+	"for":      true,
+	"function": true,
+	"if":       true,
+	"in":       true,
+	"local":    true,
+	"nil":      true,
+	"not":      true,
+	"or":       true,
+	"repeat":   true,
+	"return":   true,
+	"then":     true,
+	"true":     true,
+	"until":    true,
+	"while":    true,
+}
+
+// luauPrims maps primitive types of the dump to Luau types.
+var luauPrims = map[string]string{
+	"any":      "any",
+	"bool":     "boolean",
+	"boolean":  "boolean",
+	"double":   "number",
+	"false":    "false",
+	"float":    "number",
+	"function": "(...any) -> ...any",
+	"int":      "number",
+	"int64":    "number",
+	"integer":  "number",
+	"nil":      "nil",
+	"number":   "number",
+	"string":   "string",
+	"table":    "{[any]: any}",
+	"true":     "true",
+	"Variant":  "any",
+}
+
+type luauWriter struct {
+	buf   *bufio.Writer
+	types dump.TypeDefs
+}
+
+// writeTypeDef writes a type definition as a class, or as a type alias if the
+// type has an underlying type and no members.
+func (l luauWriter) writeTypeDef(name string, def dump.TypeDef) {
+	if _, ok := luauPrims[name]; ok || !isName(name) {
+		return
+	}
+	if t := def.Underlying; t != nil &&
+		len(def.Properties) == 0 &&
+		len(def.Methods) == 0 &&
+		def.Operators == nil {
+		if p, ok := t.Kind.(dt.KindPrim); !ok || string(p) != name {
+			l.buf.WriteString("\ntype ")
+			l.buf.WriteString(name)
+			l.buf.WriteString(" = ")
+			l.buf.WriteString(l.typeString(*t))
+			l.buf.WriteString("\n")
+			return
+		}
+	}
+
+	l.buf.WriteString("\ndeclare class ")
+	l.buf.WriteString(name)
+	l.buf.WriteString("\n")
+
+	// Properties.
+	sortProperties(def.Properties, func(propName string, prop dump.Property) {
+		if !isName(propName) {
+			return
+		}
+		l.buf.WriteString("\t")
+		l.buf.WriteString(propName)
+		l.buf.WriteString(": ")
+		l.buf.WriteString(l.typeString(prop.ValueType))
+		l.buf.WriteString("\n")
+	})
+
+	// Methods.
+	sortMethods(def.Methods, func(methodName string, method dump.Function) {
+		if !isName(methodName) {
+			return
+		}
+		l.writeMethod(methodName, method.Parameters, method.Returns)
+	})
+
+	// Operators.
+	if op := def.Operators; op != nil {
+		self := dt.Parameters{{Name: "op", Type: dt.Prim(name)}}
+		binops := []struct {
+			name string
+			ops  []dump.Binop
+		}{
+			{"__add", op.Add},
+			{"__sub", op.Sub},
+			{"__mul", op.Mul},
+			{"__div", op.Div},
+			{"__mod", op.Mod},
+			{"__pow", op.Pow},
+			{"__concat", op.Concat},
+		}
+		for _, binop := range binops {
+			for _, o := range binop.ops {
+				l.writeMethod(binop.name,
+					dt.Parameters{{Name: "op", Type: o.Operand}},
+					dt.Parameters{{Type: o.Result}},
+				)
+			}
+		}
+		boolean := dt.Parameters{{Type: dt.Prim("boolean")}}
+		if op.Eq != nil {
+			l.writeMethod("__eq", self, boolean)
+		}
+		if op.Lt != nil {
+			l.writeMethod("__lt", self, boolean)
+		}
+		if op.Le != nil {
+			l.writeMethod("__le", self, boolean)
+		}
+		if op.Len != nil {
+			l.writeMethod("__len", nil, dt.Parameters{{Type: op.Len.Result}})
+		}
+		if op.Unm != nil {
+			l.writeMethod("__unm", nil, dt.Parameters{{Type: op.Unm.Result}})
+		}
+		if op.Call != nil {
+			l.writeMethod("__call", op.Call.Parameters, op.Call.Returns)
+		}
+		if op.Index != nil && len(op.Index.Parameters) > 0 && len(op.Index.Returns) > 0 {
+			l.buf.WriteString("\t[")
+			l.buf.WriteString(l.typeString(op.Index.Parameters[0].Type))
+			l.buf.WriteString("]: ")
+			l.buf.WriteString(l.typeString(op.Index.Returns[0].Type))
+			l.buf.WriteString("\n")
+		}
+	}
+
+	l.buf.WriteString("end\n")
+
+	// Constructors are declared as globals through the environment.
+}
+
+// writeMethod writes a method of a class.
+func (l luauWriter) writeMethod(name string, params, returns dt.Parameters) {
+	l.buf.WriteString("\tfunction ")
+	l.buf.WriteString(name)
+	l.buf.WriteString("(self")
+	for _, param := range params {
+		l.buf.WriteString(", ")
+		l.buf.WriteString(l.parameterString(param))
+	}
+	l.buf.WriteString(")")
+	if len(returns) > 0 {
+		l.buf.WriteString(": ")
+		l.buf.WriteString(l.returnsString(returns))
+	}
+	l.buf.WriteString("\n")
+}
+
+// parameterString returns a named parameter of a function.
+func (l luauWriter) parameterString(param dt.Parameter) string {
+	if param.Name == "..." {
+		return "...: " + l.typeString(param.Type)
+	}
+	t := l.typeString(param.Type)
+	if s := luauEnumString(param.Enums); s != "" {
+		t = s
+		if _, ok := param.Type.Kind.(dt.KindOptional); ok {
+			t = "(" + t + ")?"
+		}
+	} else if param.Default != "" {
+		t = luauOptional(t)
+	}
+	name := param.Name
+	if !isName(name) {
+		name = "_"
+	} else if luauKeywords[name] {
+		name += "_"
+	}
+	return name + ": " + t
+}
+
+// luauEnumString returns a union of string singletons from enums, or an empty
+// string if any enum is not a string literal.
+func luauEnumString(enums dt.Enums) string {
+	if len(enums) == 0 {
+		return ""
+	}
+	for _, enum := range enums {
+		if len(enum) < 2 || enum[0] != '"' || enum[len(enum)-1] != '"' {
+			return ""
+		}
+	}
+	return strings.Join(enums, " | ")
+}
+
+// luauOptional returns t as an optional type.
+func luauOptional(t string) string {
+	if strings.HasSuffix(t, "?") {
+		return t
+	}
+	if strings.Contains(t, "->") || strings.Contains(t, "|") || strings.Contains(t, "&") {
+		return "(" + t + ")?"
+	}
+	return t + "?"
+}
+
+// returnsString returns the return types of a function.
+func (l luauWriter) returnsString(returns dt.Parameters) string {
+	if len(returns) == 1 && returns[0].Name != "..." {
+		t := l.typeString(returns[0].Type)
+		if strings.Contains(t, "->") {
+			t = "(" + t + ")"
+		}
+		return t
+	}
+	var s strings.Builder
+	s.WriteByte('(')
+	for i, r := range returns {
+		if i > 0 {
+			s.WriteString(", ")
+		}
+		if r.Name == "..." {
+			s.WriteString("...")
+		}
+		s.WriteString(l.typeString(r.Type))
+	}
+	s.WriteByte(')')
+	return s.String()
+}
+
+// functionString returns the type of a function with the given parameters and
+// returns.
+func (l luauWriter) functionString(params, returns dt.Parameters) string {
+	var s strings.Builder
+	s.WriteByte('(')
+	for i, param := range params {
+		if i > 0 {
+			s.WriteString(", ")
+		}
+		if param.Name == "..." {
+			// Variadic types within function types are unnamed.
+			s.WriteString("..." + l.typeString(param.Type))
+			continue
+		}
+		s.WriteString(l.parameterString(param))
+	}
+	s.WriteString(") -> ")
+	if len(returns) == 0 {
+		s.WriteString("()")
+	} else {
+		s.WriteString(l.returnsString(returns))
+	}
+	return s.String()
+}
+
+// multiFunctionString returns the intersection of the types of each function.
+func (l luauWriter) multiFunctionString(funcs dump.MultiFunction) string {
+	if len(funcs) == 0 {
+		return "(...any) -> ...any"
+	}
+	types := make([]string, len(funcs))
+	for i, fn := range funcs {
+		types[i] = l.functionString(fn.Parameters, fn.Returns)
+		if len(funcs) > 1 {
+			types[i] = "(" + types[i] + ")"
+		}
+	}
+	return strings.Join(types, " & ")
+}
+
+// primString returns the Luau type corresponding to a primitive type.
+func (l luauWriter) primString(name string) string {
+	if t, ok := luauPrims[name]; ok {
+		return t
+	}
+	if strings.HasPrefix(name, "Enum.") {
+		if _, ok := l.types["EnumItem"]; ok {
+			return "EnumItem"
+		}
+	}
+	if _, ok := l.types[name]; ok && isName(name) {
+		return name
+	}
+	return "any"
+}
+
+// fieldName returns name as the key of a table type.
+func luauFieldName(name string) string {
+	if isName(name) && !luauKeywords[name] {
+		return name
+	}
+	return "[" + strconv.Quote(name) + "]"
+}
+
+// typeString returns the Luau type corresponding to t.
+func (l luauWriter) typeString(t dt.Type) string {
+	switch k := t.Kind.(type) {
+	case nil:
+		return "any"
+	case dt.KindPrim:
+		return l.primString(string(k))
+	case dt.KindOptional:
+		if g, ok := k.Type.Kind.(dt.KindGroup); ok {
+			return "(" + l.typeString(g.Type) + ")?"
+		}
+		return luauOptional(l.typeString(k.Type))
+	case dt.KindGroup:
+		return "(" + l.typeString(k.Type) + ")"
+	case dt.KindOr:
+		types := make([]string, len(k))
+		for i, t := range k {
+			types[i] = l.typeString(t)
+			if strings.Contains(types[i], "->") {
+				types[i] = "(" + types[i] + ")"
+			}
+		}
+		return strings.Join(types, " | ")
+	case dt.KindArray:
+		return "{" + l.typeString(k.Type) + "}"
+	case dt.KindDictionary:
+		return "{[string]: " + l.typeString(k.Type) + "}"
+	case dt.KindMap:
+		return "{[" + l.typeString(k.K) + "]: " + l.typeString(k.V) + "}"
+	case dt.KindStruct:
+		return l.structString(k, nil)
+	case dt.KindTable:
+		return l.structString(k.Fields, &k)
+	case dt.KindFunction:
+		return l.functionString(k.Parameters, k.Returns)
+	case dt.KindMultiFunctionType:
+		return "(...any) -> ...any"
+	}
+	return "any"
+}
+
+// structString returns a table type with the given fields, and an optional
+// indexer.
+func (l luauWriter) structString(fields dt.KindStruct, indexer *dt.KindTable) string {
+	var s strings.Builder
+	s.WriteByte('{')
+	n := 0
+	if indexer != nil {
+		s.WriteString("[")
+		s.WriteString(l.typeString(indexer.Key))
+		s.WriteString("]: ")
+		s.WriteString(l.typeString(indexer.Value))
+		n++
+	}
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if n > 0 {
+			s.WriteString(", ")
+		}
+		if key == "..." {
+			s.WriteString("[number]: ")
+		} else {
+			s.WriteString(luauFieldName(key))
+			s.WriteString(": ")
+		}
+		s.WriteString(l.typeString(fields[key]))
+		n++
+	}
+	s.WriteByte('}')
+	return s.String()
+}
+
+// valueType returns the type of a value of the dump, or an empty string if the
+// value has no type.
+func (l luauWriter) valueType(value any, depth int) string {
+	switch v := value.(type) {
+	case dump.Property:
+		return l.typeString(v.ValueType)
+	case dump.Function:
+		return l.functionString(v.Parameters, v.Returns)
+	case dump.MultiFunction:
+		return l.multiFunctionString(v)
+	case dump.Struct:
+		fields := map[string]string{}
+		for name, field := range v.Fields {
+			if t := l.valueType(field, depth+1); t != "" {
+				fields[name] = t
+			}
+		}
+		return luauTableString(fields, depth)
+	case dump.Enum:
+		if _, ok := l.types["Enum"]; ok {
+			return "Enum"
+		}
+		return "any"
+	}
+	return ""
+}
+
+// envType returns the type of a table in the environment.
+func (l luauWriter) envType(root dump.Root, ref *dump.EnvRef, depth int) string {
+	fields := map[string]string{}
+	for name, sub := range ref.Fields {
+		var t string
+		if len(sub.Fields) > 0 {
+			t = l.envType(root, sub, depth+1)
+		} else {
+			t = l.valueType(root.Resolve(sub.Path...), depth+1)
+		}
+		if t != "" {
+			fields[name] = t
+		}
+	}
+	return luauTableString(fields, depth)
+}
+
+// luauTableString returns a table type with the given field types, with one
+// field per line.
+func luauTableString(fields map[string]string, depth int) string {
+	if len(fields) == 0 {
+		return "{}"
+	}
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	indent := strings.Repeat("\t", depth+1)
+	var s strings.Builder
+	s.WriteString("{\n")
+	for _, key := range keys {
+		s.WriteString(indent)
+		s.WriteString(luauFieldName(key))
+		s.WriteString(": ")
+		s.WriteString(fields[key])
+		s.WriteString(",\n")
+	}
+	s.WriteString(indent[:depth])
+	s.WriteString("}")
+	return s.String()
+}
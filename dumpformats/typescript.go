@@ -0,0 +1,597 @@
+package dumpformats
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/anaminus/rbxmk/dump"
+	"github.com/anaminus/rbxmk/dump/dt"
+)
+
+func init() { register(TypeScript) }
+
+var TypeScript = Format{
+	Name: "typescript",
+	Func: func(w io.Writer, root dump.Root, opts Options) error {
+		buf := bufio.NewWriter(w)
+		t := tsWriter{buf: buf, types: root.Types}
+		buf.WriteString("// TypeScript declarations for the rbxmk environment.\n")
+
+		// Types.
+		sortTypeDefs(root.Types, func(name string, def dump.TypeDef) {
+			t.writeTypeDef(name, def)
+		})
+
+		// Globals.
+		if env := root.Environment; env != nil {
+			for _, name := range sortedEnvFields(env) {
+				// The standard Luau globals are declared by roblox-ts.
+				if luauBuiltins[name] || !isName(name) || tsReserved[name] {
+					continue
+				}
+				ref := env.Fields[name]
+				switch {
+				case len(ref.Fields) == 0:
+					t.writeGlobal(name, root.Resolve(ref.Path...))
+				case tsIsConstructorTable(ref):
+					t.writeConstructors(root, name, ref)
+				default:
+					buf.WriteString("\ndeclare namespace ")
+					buf.WriteString(name)
+					buf.WriteString(" {\n")
+					t.writeNamespace(root, ref, 1)
+					buf.WriteString("}\n")
+				}
+			}
+		}
+		return buf.Flush()
+	},
+}
+
+// tsReserved contains the reserved words of TypeScript, which cannot be used
+// as names of declarations or parameters.
+var tsReserved = map[string]bool{
+	"break":      true,
+	"case":       true,
+	"catch":      true,
+	"class":      true,
+	"const":      true,
+	"continue":   true,
+	"debugger":   true,
+	"default":    true,
+	"delete":     true,
+	"do":         true,
+	"else":       true,
+	"enum":       true,
+	"export":     true,
+	"extends":    true,
+	"false":      true,
+	"finally":    true,
+	"for":        true,
+	"function":   true,
+	"if":         true,
+	"implements": true,
+	"import":     true,
+	"in":         true,
+	"instanceof": true,
+	"interface":  true,
+	"let":        true,
+	"new":        true,
+	"null":       true,
+	"package":    true,
+	"private":    true,
+	"protected":  true,
+	"public":     true,
+	"return":     true,
+	"static":     true,
+	"super":      true,
+	"switch":     true,
+	"this":       true,
+	"throw":      true,
+	"true":       true,
+	"try":        true,
+	"typeof":     true,
+	"var":        true,
+	"void":       true,
+	"while":      true,
+	"with":       true,
+	"yield":      true,
+}
+
+// tsPrims maps primitive types of the dump to TypeScript types. Array and
+// Dictionary would otherwise conflict with the global types of TypeScript.
+var tsPrims = map[string]string{
+	"any":        "any",
+	"Array":      "any[]",
+	"Dictionary": "{ [key: string]: any }",
+	"bool":       "boolean",
+	"boolean":    "boolean",
+	"double":     "number",
+	"false":      "false",
+	"float":      "number",
+	"function":   "(...args: any[]) => any",
+	"int":        "number",
+	"int64":      "number",
+	"integer":    "number",
+	"nil":        "undefined",
+	"number":     "number",
+	"string":     "string",
+	"table":      "object",
+	"true":       "true",
+	"Variant":    "any",
+}
+
+// tsIsConstructorTable returns whether ref is a table containing the
+// constructors of a type.
+func tsIsConstructorTable(ref *dump.EnvRef) bool {
+	for _, sub := range ref.Fields {
+		if len(sub.Path) > 0 && sub.Path[0] == "Types" {
+			return true
+		}
+	}
+	return false
+}
+
+// tsName returns name as the name of a parameter.
+func tsName(name string) string {
+	if !isName(name) {
+		return "_"
+	}
+	if tsReserved[name] {
+		return name + "_"
+	}
+	return name
+}
+
+// tsPropertyName returns name as the key of a property.
+func tsPropertyName(name string) string {
+	if isName(name) {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+// tsWrap encloses t in parentheses if it is a union, intersection, or function
+// type.
+func tsWrap(t string) string {
+	if strings.Contains(t, "=>") || strings.Contains(t, "|") || strings.Contains(t, "&") {
+		return "(" + t + ")"
+	}
+	return t
+}
+
+// tsOptional returns t as a type that includes undefined.
+func tsOptional(t string) string {
+	if t == "any" || t == "undefined" || strings.HasSuffix(t, " | undefined") {
+		return t
+	}
+	if strings.Contains(t, "=>") {
+		t = "(" + t + ")"
+	}
+	return t + " | undefined"
+}
+
+type tsWriter struct {
+	buf   *bufio.Writer
+	types dump.TypeDefs
+}
+
+// writeTypeDef writes a type definition as an interface, or as a type alias if
+// the type has an underlying type and no members.
+func (t tsWriter) writeTypeDef(name string, def dump.TypeDef) {
+	if _, ok := tsPrims[name]; ok || !isName(name) {
+		return
+	}
+	if u := def.Underlying; u != nil &&
+		len(def.Properties) == 0 &&
+		len(def.Methods) == 0 &&
+		def.Operators == nil {
+		if p, ok := u.Kind.(dt.KindPrim); !ok || string(p) != name {
+			t.buf.WriteString("\ntype ")
+			t.buf.WriteString(name)
+			t.buf.WriteString(" = ")
+			t.buf.WriteString(t.typeString(*u))
+			t.buf.WriteString(";\n")
+			return
+		}
+	}
+
+	t.buf.WriteString("\ninterface ")
+	t.buf.WriteString(name)
+	t.buf.WriteString(" {\n")
+
+	// Properties.
+	sortProperties(def.Properties, func(propName string, prop dump.Property) {
+		t.buf.WriteString("\t")
+		if prop.ReadOnly {
+			t.buf.WriteString("readonly ")
+		}
+		t.buf.WriteString(tsPropertyName(propName))
+		t.buf.WriteString(": ")
+		t.buf.WriteString(t.typeString(prop.ValueType))
+		t.buf.WriteString(";\n")
+	})
+
+	// Methods.
+	sortMethods(def.Methods, func(methodName string, method dump.Function) {
+		t.buf.WriteString("\t")
+		t.buf.WriteString(tsPropertyName(methodName))
+		t.buf.WriteString(t.signatureString(method.Parameters, method.Returns, ": "))
+		t.buf.WriteString(";\n")
+	})
+
+	// Operators. Only calling and indexing have equivalents in TypeScript.
+	if op := def.Operators; op != nil {
+		if op.Call != nil {
+			t.buf.WriteString("\t")
+			t.buf.WriteString(t.signatureString(op.Call.Parameters, op.Call.Returns, ": "))
+			t.buf.WriteString(";\n")
+		}
+		if op.Index != nil && len(op.Index.Parameters) > 0 && len(op.Index.Returns) > 0 {
+			// An index signature must include the types of every member, so
+			// only untyped indexes are included.
+			key := t.typeString(op.Index.Parameters[0].Type)
+			value := t.typeString(op.Index.Returns[0].Type)
+			if value == "any" && (key == "string" || key == "number") {
+				t.buf.WriteString("\t[key: ")
+				t.buf.WriteString(key)
+				t.buf.WriteString("]: any;\n")
+			}
+		}
+	}
+
+	t.buf.WriteString("}\n")
+}
+
+// writeGlobal writes the declaration of a global value.
+func (t tsWriter) writeGlobal(name string, value any) {
+	switch v := value.(type) {
+	case dump.Property:
+		if v.ReadOnly {
+			t.buf.WriteString("\ndeclare const ")
+		} else {
+			t.buf.WriteString("\ndeclare let ")
+		}
+		t.buf.WriteString(name)
+		t.buf.WriteString(": ")
+		t.buf.WriteString(t.typeString(v.ValueType))
+		t.buf.WriteString(";\n")
+	case dump.Function:
+		t.buf.WriteString("\ndeclare function ")
+		t.buf.WriteString(name)
+		t.buf.WriteString(t.signatureString(v.Parameters, v.Returns, ": "))
+		t.buf.WriteString(";\n")
+	case dump.MultiFunction:
+		t.buf.WriteString("\n")
+		for _, fn := range v {
+			t.buf.WriteString("declare function ")
+			t.buf.WriteString(name)
+			t.buf.WriteString(t.signatureString(fn.Parameters, fn.Returns, ": "))
+			t.buf.WriteString(";\n")
+		}
+	}
+}
+
+// writeConstructors writes the table of constructors of a type as an
+// interface, and declares a global of that interface.
+func (t tsWriter) writeConstructors(root dump.Root, name string, ref *dump.EnvRef) {
+	t.buf.WriteString("\ninterface ")
+	t.buf.WriteString(name)
+	t.buf.WriteString("Constructor {\n")
+	for _, field := range sortedEnvFields(ref) {
+		switch v := root.Resolve(ref.Fields[field].Path...).(type) {
+		case dump.Function:
+			t.buf.WriteString("\t")
+			t.buf.WriteString(tsPropertyName(field))
+			t.buf.WriteString(": ")
+			t.buf.WriteString(t.signatureString(v.Parameters, v.Returns, " => "))
+			t.buf.WriteString(";\n")
+		case dump.MultiFunction:
+			t.buf.WriteString("\t")
+			t.buf.WriteString(tsPropertyName(field))
+			t.buf.WriteString(": ")
+			t.buf.WriteString(t.multiFunctionString(v))
+			t.buf.WriteString(";\n")
+		case dump.Property:
+			t.buf.WriteString("\t")
+			if v.ReadOnly {
+				t.buf.WriteString("readonly ")
+			}
+			t.buf.WriteString(tsPropertyName(field))
+			t.buf.WriteString(": ")
+			t.buf.WriteString(t.typeString(v.ValueType))
+			t.buf.WriteString(";\n")
+		}
+	}
+	t.buf.WriteString("}\n")
+	t.buf.WriteString("declare const ")
+	t.buf.WriteString(name)
+	t.buf.WriteString(": ")
+	t.buf.WriteString(name)
+	t.buf.WriteString("Constructor;\n")
+}
+
+// writeNamespace writes the members of a namespace.
+func (t tsWriter) writeNamespace(root dump.Root, ref *dump.EnvRef, depth int) {
+	indent := strings.Repeat("\t", depth)
+	for _, name := range sortedEnvFields(ref) {
+		if !isName(name) || tsReserved[name] {
+			continue
+		}
+		sub := ref.Fields[name]
+		if len(sub.Fields) > 0 {
+			t.buf.WriteString(indent)
+			t.buf.WriteString("namespace ")
+			t.buf.WriteString(name)
+			t.buf.WriteString(" {\n")
+			t.writeNamespace(root, sub, depth+1)
+			t.buf.WriteString(indent)
+			t.buf.WriteString("}\n")
+			continue
+		}
+		t.writeNamespaceValue(name, root.Resolve(sub.Path...), depth)
+	}
+}
+
+// writeNamespaceValue writes a value as a member of a namespace.
+func (t tsWriter) writeNamespaceValue(name string, value any, depth int) {
+	indent := strings.Repeat("\t", depth)
+	switch v := value.(type) {
+	case dump.Property:
+		t.buf.WriteString(indent)
+		if v.ReadOnly {
+			t.buf.WriteString("const ")
+		} else {
+			t.buf.WriteString("let ")
+		}
+		t.buf.WriteString(name)
+		t.buf.WriteString(": ")
+		t.buf.WriteString(t.typeString(v.ValueType))
+		t.buf.WriteString(";\n")
+	case dump.Function:
+		t.buf.WriteString(indent)
+		t.buf.WriteString("function ")
+		t.buf.WriteString(name)
+		t.buf.WriteString(t.signatureString(v.Parameters, v.Returns, ": "))
+		t.buf.WriteString(";\n")
+	case dump.MultiFunction:
+		for _, fn := range v {
+			t.buf.WriteString(indent)
+			t.buf.WriteString("function ")
+			t.buf.WriteString(name)
+			t.buf.WriteString(t.signatureString(fn.Parameters, fn.Returns, ": "))
+			t.buf.WriteString(";\n")
+		}
+	case dump.Struct:
+		t.buf.WriteString(indent)
+		t.buf.WriteString("namespace ")
+		t.buf.WriteString(name)
+		t.buf.WriteString(" {\n")
+		sortFields(v.Fields, func(name string, field dump.Value) {
+			if isName(name) && !tsReserved[name] {
+				t.writeNamespaceValue(name, field, depth+1)
+			}
+		})
+		t.buf.WriteString(indent)
+		t.buf.WriteString("}\n")
+	case dump.Enum:
+		t.buf.WriteString(indent)
+		t.buf.WriteString("const ")
+		t.buf.WriteString(name)
+		t.buf.WriteString(": ")
+		if _, ok := t.types["Enum"]; ok {
+			t.buf.WriteString("Enum")
+		} else {
+			t.buf.WriteString("any")
+		}
+		t.buf.WriteString(";\n")
+	}
+}
+
+// parametersString returns the parameter list of a function. An optional
+// parameter is marked as such only if no required parameter follows it.
+func (t tsWriter) parametersString(params dt.Parameters) string {
+	required := len(params)
+	for ; required > 0; required-- {
+		param := params[required-1]
+		if param.Name == "..." {
+			continue
+		}
+		if _, ok := param.Type.Kind.(dt.KindOptional); !ok && param.Default == "" {
+			break
+		}
+	}
+	var s strings.Builder
+	s.WriteByte('(')
+	for i, param := range params {
+		if i > 0 {
+			s.WriteString(", ")
+		}
+		if param.Name == "..." {
+			s.WriteString("...args: ")
+			s.WriteString(tsWrap(t.typeString(param.Type)))
+			s.WriteString("[]")
+			continue
+		}
+		typ := t.typeString(param.Type)
+		if enums := tsEnumString(param.Enums); enums != "" {
+			typ = enums
+			if _, ok := param.Type.Kind.(dt.KindOptional); ok {
+				typ = tsOptional(typ)
+			}
+		}
+		s.WriteString(tsName(param.Name))
+		if i >= required {
+			s.WriteString("?")
+			typ = strings.TrimSuffix(typ, " | undefined")
+		} else if param.Default != "" {
+			typ = tsOptional(typ)
+		}
+		s.WriteString(": ")
+		s.WriteString(typ)
+	}
+	s.WriteByte(')')
+	return s.String()
+}
+
+// tsEnumString returns a union of string literals from enums, or an empty
+// string if any enum is not a string literal.
+func tsEnumString(enums dt.Enums) string {
+	if len(enums) == 0 {
+		return ""
+	}
+	for _, enum := range enums {
+		if len(enum) < 2 || enum[0] != '"' || enum[len(enum)-1] != '"' {
+			return ""
+		}
+	}
+	return strings.Join(enums, " | ")
+}
+
+// returnsString returns the return type of a function. Multiple values are
+// returned as a LuaTuple.
+func (t tsWriter) returnsString(returns dt.Parameters) string {
+	switch {
+	case len(returns) == 0:
+		return "void"
+	case len(returns) == 1 && returns[0].Name == "...":
+		return "LuaTuple<" + tsWrap(t.typeString(returns[0].Type)) + "[]>"
+	case len(returns) == 1:
+		return t.typeString(returns[0].Type)
+	}
+	var s strings.Builder
+	s.WriteString("LuaTuple<[")
+	for i, r := range returns {
+		if i > 0 {
+			s.WriteString(", ")
+		}
+		if r.Name == "..." {
+			s.WriteString("...")
+			s.WriteString(tsWrap(t.typeString(r.Type)))
+			s.WriteString("[]")
+			continue
+		}
+		s.WriteString(t.typeString(r.Type))
+	}
+	s.WriteString("]>")
+	return s.String()
+}
+
+// signatureString returns the signature of a function, with sep separating the
+// parameters from the return type.
+func (t tsWriter) signatureString(params, returns dt.Parameters, sep string) string {
+	return t.parametersString(params) + sep + t.returnsString(returns)
+}
+
+// multiFunctionString returns the intersection of the types of each function.
+func (t tsWriter) multiFunctionString(funcs dump.MultiFunction) string {
+	if len(funcs) == 0 {
+		return "(...args: any[]) => any"
+	}
+	types := make([]string, len(funcs))
+	for i, fn := range funcs {
+		types[i] = t.signatureString(fn.Parameters, fn.Returns, " => ")
+		if len(funcs) > 1 {
+			types[i] = "(" + types[i] + ")"
+		}
+	}
+	return strings.Join(types, " & ")
+}
+
+// primString returns the TypeScript type corresponding to a primitive type.
+func (t tsWriter) primString(name string) string {
+	if typ, ok := tsPrims[name]; ok {
+		return typ
+	}
+	if strings.HasPrefix(name, "Enum.") {
+		if _, ok := t.types["EnumItem"]; ok {
+			return "EnumItem"
+		}
+	}
+	if _, ok := t.types[name]; ok && isName(name) {
+		return name
+	}
+	return "any"
+}
+
+// typeString returns the TypeScript type corresponding to typ.
+func (t tsWriter) typeString(typ dt.Type) string {
+	switch k := typ.Kind.(type) {
+	case nil:
+		return "any"
+	case dt.KindPrim:
+		return t.primString(string(k))
+	case dt.KindOptional:
+		if g, ok := k.Type.Kind.(dt.KindGroup); ok {
+			return tsOptional(t.typeString(g.Type))
+		}
+		return tsOptional(t.typeString(k.Type))
+	case dt.KindGroup:
+		return "(" + t.typeString(k.Type) + ")"
+	case dt.KindOr:
+		types := make([]string, len(k))
+		for i, typ := range k {
+			types[i] = t.typeString(typ)
+			if strings.Contains(types[i], "=>") {
+				types[i] = "(" + types[i] + ")"
+			}
+		}
+		return strings.Join(types, " | ")
+	case dt.KindArray:
+		return tsWrap(t.typeString(k.Type)) + "[]"
+	case dt.KindDictionary:
+		return "{ [key: string]: " + t.typeString(k.Type) + " }"
+	case dt.KindMap:
+		key := t.typeString(k.K)
+		if key == "string" || key == "number" {
+			return "{ [key: " + key + "]: " + t.typeString(k.V) + " }"
+		}
+		return "Map<" + key + ", " + t.typeString(k.V) + ">"
+	case dt.KindStruct:
+		return t.structString(k, nil)
+	case dt.KindTable:
+		return t.structString(k.Fields, &k)
+	case dt.KindFunction:
+		return t.signatureString(k.Parameters, k.Returns, " => ")
+	case dt.KindMultiFunctionType:
+		return "(...args: any[]) => any"
+	}
+	return "any"
+}
+
+// structString returns an object type with the given fields, and an optional
+// index signature. Fields with optional types are optional properties.
+func (t tsWriter) structString(fields dt.KindStruct, indexer *dt.KindTable) string {
+	var members []string
+	if indexer != nil {
+		key := t.typeString(indexer.Key)
+		if key != "number" {
+			key = "string"
+		}
+		members = append(members, "[key: "+key+"]: "+t.typeString(indexer.Value))
+	}
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		field := fields[key]
+		if key == "..." {
+			members = append(members, "[index: number]: "+t.typeString(field))
+			continue
+		}
+		if _, ok := field.Kind.(dt.KindOptional); ok {
+			members = append(members, tsPropertyName(key)+"?: "+strings.TrimSuffix(t.typeString(field), " | undefined"))
+			continue
+		}
+		members = append(members, tsPropertyName(key)+": "+t.typeString(field))
+	}
+	if len(members) == 0 {
+		return "{}"
+	}
+	return "{ " + strings.Join(members, "; ") + " }"
+}
@@ -54,3 +54,12 @@ type FormatOptions interface {
 	// exist.
 	ValueOf(field string) types.Value
 }
+
+// PathResolver is implemented by a FormatOptions that is able to decode files
+// relative to the location of the data being decoded. Formats that refer to
+// other files may use it to resolve those files.
+type PathResolver interface {
+	// ResolvePath decodes the file or directory at path, which is relative to
+	// the location of the data being decoded.
+	ResolvePath(path string) (types.Value, error)
+}
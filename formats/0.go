@@ -104,3 +104,27 @@ func boolOf(f rbxmk.FormatOptions, field string) (v bool, ok bool) {
 		return true, false
 	}
 }
+
+// stringsOf returns field as a list of strings. Returns nil if the field does
+// not exist. Returns an error if the field is not an array of strings.
+func stringsOf(f rbxmk.FormatOptions, field string) (v []string, err error) {
+	if f == nil {
+		return nil, nil
+	}
+	switch a := f.ValueOf(field).(type) {
+	case nil, rtypes.NilType:
+		return nil, nil
+	case rtypes.Array:
+		v = make([]string, len(a))
+		for i, e := range a {
+			s, ok := e.(types.Stringlike)
+			if !ok {
+				return nil, fmt.Errorf("option %s: element %d must be a string", field, i+1)
+			}
+			v[i] = s.Stringlike()
+		}
+		return v, nil
+	default:
+		return nil, fmt.Errorf("option %s: expected array", field)
+	}
+}
@@ -0,0 +1,323 @@
+package formats
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/anaminus/rbxmk"
+	"github.com/anaminus/rbxmk/dump"
+	"github.com/anaminus/rbxmk/dump/dt"
+	"github.com/anaminus/rbxmk/reflect"
+	"github.com/anaminus/rbxmk/rtypes"
+	"github.com/robloxapi/types"
+)
+
+func jsonModelTypes() []func() rbxmk.Reflector {
+	return []func() rbxmk.Reflector{
+		reflect.Instance,
+	}
+}
+
+// jsonModel is the structure of an encoded instance.
+type jsonModel struct {
+	Name       string                 `json:",omitempty"`
+	ClassName  string                 `json:",omitempty"`
+	Properties map[string]interface{} `json:",omitempty"`
+	Attributes map[string]interface{} `json:",omitempty"`
+	Children   []*jsonModel           `json:",omitempty"`
+}
+
+// jsonField returns the value of the first field in m that matches one of the
+// given names.
+func jsonField(m map[string]interface{}, names ...string) (v interface{}, ok bool) {
+	for _, name := range names {
+		if v, ok = m[name]; ok {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// attrPropertyOf returns the name of the property that holds the attributes of
+// inst.
+func attrPropertyOf(g rtypes.Global, inst *rtypes.Instance) string {
+	if attrcfg := g.AttrConfig.Of(inst); attrcfg != nil {
+		return attrcfg.Property
+	}
+	return "AttributesSerialize"
+}
+
+// jsonModelDecoder decodes instances from JSON.
+type jsonModelDecoder struct {
+	g rtypes.Global
+	// class is the class of the instance described by metadata, used when the
+	// metadata has no ClassName.
+	class string
+}
+
+// instance decodes u into an instance. full is the full name of the instance,
+// used for errors. If children is false, then the instance is decoded as
+// metadata, where the Name and Children fields are not allowed, and ClassName
+// is optional. If omitted, the class is d.class, which is required to decode
+// properties.
+func (d jsonModelDecoder) instance(full string, u interface{}, children bool) (inst *rtypes.Instance, err error) {
+	m, ok := u.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: instance must be an object", full)
+	}
+
+	var className string
+	if v, ok := jsonField(m, "ClassName", "className"); ok {
+		if className, ok = v.(string); !ok {
+			return nil, fmt.Errorf("%s: ClassName must be a string", full)
+		}
+	} else if children {
+		return nil, fmt.Errorf("%s: ClassName required", full)
+	} else {
+		className = d.class
+	}
+	if className == "DataModel" {
+		inst = rtypes.NewDataModel()
+	} else {
+		inst = rtypes.NewInstance(className, nil)
+	}
+	desc := d.g.Desc
+	if class := desc.Class(className); class != nil {
+		inst.IsService = class.GetTag("Service")
+	}
+
+	if v, ok := jsonField(m, "ignoreUnknownInstances"); ok {
+		if _, ok := v.(bool); !ok {
+			return nil, fmt.Errorf("%s: ignoreUnknownInstances must be a boolean", full)
+		}
+	}
+
+	if v, ok := jsonField(m, "Name", "name"); ok {
+		if !children {
+			return nil, fmt.Errorf("%s: unexpected Name", full)
+		}
+		name, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: Name must be a string", full)
+		}
+		inst.SetName(name)
+		full = name
+	}
+
+	if v, ok := jsonField(m, "Properties", "properties"); ok {
+		props, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: Properties must be an object", full)
+		}
+		if className == "" && len(props) > 0 {
+			return nil, fmt.Errorf("%s: ClassName required to decode properties", full)
+		}
+		for _, prop := range sortedKeys(props) {
+			value, err := decodeJSONProperty(desc, className, prop, props[prop])
+			if err != nil {
+				return nil, fmt.Errorf("%s: property %s: %w", full, prop, err)
+			}
+			inst.Set(prop, value)
+		}
+	}
+
+	if v, ok := jsonField(m, "Attributes", "attributes"); ok {
+		attrs, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: Attributes must be an object", full)
+		}
+		dict := make(rtypes.Dictionary, len(attrs))
+		for name, attr := range attrs {
+			value, err := decodeJSONProperty(nil, "", "", attr)
+			if err != nil {
+				return nil, fmt.Errorf("%s: attribute %s: %w", full, name, err)
+			}
+			dict[name] = value
+		}
+		var w bytes.Buffer
+		if err := rtypes.EncodeAttributes(&w, dict); err != nil {
+			return nil, fmt.Errorf("%s: %w", full, err)
+		}
+		inst.Set(attrPropertyOf(d.g, inst), types.BinaryString(w.Bytes()))
+	}
+
+	if v, ok := jsonField(m, "Children", "children"); ok {
+		if !children {
+			return nil, fmt.Errorf("%s: unexpected Children", full)
+		}
+		a, ok := v.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: Children must be an array", full)
+		}
+		for i, u := range a {
+			child, err := d.instance(fmt.Sprintf("%s[%d]", full, i+1), u, true)
+			if err != nil {
+				return nil, err
+			}
+			child.SetParent(inst)
+		}
+	}
+	return inst, nil
+}
+
+// encodeJSONModel encodes inst into a jsonModel. If children is false, then
+// the instance is encoded as metadata, where the Name and Children fields are
+// excluded.
+//
+// References to other instances cannot be encoded, and are skipped.
+func encodeJSONModel(g rtypes.Global, inst *rtypes.Instance, children bool) (m *jsonModel, err error) {
+	desc := g.Desc.Of(inst)
+	attrProp := attrPropertyOf(g, inst)
+	m = &jsonModel{ClassName: inst.ClassName}
+	if children {
+		m.Name = inst.Name()
+	}
+	for _, prop := range inst.PropertyNames() {
+		value := inst.Get(prop)
+		switch value.(type) {
+		case nil, *rtypes.Instance:
+			continue
+		}
+		if prop == "Name" {
+			if _, ok := value.(types.String); ok {
+				continue
+			}
+		}
+		if prop == attrProp {
+			if s, ok := value.(types.Stringlike); ok {
+				if s.Stringlike() == "" {
+					continue
+				}
+				if attrs, err := rtypes.DecodeAttributes(strings.NewReader(s.Stringlike())); err == nil {
+					dict := attrs.(rtypes.Dictionary)
+					if len(dict) == 0 {
+						continue
+					}
+					m.Attributes = make(map[string]interface{}, len(dict))
+					for name, attr := range dict {
+						if m.Attributes[name], err = encodeJSONAttribute(attr); err != nil {
+							return nil, fmt.Errorf("%s: attribute %s: %w", inst.GetFullName(), name, err)
+						}
+					}
+					continue
+				}
+			}
+		}
+		u, err := encodeJSONProperty(desc, inst.ClassName, prop, value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: property %s: %w", inst.GetFullName(), prop, err)
+		}
+		if m.Properties == nil {
+			m.Properties = map[string]interface{}{}
+		}
+		m.Properties[prop] = u
+	}
+	if children {
+		for _, child := range inst.Children() {
+			c, err := encodeJSONModel(g, child, true)
+			if err != nil {
+				return nil, err
+			}
+			m.Children = append(m.Children, c)
+		}
+	}
+	return m, nil
+}
+
+// writeJSONModel writes m to w as indented JSON.
+func writeJSONModel(w io.Writer, m *jsonModel) error {
+	j := json.NewEncoder(w)
+	j.SetIndent("", "\t")
+	j.SetEscapeHTML(false)
+	return j.Encode(m)
+}
+
+const F_ModelJSON = "model.json"
+
+func init() { register(ModelJSON) }
+func ModelJSON() rbxmk.Format {
+	return rbxmk.Format{
+		Name:       F_ModelJSON,
+		MediaTypes: []string{"application/json", "text/plain"},
+		CanDecode: func(g rtypes.Global, f rbxmk.FormatOptions, typeName string) bool {
+			return typeName == rtypes.T_Instance
+		},
+		Decode: func(g rtypes.Global, f rbxmk.FormatOptions, r io.Reader) (v types.Value, err error) {
+			var u interface{}
+			if err := json.NewDecoder(r).Decode(&u); err != nil {
+				return nil, err
+			}
+			return jsonModelDecoder{g: g}.instance("model", u, true)
+		},
+		Encode: func(g rtypes.Global, f rbxmk.FormatOptions, w io.Writer, v types.Value) error {
+			inst, ok := v.(*rtypes.Instance)
+			if !ok {
+				return cannotEncode(v)
+			}
+			m, err := encodeJSONModel(g, inst, true)
+			if err != nil {
+				return err
+			}
+			return writeJSONModel(w, m)
+		},
+		Dump: func() dump.Format {
+			return dump.Format{
+				Summary:     "Formats/model.json:Summary",
+				Description: "Formats/model.json:Description",
+			}
+		},
+		Types: jsonModelTypes(),
+	}
+}
+
+const F_MetaJSON = "meta.json"
+
+func init() { register(MetaJSON) }
+func MetaJSON() rbxmk.Format {
+	return rbxmk.Format{
+		Name:       F_MetaJSON,
+		MediaTypes: []string{"application/json", "text/plain"},
+		Options: map[string][]string{
+			"ClassName": {rtypes.T_String},
+		},
+		CanDecode: func(g rtypes.Global, f rbxmk.FormatOptions, typeName string) bool {
+			return typeName == rtypes.T_Instance
+		},
+		Decode: func(g rtypes.Global, f rbxmk.FormatOptions, r io.Reader) (v types.Value, err error) {
+			var u interface{}
+			if err := json.NewDecoder(r).Decode(&u); err != nil {
+				return nil, err
+			}
+			class, _ := stringOf(f, "ClassName")
+			return jsonModelDecoder{g: g, class: class}.instance("meta", u, false)
+		},
+		Encode: func(g rtypes.Global, f rbxmk.FormatOptions, w io.Writer, v types.Value) error {
+			inst, ok := v.(*rtypes.Instance)
+			if !ok {
+				return cannotEncode(v)
+			}
+			m, err := encodeJSONModel(g, inst, false)
+			if err != nil {
+				return err
+			}
+			return writeJSONModel(w, m)
+		},
+		Dump: func() dump.Format {
+			return dump.Format{
+				Options: dump.FormatOptions{
+					"ClassName": dump.FormatOption{
+						Type:        dt.Optional(dt.Prim(rtypes.T_String)),
+						Default:     "nil",
+						Description: "Formats/meta.json:Options/ClassName",
+					},
+				},
+				Summary:     "Formats/meta.json:Summary",
+				Description: "Formats/meta.json:Description",
+			}
+		},
+		Types: jsonModelTypes(),
+	}
+}
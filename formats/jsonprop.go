@@ -0,0 +1,789 @@
+package formats
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/anaminus/rbxmk/rtypes"
+	"github.com/robloxapi/rbxdump"
+	"github.com/robloxapi/types"
+)
+
+// jsonTypeNames maps alternative names of explicit property types to the names
+// used by descriptors.
+var jsonTypeNames = map[string]string{
+	"Bool":           "bool",
+	"String":         "string",
+	"Int32":          "int",
+	"Int64":          "int64",
+	"Float32":        "float",
+	"Float64":        "double",
+	"OptionalCFrame": "CFrame?",
+}
+
+// jsonPropType returns the descriptor type corresponding to the name of an
+// explicit property type.
+func jsonPropType(name string) rbxdump.Type {
+	if name == "Enum" {
+		return rbxdump.Type{Category: "Enum"}
+	}
+	if n, ok := jsonTypeNames[name]; ok {
+		name = n
+	}
+	return rbxdump.Type{Name: name}
+}
+
+// decodeJSONProperty decodes JSON value u into the value of property prop of
+// class. The value is explicit when it is an object with a single field that
+// names the type of the value. Otherwise, the value is implicit, and the type
+// is taken from the descriptor of the property within desc. Without a
+// descriptor, only primitive types can be decoded implicitly.
+func decodeJSONProperty(desc *rtypes.Desc, class, prop string, u interface{}) (v types.PropValue, err error) {
+	if m, ok := u.(map[string]interface{}); ok && len(m) == 1 {
+		for name, value := range m {
+			typ := jsonPropType(name)
+			if desc != nil && typ.Category == "Enum" {
+				if p := desc.Property(class, prop); p != nil && p.ValueType.Category == "Enum" {
+					typ = p.ValueType
+				}
+			}
+			return decodeJSONType(desc, typ, value)
+		}
+	}
+	if desc != nil {
+		if p := desc.Property(class, prop); p != nil {
+			return decodeJSONType(desc, p.ValueType, u)
+		}
+	}
+	switch u := u.(type) {
+	case bool:
+		return types.Bool(u), nil
+	case float64:
+		return types.Double(u), nil
+	case string:
+		return types.String(u), nil
+	}
+	return nil, fmt.Errorf("cannot determine type of property")
+}
+
+// decodeJSONType decodes JSON value u into a value of type typ.
+func decodeJSONType(desc *rtypes.Desc, typ rbxdump.Type, u interface{}) (v types.PropValue, err error) {
+	if strings.HasSuffix(typ.Name, "?") {
+		typ.Name = strings.TrimSuffix(typ.Name, "?")
+		if u == nil {
+			v, err := decodeJSONType(desc, typ, decodeJSONZero(typ))
+			if err != nil {
+				return nil, err
+			}
+			return rtypes.None(v.Type()), nil
+		}
+		v, err := decodeJSONType(desc, typ, u)
+		if err != nil {
+			return nil, err
+		}
+		return rtypes.Some(v), nil
+	}
+	switch typ.Category {
+	case "Class":
+		return nil, fmt.Errorf("cannot decode reference")
+	case "Enum":
+		switch u := u.(type) {
+		case float64:
+			return types.Token(u), nil
+		case string:
+			if typ.Name == "" || desc == nil {
+				return nil, fmt.Errorf("cannot decode enum item name without descriptor")
+			}
+			enum := desc.Enum(typ.Name)
+			if enum == nil {
+				return nil, fmt.Errorf("descriptor has no definition for enum %q", typ.Name)
+			}
+			item := enum.Items[u]
+			if item == nil {
+				return nil, fmt.Errorf("invalid item name %s for enum %s", u, typ.Name)
+			}
+			return types.Token(item.Value), nil
+		}
+		return nil, jsonTypeError(typ, u)
+	}
+
+	var n []float64
+	switch typ.Name {
+	case "string", "ProtectedString", "Content":
+		s, ok := u.(string)
+		if !ok {
+			return nil, jsonTypeError(typ, u)
+		}
+		switch typ.Name {
+		case "ProtectedString":
+			return types.ProtectedString(s), nil
+		case "Content":
+			return types.Content(s), nil
+		}
+		return types.String(s), nil
+	case "BinaryString", "SharedString":
+		s, ok := u.(string)
+		if !ok {
+			return nil, jsonTypeError(typ, u)
+		}
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, err
+		}
+		if typ.Name == "SharedString" {
+			return types.SharedString(b), nil
+		}
+		return types.BinaryString(b), nil
+	case "Tags":
+		a, ok := u.([]interface{})
+		if !ok {
+			return nil, jsonTypeError(typ, u)
+		}
+		tags := make([]string, len(a))
+		for i, t := range a {
+			if tags[i], ok = t.(string); !ok {
+				return nil, jsonTypeError(typ, u)
+			}
+		}
+		return types.BinaryString(strings.Join(tags, "\x00")), nil
+	case "bool":
+		b, ok := u.(bool)
+		if !ok {
+			return nil, jsonTypeError(typ, u)
+		}
+		return types.Bool(b), nil
+	case "int", "int64", "float", "double", "BrickColor":
+		f, ok := u.(float64)
+		if !ok {
+			return nil, jsonTypeError(typ, u)
+		}
+		switch typ.Name {
+		case "int":
+			return types.Int(f), nil
+		case "int64":
+			return types.Int64(f), nil
+		case "float":
+			return types.Float(f), nil
+		case "BrickColor":
+			return types.BrickColor(f), nil
+		}
+		return types.Double(f), nil
+	case "Vector2", "Vector2int16", "UDim", "NumberRange":
+		if n, err = jsonNumbers(typ, u, 2); err != nil {
+			return nil, err
+		}
+		switch typ.Name {
+		case "Vector2int16":
+			return types.Vector2int16{X: int16(n[0]), Y: int16(n[1])}, nil
+		case "UDim":
+			return types.UDim{Scale: float32(n[0]), Offset: int32(n[1])}, nil
+		case "NumberRange":
+			return types.NumberRange{Min: float32(n[0]), Max: float32(n[1])}, nil
+		}
+		return types.Vector2{X: float32(n[0]), Y: float32(n[1])}, nil
+	case "Vector3", "Vector3int16", "Color3", "Color3uint8":
+		if n, err = jsonNumbers(typ, u, 3); err != nil {
+			return nil, err
+		}
+		switch typ.Name {
+		case "Vector3int16":
+			return types.Vector3int16{X: int16(n[0]), Y: int16(n[1]), Z: int16(n[2])}, nil
+		case "Color3":
+			return types.Color3{R: float32(n[0]), G: float32(n[1]), B: float32(n[2])}, nil
+		case "Color3uint8":
+			return rtypes.Color3uint8{R: float32(n[0]) / 255, G: float32(n[1]) / 255, B: float32(n[2]) / 255}, nil
+		}
+		return types.Vector3{X: float32(n[0]), Y: float32(n[1]), Z: float32(n[2])}, nil
+	case "UDim2", "Rect":
+		a, ok := u.([]interface{})
+		if !ok || len(a) != 2 {
+			return nil, jsonTypeError(typ, u)
+		}
+		x, err := jsonNumbers(typ, a[0], 2)
+		if err != nil {
+			return nil, err
+		}
+		y, err := jsonNumbers(typ, a[1], 2)
+		if err != nil {
+			return nil, err
+		}
+		if typ.Name == "Rect" {
+			return types.Rect{
+				Min: types.Vector2{X: float32(x[0]), Y: float32(x[1])},
+				Max: types.Vector2{X: float32(y[0]), Y: float32(y[1])},
+			}, nil
+		}
+		return types.UDim2{
+			X: types.UDim{Scale: float32(x[0]), Offset: int32(x[1])},
+			Y: types.UDim{Scale: float32(y[0]), Offset: int32(y[1])},
+		}, nil
+	case "CFrame", "CoordinateFrame":
+		m, ok := u.(map[string]interface{})
+		if !ok {
+			return nil, jsonTypeError(typ, u)
+		}
+		p, err := jsonNumbers(typ, m["position"], 3)
+		if err != nil {
+			return nil, err
+		}
+		cf := types.CFrame{Position: types.Vector3{X: float32(p[0]), Y: float32(p[1]), Z: float32(p[2])}}
+		rows, ok := m["orientation"].([]interface{})
+		if !ok || len(rows) != 3 {
+			return nil, jsonTypeError(typ, u)
+		}
+		for i, row := range rows {
+			r, err := jsonNumbers(typ, row, 3)
+			if err != nil {
+				return nil, err
+			}
+			for j := range r {
+				cf.Rotation[i*3+j] = float32(r[j])
+			}
+		}
+		return cf, nil
+	case "Ray":
+		m, ok := u.(map[string]interface{})
+		if !ok {
+			return nil, jsonTypeError(typ, u)
+		}
+		o, err := jsonNumbers(typ, m["origin"], 3)
+		if err != nil {
+			return nil, err
+		}
+		d, err := jsonNumbers(typ, m["direction"], 3)
+		if err != nil {
+			return nil, err
+		}
+		return types.Ray{
+			Origin:    types.Vector3{X: float32(o[0]), Y: float32(o[1]), Z: float32(o[2])},
+			Direction: types.Vector3{X: float32(d[0]), Y: float32(d[1]), Z: float32(d[2])},
+		}, nil
+	case "Faces", "Axes":
+		a, ok := u.([]interface{})
+		if !ok {
+			return nil, jsonTypeError(typ, u)
+		}
+		var faces types.Faces
+		var axes types.Axes
+		for _, name := range a {
+			name, _ := name.(string)
+			switch {
+			case typ.Name == "Faces" && name == "Right":
+				faces.Right = true
+			case typ.Name == "Faces" && name == "Top":
+				faces.Top = true
+			case typ.Name == "Faces" && name == "Back":
+				faces.Back = true
+			case typ.Name == "Faces" && name == "Left":
+				faces.Left = true
+			case typ.Name == "Faces" && name == "Bottom":
+				faces.Bottom = true
+			case typ.Name == "Faces" && name == "Front":
+				faces.Front = true
+			case typ.Name == "Axes" && name == "X":
+				axes.X = true
+			case typ.Name == "Axes" && name == "Y":
+				axes.Y = true
+			case typ.Name == "Axes" && name == "Z":
+				axes.Z = true
+			default:
+				return nil, fmt.Errorf("invalid %s name %q", typ.Name, name)
+			}
+		}
+		if typ.Name == "Axes" {
+			return axes, nil
+		}
+		return faces, nil
+	case "NumberSequence", "ColorSequence":
+		m, ok := u.(map[string]interface{})
+		if !ok {
+			return nil, jsonTypeError(typ, u)
+		}
+		keypoints, ok := m["keypoints"].([]interface{})
+		if !ok {
+			return nil, jsonTypeError(typ, u)
+		}
+		var ns types.NumberSequence
+		var cs types.ColorSequence
+		for _, k := range keypoints {
+			k, ok := k.(map[string]interface{})
+			if !ok {
+				return nil, jsonTypeError(typ, u)
+			}
+			t, _ := k["time"].(float64)
+			e, _ := k["envelope"].(float64)
+			if typ.Name == "NumberSequence" {
+				v, _ := k["value"].(float64)
+				ns = append(ns, types.NumberSequenceKeypoint{Time: float32(t), Value: float32(v), Envelope: float32(e)})
+				continue
+			}
+			c, err := jsonNumbers(typ, k["color"], 3)
+			if err != nil {
+				return nil, err
+			}
+			cs = append(cs, types.ColorSequenceKeypoint{
+				Time:     float32(t),
+				Value:    types.Color3{R: float32(c[0]), G: float32(c[1]), B: float32(c[2])},
+				Envelope: float32(e),
+			})
+		}
+		if typ.Name == "ColorSequence" {
+			return cs, nil
+		}
+		return ns, nil
+	case "PhysicalProperties":
+		switch u := u.(type) {
+		case string:
+			if u != "Default" {
+				return nil, fmt.Errorf("invalid PhysicalProperties %q", u)
+			}
+			return types.PhysicalProperties{}, nil
+		case map[string]interface{}:
+			d, _ := u["density"].(float64)
+			f, _ := u["friction"].(float64)
+			e, _ := u["elasticity"].(float64)
+			fw, _ := u["frictionWeight"].(float64)
+			ew, _ := u["elasticityWeight"].(float64)
+			return types.PhysicalProperties{
+				CustomPhysics:    true,
+				Density:          float32(d),
+				Friction:         float32(f),
+				Elasticity:       float32(e),
+				FrictionWeight:   float32(fw),
+				ElasticityWeight: float32(ew),
+			}, nil
+		}
+		return nil, jsonTypeError(typ, u)
+	case "Font":
+		m, ok := u.(map[string]interface{})
+		if !ok {
+			return nil, jsonTypeError(typ, u)
+		}
+		var font rtypes.Font
+		font.Family, _ = m["family"].(string)
+		font.CachedFaceId, _ = m["cachedFaceId"].(string)
+		switch w := m["weight"].(type) {
+		case nil:
+			font.Weight = 400
+		case float64:
+			font.Weight = int(w)
+		case string:
+			if font.Weight, ok = jsonFontWeights[w]; !ok {
+				return nil, fmt.Errorf("invalid font weight %q", w)
+			}
+		}
+		switch s := m["style"].(type) {
+		case float64:
+			font.Style = int(s)
+		case string:
+			switch s {
+			case "Normal":
+				font.Style = 0
+			case "Italic":
+				font.Style = 1
+			default:
+				return nil, fmt.Errorf("invalid font style %q", s)
+			}
+		}
+		return font, nil
+	case "UniqueId":
+		s, ok := u.(string)
+		if !ok {
+			return nil, jsonTypeError(typ, u)
+		}
+		return parseUniqueId(s)
+	}
+	return nil, fmt.Errorf("cannot decode type %s", typ.Name)
+}
+
+// jsonFontWeights maps the names of font weights to their values.
+var jsonFontWeights = map[string]int{
+	"Thin":       100,
+	"ExtraLight": 200,
+	"Light":      300,
+	"Regular":    400,
+	"Medium":     500,
+	"SemiBold":   600,
+	"Bold":       700,
+	"ExtraBold":  800,
+	"Heavy":      900,
+}
+
+// decodeJSONZero returns a JSON value that decodes into the zero value of typ.
+func decodeJSONZero(typ rbxdump.Type) interface{} {
+	switch typ.Name {
+	case "CFrame", "CoordinateFrame":
+		return map[string]interface{}{
+			"position":    []interface{}{0.0, 0.0, 0.0},
+			"orientation": []interface{}{[]interface{}{1.0, 0.0, 0.0}, []interface{}{0.0, 1.0, 0.0}, []interface{}{0.0, 0.0, 1.0}},
+		}
+	case "Vector3", "Vector3int16", "Color3", "Color3uint8":
+		return []interface{}{0.0, 0.0, 0.0}
+	case "Vector2", "Vector2int16", "UDim", "NumberRange":
+		return []interface{}{0.0, 0.0}
+	case "UDim2", "Rect":
+		return []interface{}{[]interface{}{0.0, 0.0}, []interface{}{0.0, 0.0}}
+	case "bool":
+		return false
+	case "string", "ProtectedString", "Content", "BinaryString", "SharedString":
+		return ""
+	}
+	return 0.0
+}
+
+// jsonNumbers decodes u as an array of n numbers.
+func jsonNumbers(typ rbxdump.Type, u interface{}, n int) ([]float64, error) {
+	a, ok := u.([]interface{})
+	if !ok || len(a) != n {
+		return nil, jsonTypeError(typ, u)
+	}
+	f := make([]float64, n)
+	for i, v := range a {
+		if f[i], ok = v.(float64); !ok {
+			return nil, jsonTypeError(typ, u)
+		}
+	}
+	return f, nil
+}
+
+// jsonTypeError returns an error indicating that JSON value u could not be
+// decoded as typ.
+func jsonTypeError(typ rbxdump.Type, u interface{}) error {
+	name := typ.Name
+	if name == "" {
+		name = typ.Category
+	}
+	var got string
+	switch u.(type) {
+	case nil:
+		got = "null"
+	case bool:
+		got = "boolean"
+	case float64:
+		got = "number"
+	case string:
+		got = "string"
+	case []interface{}:
+		got = "array"
+	case map[string]interface{}:
+		got = "object"
+	}
+	return fmt.Errorf("cannot decode JSON %s as %s", got, name)
+}
+
+// parseUniqueId parses a UniqueId from a string of 32 hexadecimal digits.
+func parseUniqueId(s string) (u rtypes.UniqueId, err error) {
+	if len(s) != 32 {
+		return u, fmt.Errorf("invalid UniqueId %q", s)
+	}
+	var b [16]byte
+	for i := range b {
+		hi, ok1 := fromHex(s[i*2])
+		lo, ok2 := fromHex(s[i*2+1])
+		if !ok1 || !ok2 {
+			return u, fmt.Errorf("invalid UniqueId %q", s)
+		}
+		b[i] = hi<<4 | lo
+	}
+	var random uint64
+	for _, c := range b[0:8] {
+		random = random<<8 | uint64(c)
+	}
+	for _, c := range b[8:12] {
+		u.Time = u.Time<<8 | uint32(c)
+	}
+	for _, c := range b[12:16] {
+		u.Index = u.Index<<8 | uint32(c)
+	}
+	u.Random = types.Int64(random)
+	return u, nil
+}
+
+// fromHex returns the value of hexadecimal digit c.
+func fromHex(c byte) (byte, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0', true
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10, true
+	case 'A' <= c && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// encodeJSONProperty encodes value v of property prop of class into a JSON
+// value. The result is implicit if the descriptor of the property within desc
+// has a type matching v, or if there is no descriptor and v is a primitive type
+// that is decoded implicitly. Otherwise, the result is explicit.
+func encodeJSONProperty(desc *rtypes.Desc, class, prop string, v types.Value) (u interface{}, err error) {
+	var enum string
+	if p := desc.Property(class, prop); p != nil {
+		if jsonTypeMatches(p.ValueType, v) {
+			if p.ValueType.Category == "Enum" {
+				enum = p.ValueType.Name
+			}
+			_, u, err = encodeJSONValue(desc, enum, v)
+			return u, err
+		}
+	} else {
+		switch v.(type) {
+		case types.Bool, types.String, types.Double:
+			_, u, err = encodeJSONValue(desc, enum, v)
+			return u, err
+		}
+	}
+	if prop == "Tags" {
+		if s, ok := v.(types.BinaryString); ok {
+			tags := []interface{}{}
+			if len(s) > 0 {
+				for _, tag := range strings.Split(string(s), "\x00") {
+					tags = append(tags, tag)
+				}
+			}
+			return map[string]interface{}{"Tags": tags}, nil
+		}
+	}
+	name, u, err := encodeJSONValue(desc, enum, v)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{name: u}, nil
+}
+
+// encodeJSONAttribute encodes the value of an attribute into a JSON value.
+// Booleans, strings, and doubles are implicit, while other types are explicit.
+func encodeJSONAttribute(v types.Value) (u interface{}, err error) {
+	name, u, err := encodeJSONValue(nil, "", v)
+	if err != nil {
+		return nil, err
+	}
+	switch v.(type) {
+	case types.Bool, types.String, types.Double:
+		return u, nil
+	}
+	return map[string]interface{}{name: u}, nil
+}
+
+// jsonTypeMatches returns whether value v has type typ.
+func jsonTypeMatches(typ rbxdump.Type, v types.Value) bool {
+	switch typ.Category {
+	case "Class":
+		return false
+	case "Enum":
+		_, ok := v.(types.Token)
+		return ok
+	}
+	if strings.HasSuffix(typ.Name, "?") {
+		o, ok := v.(rtypes.Optional)
+		return ok && jsonTypeMatches(rbxdump.Type{Name: strings.TrimSuffix(typ.Name, "?")}, rtypes.None(o.ValueType()))
+	}
+	t := v.Type()
+	if o, ok := v.(rtypes.Optional); ok {
+		t = o.ValueType()
+	}
+	if typ.Name == "CoordinateFrame" {
+		return t == "CFrame"
+	}
+	return typ.Name == t
+}
+
+// jsonFloat returns f as a JSON number with the fewest digits that represent f
+// exactly.
+func jsonFloat(f float32) json.Number {
+	return json.Number(strconv.FormatFloat(float64(f), 'g', -1, 32))
+}
+
+// jsonVector returns the components of a vector as a JSON array.
+func jsonVector(f ...float32) []interface{} {
+	a := make([]interface{}, len(f))
+	for i, f := range f {
+		a[i] = jsonFloat(f)
+	}
+	return a
+}
+
+// encodeJSONValue encodes v into a JSON value. name is the name of the type
+// of the value, as used by an explicit value. If enum is not empty, then a
+// token is encoded as the name of the item of enum, as described by desc.
+func encodeJSONValue(desc *rtypes.Desc, enum string, v types.Value) (name string, u interface{}, err error) {
+	switch v := v.(type) {
+	case types.Bool:
+		return "Bool", bool(v), nil
+	case types.String:
+		return "String", string(v), nil
+	case types.ProtectedString:
+		return "ProtectedString", string(v), nil
+	case types.Content:
+		return "Content", string(v), nil
+	case types.BinaryString:
+		return "BinaryString", base64.StdEncoding.EncodeToString([]byte(v)), nil
+	case types.SharedString:
+		return "SharedString", base64.StdEncoding.EncodeToString([]byte(v)), nil
+	case types.Int:
+		return "Int32", int64(v), nil
+	case types.Int64:
+		return "Int64", int64(v), nil
+	case types.Float:
+		return "Float32", jsonFloat(float32(v)), nil
+	case types.Double:
+		return "Float64", float64(v), nil
+	case types.BrickColor:
+		return "BrickColor", int64(v), nil
+	case types.Token:
+		if e := desc.Enum(enum); e != nil {
+			for _, item := range e.Items {
+				if item.Value == int(v) {
+					return "Enum", item.Name, nil
+				}
+			}
+		}
+		return "Enum", int64(v), nil
+	case types.Vector2:
+		return "Vector2", jsonVector(v.X, v.Y), nil
+	case types.Vector2int16:
+		return "Vector2int16", []interface{}{v.X, v.Y}, nil
+	case types.Vector3:
+		return "Vector3", jsonVector(v.X, v.Y, v.Z), nil
+	case types.Vector3int16:
+		return "Vector3int16", []interface{}{v.X, v.Y, v.Z}, nil
+	case types.Color3:
+		return "Color3", jsonVector(v.R, v.G, v.B), nil
+	case rtypes.Color3uint8:
+		return "Color3uint8", []interface{}{
+			int(v.R*255 + 0.5),
+			int(v.G*255 + 0.5),
+			int(v.B*255 + 0.5),
+		}, nil
+	case types.UDim:
+		return "UDim", []interface{}{jsonFloat(v.Scale), v.Offset}, nil
+	case types.UDim2:
+		return "UDim2", []interface{}{
+			[]interface{}{jsonFloat(v.X.Scale), v.X.Offset},
+			[]interface{}{jsonFloat(v.Y.Scale), v.Y.Offset},
+		}, nil
+	case types.NumberRange:
+		return "NumberRange", jsonVector(v.Min, v.Max), nil
+	case types.Rect:
+		return "Rect", []interface{}{
+			jsonVector(v.Min.X, v.Min.Y),
+			jsonVector(v.Max.X, v.Max.Y),
+		}, nil
+	case types.CFrame:
+		r := v.Rotation
+		return "CFrame", map[string]interface{}{
+			"position": jsonVector(v.Position.X, v.Position.Y, v.Position.Z),
+			"orientation": []interface{}{
+				jsonVector(r[0], r[1], r[2]),
+				jsonVector(r[3], r[4], r[5]),
+				jsonVector(r[6], r[7], r[8]),
+			},
+		}, nil
+	case types.Ray:
+		return "Ray", map[string]interface{}{
+			"origin":    jsonVector(v.Origin.X, v.Origin.Y, v.Origin.Z),
+			"direction": jsonVector(v.Direction.X, v.Direction.Y, v.Direction.Z),
+		}, nil
+	case types.Faces:
+		a := []interface{}{}
+		for _, f := range []struct {
+			ok   bool
+			name string
+		}{{v.Right, "Right"}, {v.Top, "Top"}, {v.Back, "Back"}, {v.Left, "Left"}, {v.Bottom, "Bottom"}, {v.Front, "Front"}} {
+			if f.ok {
+				a = append(a, f.name)
+			}
+		}
+		return "Faces", a, nil
+	case types.Axes:
+		a := []interface{}{}
+		for _, f := range []struct {
+			ok   bool
+			name string
+		}{{v.X, "X"}, {v.Y, "Y"}, {v.Z, "Z"}} {
+			if f.ok {
+				a = append(a, f.name)
+			}
+		}
+		return "Axes", a, nil
+	case types.NumberSequence:
+		keypoints := make([]interface{}, len(v))
+		for i, k := range v {
+			keypoints[i] = map[string]interface{}{
+				"time":     jsonFloat(k.Time),
+				"value":    jsonFloat(k.Value),
+				"envelope": jsonFloat(k.Envelope),
+			}
+		}
+		return "NumberSequence", map[string]interface{}{"keypoints": keypoints}, nil
+	case types.ColorSequence:
+		keypoints := make([]interface{}, len(v))
+		for i, k := range v {
+			keypoints[i] = map[string]interface{}{
+				"time":     jsonFloat(k.Time),
+				"color":    jsonVector(k.Value.R, k.Value.G, k.Value.B),
+				"envelope": jsonFloat(k.Envelope),
+			}
+		}
+		return "ColorSequence", map[string]interface{}{"keypoints": keypoints}, nil
+	case types.PhysicalProperties:
+		if !v.CustomPhysics {
+			return "PhysicalProperties", "Default", nil
+		}
+		return "PhysicalProperties", map[string]interface{}{
+			"density":          jsonFloat(v.Density),
+			"friction":         jsonFloat(v.Friction),
+			"elasticity":       jsonFloat(v.Elasticity),
+			"frictionWeight":   jsonFloat(v.FrictionWeight),
+			"elasticityWeight": jsonFloat(v.ElasticityWeight),
+		}, nil
+	case rtypes.Font:
+		font := map[string]interface{}{
+			"family": v.Family,
+			"weight": v.Weight,
+			"style":  v.Style,
+		}
+		for name, weight := range jsonFontWeights {
+			if weight == v.Weight {
+				font["weight"] = name
+				break
+			}
+		}
+		switch v.Style {
+		case 0:
+			font["style"] = "Normal"
+		case 1:
+			font["style"] = "Italic"
+		}
+		if v.CachedFaceId != "" {
+			font["cachedFaceId"] = v.CachedFaceId
+		}
+		return "Font", font, nil
+	case rtypes.UniqueId:
+		return "UniqueId", v.String(), nil
+	case rtypes.Optional:
+		if v.ValueType() != "CFrame" {
+			break
+		}
+		if v.Value() == nil {
+			return "OptionalCFrame", nil, nil
+		}
+		_, u, err := encodeJSONValue(desc, enum, v.Value())
+		return "OptionalCFrame", u, err
+	}
+	return "", nil, cannotEncode(v)
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
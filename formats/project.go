@@ -0,0 +1,168 @@
+package formats
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/anaminus/rbxmk"
+	"github.com/anaminus/rbxmk/dump"
+	"github.com/anaminus/rbxmk/reflect"
+	"github.com/anaminus/rbxmk/rtypes"
+	"github.com/robloxapi/types"
+)
+
+const F_Project = "project.json"
+
+func init() { register(Project) }
+func Project() rbxmk.Format {
+	return rbxmk.Format{
+		Name:       F_Project,
+		MediaTypes: []string{"application/json", "text/plain"},
+		CanDecode: func(g rtypes.Global, f rbxmk.FormatOptions, typeName string) bool {
+			return typeName == rtypes.T_Instance
+		},
+		Decode: func(g rtypes.Global, f rbxmk.FormatOptions, r io.Reader) (v types.Value, err error) {
+			var project struct {
+				Name string                 `json:"name"`
+				Tree map[string]interface{} `json:"tree"`
+			}
+			if err := json.NewDecoder(r).Decode(&project); err != nil {
+				return nil, err
+			}
+			if project.Tree == nil {
+				return nil, fmt.Errorf("project has no tree")
+			}
+			d := projectDecoder{desc: g.Desc}
+			d.resolver, _ = f.(rbxmk.PathResolver)
+			return d.node("tree", project.Name, project.Tree)
+		},
+		Dump: func() dump.Format {
+			return dump.Format{
+				Summary:     "Formats/project.json:Summary",
+				Description: "Formats/project.json:Description",
+			}
+		},
+		Types: []func() rbxmk.Reflector{
+			reflect.Instance,
+		},
+	}
+}
+
+// projectDecoder decodes the tree of a project file.
+type projectDecoder struct {
+	desc     *rtypes.Desc
+	resolver rbxmk.PathResolver
+}
+
+// node decodes a node of a project tree into an instance with the given name.
+// full is the full name of the node, used for errors.
+func (d projectDecoder) node(full, name string, node map[string]interface{}) (inst *rtypes.Instance, err error) {
+	var className string
+	if v, ok := node["$className"]; ok {
+		if className, ok = v.(string); !ok {
+			return nil, fmt.Errorf("%s: $className must be a string", full)
+		}
+	}
+	// $ignoreUnknownInstances only affects syncing into an existing tree, so it
+	// is validated, but otherwise has no effect.
+	if v, ok := node["$ignoreUnknownInstances"]; ok {
+		if _, ok := v.(bool); !ok {
+			return nil, fmt.Errorf("%s: $ignoreUnknownInstances must be a boolean", full)
+		}
+	}
+
+	if v, ok := node["$path"]; ok {
+		p, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: $path must be a string", full)
+		}
+		if inst, err = d.path(p); err != nil {
+			return nil, fmt.Errorf("%s: $path: %w", full, err)
+		}
+		if className != "" && className != inst.ClassName {
+			if inst.ClassName != "Folder" {
+				return nil, fmt.Errorf("%s: $className %s conflicts with class %s of $path", full, className, inst.ClassName)
+			}
+			inst.ClassName = className
+		}
+	} else {
+		if className == "" {
+			if d.desc == nil || d.desc.Class(name) == nil || !d.desc.Class(name).GetTag("Service") {
+				return nil, fmt.Errorf("%s: $className or $path required", full)
+			}
+			className = name
+		}
+		if className == "DataModel" {
+			inst = rtypes.NewDataModel()
+		} else {
+			inst = rtypes.NewInstance(className, nil)
+		}
+	}
+	if d.desc != nil {
+		if class := d.desc.Class(inst.ClassName); class != nil {
+			inst.IsService = class.GetTag("Service")
+		}
+	}
+	if name != "" {
+		inst.SetName(name)
+	}
+
+	if v, ok := node["$properties"]; ok {
+		props, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: $properties must be an object", full)
+		}
+		for _, prop := range sortedKeys(props) {
+			value, err := decodeJSONProperty(d.desc, inst.ClassName, prop, props[prop])
+			if err != nil {
+				return nil, fmt.Errorf("%s: property %s: %w", full, prop, err)
+			}
+			inst.Set(prop, value)
+		}
+	}
+
+	for _, key := range sortedKeys(node) {
+		if strings.HasPrefix(key, "$") {
+			continue
+		}
+		child, ok := node[key].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s.%s: node must be an object", full, key)
+		}
+		c, err := d.node(full+"."+key, key, child)
+		if err != nil {
+			return nil, err
+		}
+		c.SetParent(inst)
+	}
+	return inst, nil
+}
+
+// path resolves the instance located at p. If p is a model file, then the model
+// must contain exactly one instance, which is returned.
+func (d projectDecoder) path(p string) (inst *rtypes.Instance, err error) {
+	if d.resolver == nil {
+		return nil, fmt.Errorf("cannot resolve %q", p)
+	}
+	v, err := d.resolver.ResolvePath(p)
+	if err != nil {
+		return nil, err
+	}
+	inst, ok := v.(*rtypes.Instance)
+	if !ok || inst == nil {
+		return nil, fmt.Errorf("%q does not decode into an Instance", p)
+	}
+	switch strings.ToLower(path.Ext(p)) {
+	case ".rbxm", ".rbxmx":
+		children := inst.Children()
+		if len(children) != 1 {
+			return nil, fmt.Errorf("model %q must contain exactly one instance", p)
+		}
+		inst = children[0]
+		inst.SetParent(nil)
+	}
+	return inst, nil
+}
@@ -70,6 +70,9 @@ func RBXL() rbxmk.Format {
 		Options: map[string][]string{
 			"Desc":     {rtypes.T_Desc, rtypes.T_Bool},
 			"DescMode": {rtypes.T_String},
+			"Lazy":     {rtypes.T_Bool},
+			"Include":  {rtypes.T_Array},
+			"Classes":  {rtypes.T_Array},
 		},
 		CanDecode: func(g rtypes.Global, f rbxmk.FormatOptions, typeName string) bool {
 			return typeName == rtypes.T_Instance
@@ -80,14 +83,29 @@ func RBXL() rbxmk.Format {
 			if err != nil {
 				return nil, err
 			}
+			lazy, err := lazyOf(f, "Lazy", mode)
+			if err != nil {
+				return nil, err
+			}
+			include, err := pathsOf(f, "Include")
+			if err != nil {
+				return nil, err
+			}
+			classes, err := stringsOf(f, "Classes")
+			if err != nil {
+				return nil, err
+			}
 			d := rbxDecoder{
 				method: func(r io.Reader) (root *rbxfile.Root, err error) {
 					root, _, err = rbxl.Decoder{Mode: rbxl.Place}.Decode(r)
 					return root, err
 				},
-				r:    r,
-				desc: desc,
-				mode: mode,
+				r:       r,
+				desc:    desc,
+				mode:    mode,
+				lazy:    lazy,
+				include: include,
+				classes: classes,
 			}
 			return d.rbx()
 		},
@@ -121,6 +139,21 @@ func RBXL() rbxmk.Format {
 						Default:     `"NonStrict"`,
 						Description: "Formats/options/rbx:DescMode",
 					},
+					"Lazy": dump.FormatOption{
+						Type:        dt.Prim(rtypes.T_Bool),
+						Default:     "false",
+						Description: "Formats/options/rbx:Lazy",
+					},
+					"Include": dump.FormatOption{
+						Type:        dt.Optional(dt.Array(dt.Prim(rtypes.T_String))),
+						Default:     "nil",
+						Description: "Formats/options/rbx:Include",
+					},
+					"Classes": dump.FormatOption{
+						Type:        dt.Optional(dt.Array(dt.Prim(rtypes.T_String))),
+						Default:     "nil",
+						Description: "Formats/options/rbx:Classes",
+					},
 				},
 				Summary:     "Formats/rbxl:Summary",
 				Description: "Formats/rbxl:Description",
@@ -141,6 +174,9 @@ func RBXM() rbxmk.Format {
 		Options: map[string][]string{
 			"Desc":     {rtypes.T_Desc, rtypes.T_Bool},
 			"DescMode": {rtypes.T_String},
+			"Lazy":     {rtypes.T_Bool},
+			"Include":  {rtypes.T_Array},
+			"Classes":  {rtypes.T_Array},
 		},
 		CanDecode: func(g rtypes.Global, f rbxmk.FormatOptions, typeName string) bool {
 			return typeName == rtypes.T_Instance
@@ -151,14 +187,29 @@ func RBXM() rbxmk.Format {
 			if err != nil {
 				return nil, err
 			}
+			lazy, err := lazyOf(f, "Lazy", mode)
+			if err != nil {
+				return nil, err
+			}
+			include, err := pathsOf(f, "Include")
+			if err != nil {
+				return nil, err
+			}
+			classes, err := stringsOf(f, "Classes")
+			if err != nil {
+				return nil, err
+			}
 			d := rbxDecoder{
 				method: func(r io.Reader) (root *rbxfile.Root, err error) {
 					root, _, err = rbxl.Decoder{Mode: rbxl.Model}.Decode(r)
 					return root, err
 				},
-				r:    r,
-				desc: desc,
-				mode: mode,
+				r:       r,
+				desc:    desc,
+				mode:    mode,
+				lazy:    lazy,
+				include: include,
+				classes: classes,
 			}
 			return d.rbx()
 		},
@@ -192,6 +243,21 @@ func RBXM() rbxmk.Format {
 						Default:     `"NonStrict"`,
 						Description: "Formats/options/rbx:DescMode",
 					},
+					"Lazy": dump.FormatOption{
+						Type:        dt.Prim(rtypes.T_Bool),
+						Default:     "false",
+						Description: "Formats/options/rbx:Lazy",
+					},
+					"Include": dump.FormatOption{
+						Type:        dt.Optional(dt.Array(dt.Prim(rtypes.T_String))),
+						Default:     "nil",
+						Description: "Formats/options/rbx:Include",
+					},
+					"Classes": dump.FormatOption{
+						Type:        dt.Optional(dt.Array(dt.Prim(rtypes.T_String))),
+						Default:     "nil",
+						Description: "Formats/options/rbx:Classes",
+					},
 				},
 				Summary:     "Formats/rbxm:Summary",
 				Description: "Formats/rbxm:Description",
@@ -212,6 +278,9 @@ func RBXLX() rbxmk.Format {
 		Options: map[string][]string{
 			"Desc":     {rtypes.T_Desc, rtypes.T_Bool},
 			"DescMode": {rtypes.T_String},
+			"Lazy":     {rtypes.T_Bool},
+			"Include":  {rtypes.T_Array},
+			"Classes":  {rtypes.T_Array},
 		},
 		CanDecode: func(g rtypes.Global, f rbxmk.FormatOptions, typeName string) bool {
 			return typeName == rtypes.T_Instance
@@ -222,14 +291,29 @@ func RBXLX() rbxmk.Format {
 			if err != nil {
 				return nil, err
 			}
+			lazy, err := lazyOf(f, "Lazy", mode)
+			if err != nil {
+				return nil, err
+			}
+			include, err := pathsOf(f, "Include")
+			if err != nil {
+				return nil, err
+			}
+			classes, err := stringsOf(f, "Classes")
+			if err != nil {
+				return nil, err
+			}
 			d := rbxDecoder{
 				method: func(r io.Reader) (root *rbxfile.Root, err error) {
 					root, _, err = rbxlx.Decoder{}.Decode(r)
 					return root, err
 				},
-				r:    r,
-				desc: desc,
-				mode: mode,
+				r:       r,
+				desc:    desc,
+				mode:    mode,
+				lazy:    lazy,
+				include: include,
+				classes: classes,
 			}
 			return d.rbx()
 		},
@@ -263,6 +347,21 @@ func RBXLX() rbxmk.Format {
 						Default:     `"NonStrict"`,
 						Description: "Formats/options/rbx:DescMode",
 					},
+					"Lazy": dump.FormatOption{
+						Type:        dt.Prim(rtypes.T_Bool),
+						Default:     "false",
+						Description: "Formats/options/rbx:Lazy",
+					},
+					"Include": dump.FormatOption{
+						Type:        dt.Optional(dt.Array(dt.Prim(rtypes.T_String))),
+						Default:     "nil",
+						Description: "Formats/options/rbx:Include",
+					},
+					"Classes": dump.FormatOption{
+						Type:        dt.Optional(dt.Array(dt.Prim(rtypes.T_String))),
+						Default:     "nil",
+						Description: "Formats/options/rbx:Classes",
+					},
 				},
 				Summary:     "Formats/rbxlx:Summary",
 				Description: "Formats/rbxlx:Description",
@@ -283,6 +382,9 @@ func RBXMX() rbxmk.Format {
 		Options: map[string][]string{
 			"Desc":     {rtypes.T_Desc, rtypes.T_Bool},
 			"DescMode": {rtypes.T_String},
+			"Lazy":     {rtypes.T_Bool},
+			"Include":  {rtypes.T_Array},
+			"Classes":  {rtypes.T_Array},
 		},
 		CanDecode: func(g rtypes.Global, f rbxmk.FormatOptions, typeName string) bool {
 			return typeName == rtypes.T_Instance
@@ -293,14 +395,29 @@ func RBXMX() rbxmk.Format {
 			if err != nil {
 				return nil, err
 			}
+			lazy, err := lazyOf(f, "Lazy", mode)
+			if err != nil {
+				return nil, err
+			}
+			include, err := pathsOf(f, "Include")
+			if err != nil {
+				return nil, err
+			}
+			classes, err := stringsOf(f, "Classes")
+			if err != nil {
+				return nil, err
+			}
 			d := rbxDecoder{
 				method: func(r io.Reader) (root *rbxfile.Root, err error) {
 					root, _, err = rbxlx.Decoder{}.Decode(r)
 					return root, err
 				},
-				r:    r,
-				desc: desc,
-				mode: mode,
+				r:       r,
+				desc:    desc,
+				mode:    mode,
+				lazy:    lazy,
+				include: include,
+				classes: classes,
 			}
 			return d.rbx()
 		},
@@ -334,6 +451,21 @@ func RBXMX() rbxmk.Format {
 						Default:     `"NonStrict"`,
 						Description: "Formats/options/rbx:DescMode",
 					},
+					"Lazy": dump.FormatOption{
+						Type:        dt.Prim(rtypes.T_Bool),
+						Default:     "false",
+						Description: "Formats/options/rbx:Lazy",
+					},
+					"Include": dump.FormatOption{
+						Type:        dt.Optional(dt.Array(dt.Prim(rtypes.T_String))),
+						Default:     "nil",
+						Description: "Formats/options/rbx:Include",
+					},
+					"Classes": dump.FormatOption{
+						Type:        dt.Optional(dt.Array(dt.Prim(rtypes.T_String))),
+						Default:     "nil",
+						Description: "Formats/options/rbx:Classes",
+					},
 				},
 				Summary:     "Formats/rbxmx:Summary",
 				Description: "Formats/rbxmx:Description",
@@ -373,6 +505,29 @@ func descModeOf(f rbxmk.FormatOptions, field string) (mode descMode, err error)
 	return mode, nil
 }
 
+// lazyOf gets whether properties are decoded lazily from a given field. Lazy
+// decoding cannot report errors, so it cannot be combined with the Strict
+// descMode.
+func lazyOf(f rbxmk.FormatOptions, field string, mode descMode) (lazy bool, err error) {
+	if lazy, _ = boolOf(f, field); lazy && mode == modeStrict {
+		return false, fmt.Errorf("option %s: cannot be combined with Strict DescMode", field)
+	}
+	return lazy, nil
+}
+
+// pathsOf gets a list of paths from a given field. Each path is a string of
+// instance names separated by "." characters.
+func pathsOf(f rbxmk.FormatOptions, field string) (paths [][]string, err error) {
+	list, err := stringsOf(f, field)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range list {
+		paths = append(paths, strings.Split(path, "."))
+	}
+	return paths, nil
+}
+
 // descOf gets a descriptor from a given field. A Desc field returns the Desc. A
 // false bool returns nil. Otherwise, if v is an Instance, returns the
 // descriptor according to g.Desc.Of(v). Otherwise, returns g.Desc.
@@ -415,8 +570,17 @@ type rbxDecoder struct {
 	r      io.Reader
 	desc   *rtypes.Desc
 	mode   descMode
-	refs   decinst
-	prefs  []decprop
+	lazy   bool
+	// Paths and classes of instances to include. If both are empty, then all
+	// instances are included.
+	include [][]string
+	classes []string
+	// Instances that pass the filter, or nil if there is no filter.
+	keep  map[*rbxfile.Instance]bool
+	refs  decinst
+	prefs []decprop
+	// State shared by the property loaders of a lazy decode.
+	loaders *rbxLoaderState
 }
 
 // rbx decodes d.r according to d.method, then converts the result.
@@ -441,7 +605,14 @@ func (d *rbxDecoder) dataModel(r *rbxfile.Root) (t *rtypes.Instance, err error)
 	}
 	d.refs = decinst{}
 	d.prefs = []decprop{}
+	if d.lazy {
+		d.loaders = &rbxLoaderState{desc: d.desc, mode: d.mode}
+	}
+	d.filter(r)
 	for _, rc := range r.Instances {
+		if d.keep != nil && !d.keep[rc] {
+			continue
+		}
 		tc, err := d.instance(rc)
 		if err != nil {
 			switch d.mode {
@@ -459,9 +630,106 @@ func (d *rbxDecoder) dataModel(r *rbxfile.Root) (t *rtypes.Instance, err error)
 	for _, pref := range d.prefs {
 		pref.Instance.Set(pref.Property, d.refs[pref.Value])
 	}
+	if d.lazy {
+		d.release(r)
+	}
 	return t, nil
 }
 
+// release drops the parts of r that are not needed by property loaders, so
+// that they can be freed after a lazy decode. Loaders own the raw properties of
+// their instances, and only the instances that are the targets of references
+// remain mapped.
+func (d *rbxDecoder) release(r *rbxfile.Root) {
+	refs := decinst{}
+	for rc, t := range d.refs {
+		if d.loaders.targets[rc] {
+			refs[rc] = t
+		}
+		rc.Children = nil
+	}
+	d.loaders.refs = refs
+	d.loaders.targets = nil
+	r.Instances = nil
+}
+
+// filter determines which instances of r are decoded. An instance is kept if
+// it is within one of the included paths, and its class is one of the included
+// classes. The ancestors of a kept instance are also kept, so that the
+// structure of the tree is retained.
+func (d *rbxDecoder) filter(r *rbxfile.Root) {
+	d.keep = nil
+	if len(d.include) == 0 && len(d.classes) == 0 {
+		return
+	}
+	d.keep = map[*rbxfile.Instance]bool{}
+	for _, rc := range r.Instances {
+		d.filterInstance(rc, nil, len(d.include) == 0)
+	}
+}
+
+// filterInstance marks whether r is kept. path is the path of the parent of r,
+// and included is whether the parent is within an included path. Returns
+// whether r is kept.
+func (d *rbxDecoder) filterInstance(r *rbxfile.Instance, path []string, included bool) bool {
+	name, _ := r.Properties["Name"].(rbxfile.ValueString)
+	path = append(path[:len(path):len(path)], string(name))
+	if !included {
+		// Whether r is the ancestor of an included path.
+		ancestor := false
+		for _, p := range d.include {
+			if !pathHasPrefix(p, path) {
+				continue
+			}
+			if len(p) == len(path) {
+				included = true
+				break
+			}
+			ancestor = true
+		}
+		if !included && !ancestor {
+			return false
+		}
+	}
+	keep := included && d.classMatches(r.ClassName)
+	for _, rc := range r.Children {
+		if d.filterInstance(rc, path, included) {
+			keep = true
+		}
+	}
+	if keep {
+		d.keep[r] = true
+	}
+	return keep
+}
+
+// pathHasPrefix returns whether path begins with prefix.
+func pathHasPrefix(path, prefix []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i, name := range prefix {
+		if path[i] != name {
+			return false
+		}
+	}
+	return true
+}
+
+// classMatches returns whether className is one of the included classes, or
+// inherits from one according to the descriptor.
+func (d *rbxDecoder) classMatches(className string) bool {
+	if len(d.classes) == 0 {
+		return true
+	}
+	for _, class := range d.classes {
+		if className == class || d.desc != nil && d.desc.ClassIsA(className, class) {
+			return true
+		}
+	}
+	return false
+}
+
 // instance converts an instance.
 func (d *rbxDecoder) instance(r *rbxfile.Instance) (t *rtypes.Instance, err error) {
 	if t, ok := d.refs[r]; ok {
@@ -487,22 +755,49 @@ func (d *rbxDecoder) instance(r *rbxfile.Instance) (t *rtypes.Instance, err erro
 	}
 	t.Reference = r.Reference
 	d.refs[r] = t
-	for prop, value := range r.Properties {
-		v, err := d.value(t, prop, value)
-		if err != nil {
-			switch d.mode {
-			case modeNonStrict:
-				continue
-			case modeStrict:
-				return nil, fmt.Errorf("property %s.%s: %w", t.ClassName, prop, err)
-			case modePreserve:
+	if d.lazy {
+		// Decode Name immediately so that the tree can be traversed without
+		// loading properties.
+		if value, ok := r.Properties["Name"]; ok {
+			if v, err := d.value(t, "Name", value); err == nil && v != nil {
+				t.Set("Name", v)
 			}
 		}
-		if v != nil {
-			t.Set(prop, v)
+		for _, value := range r.Properties {
+			if o, ok := value.(rbxfile.ValueOptional); ok {
+				value = o.Value()
+			}
+			if ref, ok := value.(rbxfile.ValueReference); ok && ref.Instance != nil {
+				if d.loaders.targets == nil {
+					d.loaders.targets = map[*rbxfile.Instance]bool{}
+				}
+				d.loaders.targets[ref.Instance] = true
+			}
+		}
+		t.SetPropertyLoader(&rbxPropertyLoader{state: d.loaders, props: r.Properties})
+		// The loader owns the raw properties from here on.
+		r.Properties = nil
+	} else {
+		for prop, value := range r.Properties {
+			v, err := d.value(t, prop, value)
+			if err != nil {
+				switch d.mode {
+				case modeNonStrict:
+					continue
+				case modeStrict:
+					return nil, fmt.Errorf("property %s.%s: %w", t.ClassName, prop, err)
+				case modePreserve:
+				}
+			}
+			if v != nil {
+				t.Set(prop, v)
+			}
 		}
 	}
 	for _, rc := range r.Children {
+		if d.keep != nil && !d.keep[rc] {
+			continue
+		}
 		tc, err := d.instance(rc)
 		if err != nil {
 			switch d.mode {
@@ -520,6 +815,50 @@ func (d *rbxDecoder) instance(r *rbxfile.Instance) (t *rtypes.Instance, err erro
 	return t, nil
 }
 
+// rbxLoaderState is shared by the property loaders of a lazy decode.
+type rbxLoaderState struct {
+	desc *rtypes.Desc
+	mode descMode
+	// Maps the targets of reference properties to decoded instances.
+	refs decinst
+	// Targets of reference properties, collected while decoding.
+	targets map[*rbxfile.Instance]bool
+}
+
+// rbxPropertyLoader converts the properties of an instance the first time they
+// are accessed. Because errors cannot be returned, properties that fail to
+// convert are dropped. The raw properties are released once converted.
+type rbxPropertyLoader struct {
+	state *rbxLoaderState
+	props map[string]rbxfile.Value
+}
+
+// LoadProperties implements rtypes.PropertyLoader.
+func (l *rbxPropertyLoader) LoadProperties(t *rtypes.Instance) map[string]types.PropValue {
+	d := rbxDecoder{
+		desc: l.state.desc,
+		mode: l.state.mode,
+		refs: l.state.refs,
+	}
+	props := make(map[string]types.PropValue, len(l.props))
+	for prop, value := range l.props {
+		v, err := d.value(t, prop, value)
+		if err != nil && d.mode != modePreserve {
+			continue
+		}
+		if v != nil {
+			props[prop] = v
+		}
+	}
+	for _, pref := range d.prefs {
+		if ref := d.refs[pref.Value]; ref != nil {
+			props[pref.Property] = ref
+		}
+	}
+	l.props = nil
+	return props
+}
+
 // value converts a property value.
 func (d *rbxDecoder) value(inst *rtypes.Instance, prop string, r rbxfile.Value) (t types.PropValue, err error) {
 	if d.desc != nil {
@@ -0,0 +1,96 @@
+package formats
+
+import (
+	"io"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/anaminus/rbxmk/rtypes"
+	"github.com/robloxapi/rbxfile"
+	"github.com/robloxapi/types"
+)
+
+// retainedHeap returns the number of bytes allocated on the heap after
+// collecting garbage.
+func retainedHeap() int64 {
+	runtime.GC()
+	runtime.GC()
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return int64(m.HeapAlloc)
+}
+
+// newLazyTestRoot returns a tree containing n parts, each with a Data property
+// of size bytes, and an ObjectValue that refers to the first part.
+func newLazyTestRoot(n, size int) *rbxfile.Root {
+	folder := rbxfile.NewInstance("Folder")
+	folder.Properties["Name"] = rbxfile.ValueString("Parts")
+	for i := 0; i < n; i++ {
+		part := rbxfile.NewInstance("Part")
+		part.Properties["Name"] = rbxfile.ValueString("Part")
+		part.Properties["Data"] = make(rbxfile.ValueString, size)
+		folder.Children = append(folder.Children, part)
+	}
+	value := rbxfile.NewInstance("ObjectValue")
+	value.Properties["Name"] = rbxfile.ValueString("Value")
+	value.Properties["Value"] = rbxfile.ValueReference{Instance: folder.Children[0]}
+	return &rbxfile.Root{Instances: []*rbxfile.Instance{folder, value}}
+}
+
+// decodeLazy decodes root with lazily loaded properties.
+func decodeLazy(t *testing.T, root *rbxfile.Root) *rtypes.Instance {
+	d := rbxDecoder{
+		method: func(io.Reader) (*rbxfile.Root, error) { return root, nil },
+		mode:   modeNonStrict,
+		lazy:   true,
+	}
+	v, err := d.rbx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v.(*rtypes.Instance)
+}
+
+func TestRBXLazyRelease(t *testing.T) {
+	const n, size = 256, 64 << 10
+
+	base := retainedHeap()
+	tree := decodeLazy(t, newLazyTestRoot(n, size))
+	parts := tree.Children()[0].Children()
+	// Load and discard the properties of every part except the last. The raw
+	// data of loaded parts must not be retained by the unloaded part.
+	for _, part := range parts[:len(parts)-1] {
+		if v, ok := part.Get("Data").(types.String); !ok || len(v) != size {
+			t.Fatalf("unexpected Data property")
+		}
+		part.Set("Data", nil)
+	}
+	if retained := retainedHeap() - base; retained > n*size/4 {
+		t.Errorf("retained %d bytes after loading; raw data is %d bytes", retained, n*size)
+	}
+
+	if v, ok := parts[len(parts)-1].Get("Data").(types.String); !ok || len(v) != size {
+		t.Errorf("unexpected Data property of unloaded part")
+	}
+	if v := tree.Children()[1].Get("Value"); v != parts[0] {
+		t.Errorf("reference not resolved: %v", v)
+	}
+}
+
+func TestRBXLazyConcurrentRead(t *testing.T) {
+	tree := decodeLazy(t, newLazyTestRoot(4, 16))
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, part := range tree.Children()[0].Children() {
+				if part.Name() != "Part" || part.Get("Data") == nil {
+					t.Error("unexpected properties")
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
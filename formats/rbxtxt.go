@@ -0,0 +1,1028 @@
+package formats
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/anaminus/rbxmk"
+	"github.com/anaminus/rbxmk/dump"
+	"github.com/anaminus/rbxmk/dump/dt"
+	"github.com/anaminus/rbxmk/rtypes"
+	"github.com/robloxapi/rbxfile"
+	"github.com/robloxapi/types"
+)
+
+const F_RBXMText = "rbxm.txt"
+
+func init() { register(RBXMText) }
+func RBXMText() rbxmk.Format {
+	return rbxmk.Format{
+		Name:        F_RBXMText,
+		EncodeTypes: []string{rtypes.T_Instance, rtypes.T_Objects},
+		MediaTypes:  []string{"text/plain"},
+		Options: map[string][]string{
+			"Desc":     {rtypes.T_Desc, rtypes.T_Bool},
+			"DescMode": {rtypes.T_String},
+			"Lazy":     {rtypes.T_Bool},
+			"Include":  {rtypes.T_Array},
+			"Classes":  {rtypes.T_Array},
+		},
+		CanDecode: func(g rtypes.Global, f rbxmk.FormatOptions, typeName string) bool {
+			return typeName == rtypes.T_Instance
+		},
+		Decode: func(g rtypes.Global, f rbxmk.FormatOptions, r io.Reader) (v types.Value, err error) {
+			desc := descOf(f, "Desc", g, nil)
+			mode, err := descModeOf(f, "DescMode")
+			if err != nil {
+				return nil, err
+			}
+			lazy, err := lazyOf(f, "Lazy", mode)
+			if err != nil {
+				return nil, err
+			}
+			include, err := pathsOf(f, "Include")
+			if err != nil {
+				return nil, err
+			}
+			classes, err := stringsOf(f, "Classes")
+			if err != nil {
+				return nil, err
+			}
+			d := rbxDecoder{
+				method:  decodeRBXText,
+				r:       r,
+				desc:    desc,
+				mode:    mode,
+				lazy:    lazy,
+				include: include,
+				classes: classes,
+			}
+			return d.rbx()
+		},
+		Encode: func(g rtypes.Global, f rbxmk.FormatOptions, w io.Writer, v types.Value) error {
+			desc := descOf(f, "Desc", g, v)
+			mode, err := descModeOf(f, "DescMode")
+			if err != nil {
+				return err
+			}
+			e := rbxEncoder{
+				method: encodeRBXText,
+				w:      w,
+				desc:   desc,
+				mode:   mode,
+			}
+			return e.rbx(v)
+		},
+		Dump: func() dump.Format {
+			return dump.Format{
+				Options: dump.FormatOptions{
+					"Desc": dump.FormatOption{
+						Type:        dt.Or(dt.Prim(rtypes.T_Desc), dt.Prim(rtypes.T_Bool), dt.Prim(rtypes.T_Nil)),
+						Default:     "nil",
+						Description: "Formats/options/rbx:Desc",
+					},
+					"DescMode": dump.FormatOption{
+						Type:        dt.Prim(rtypes.T_String),
+						Default:     `"NonStrict"`,
+						Description: "Formats/options/rbx:DescMode",
+					},
+					"Lazy": dump.FormatOption{
+						Type:        dt.Prim(rtypes.T_Bool),
+						Default:     "false",
+						Description: "Formats/options/rbx:Lazy",
+					},
+					"Include": dump.FormatOption{
+						Type:        dt.Optional(dt.Array(dt.Prim(rtypes.T_String))),
+						Default:     "nil",
+						Description: "Formats/options/rbx:Include",
+					},
+					"Classes": dump.FormatOption{
+						Type:        dt.Optional(dt.Array(dt.Prim(rtypes.T_String))),
+						Default:     "nil",
+						Description: "Formats/options/rbx:Classes",
+					},
+				},
+				Summary:     "Formats/rbxm.txt:Summary",
+				Description: "Formats/rbxm.txt:Description",
+			}
+		},
+		Types: rbxTypes(),
+	}
+}
+
+// The text format is line-oriented. Each line is indented by tabs according to
+// its depth in the tree:
+//
+//     meta "ExplicitAutoJoints" "true"
+//     +Workspace service
+//     	Name: String "Workspace"
+//     	PrimaryPart: Reference #1
+//     	+Part #1
+//     		Size: Vector3 4 1 2
+//     		Source: ProtectedString |
+//     			|print("hello")
+//     			|
+//
+// A meta line describes a metadata field of the root. A line starting with "+"
+// describes an instance, followed by its class name, an optional "service"
+// flag, and an optional label. The children of an instance are indented under
+// it. Any other line describes a property of the instance it is indented under,
+// as a name, a type, and the components of the value.
+//
+// Metadata and properties are sorted by name, while children retain their
+// order. Only instances that are referred to by a property are labeled, and
+// labels are numbered in tree order.
+
+// rbxTextMultiline is the prefix of each line of a multi-line string.
+const rbxTextMultiline = "|"
+
+// encodeRBXText writes root to w in the text format.
+func encodeRBXText(w io.Writer, root *rbxfile.Root) (err error) {
+	e := rbxTextEncoder{
+		w:      bufio.NewWriter(w),
+		labels: map[*rbxfile.Instance]int{},
+	}
+	for _, inst := range root.Instances {
+		e.walk(inst)
+	}
+	// Number labels in tree order rather than reference order, so that
+	// output is stable.
+	n := 0
+	for _, inst := range e.order {
+		if _, ok := e.labels[inst]; ok {
+			n++
+			e.labels[inst] = n
+		}
+	}
+	for _, k := range sortedStrings(root.Metadata) {
+		e.line(0, "meta ", strconv.Quote(k), " ", strconv.Quote(root.Metadata[k]))
+	}
+	for _, inst := range root.Instances {
+		e.instance(0, inst)
+	}
+	if e.err != nil {
+		return e.err
+	}
+	return e.w.Flush()
+}
+
+// sortedStrings returns the keys of m in sorted order.
+func sortedStrings(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// rbxTextEncoder encodes an rbxfile tree into the text format.
+type rbxTextEncoder struct {
+	w      *bufio.Writer
+	err    error
+	labels map[*rbxfile.Instance]int
+	order  []*rbxfile.Instance
+}
+
+// walk appends inst and its descendants to e.order in tree order, and marks
+// each instance that is referred to by a property.
+func (e *rbxTextEncoder) walk(inst *rbxfile.Instance) {
+	e.order = append(e.order, inst)
+	for _, value := range inst.Properties {
+		if r, ok := value.(rbxfile.ValueReference); ok && r.Instance != nil {
+			e.labels[r.Instance] = 0
+		}
+	}
+	for _, child := range inst.Children {
+		e.walk(child)
+	}
+}
+
+// sortedProperties returns the names of the properties of inst in sorted
+// order.
+func sortedProperties(inst *rbxfile.Instance) []string {
+	props := make([]string, 0, len(inst.Properties))
+	for prop := range inst.Properties {
+		props = append(props, prop)
+	}
+	sort.Strings(props)
+	return props
+}
+
+// line writes the concatenation of s as a line indented to depth.
+func (e *rbxTextEncoder) line(depth int, s ...string) {
+	if e.err != nil {
+		return
+	}
+	if _, e.err = e.w.WriteString(strings.Repeat("\t", depth)); e.err != nil {
+		return
+	}
+	for _, s := range s {
+		if _, e.err = e.w.WriteString(s); e.err != nil {
+			return
+		}
+	}
+	_, e.err = e.w.WriteString("\n")
+}
+
+// instance writes inst and its descendants at depth.
+func (e *rbxTextEncoder) instance(depth int, inst *rbxfile.Instance) {
+	s := "+" + rbxTextName(inst.ClassName)
+	if inst.IsService {
+		s += " service"
+	}
+	if n, ok := e.labels[inst]; ok {
+		s += " #" + strconv.Itoa(n)
+	}
+	e.line(depth, s)
+	for _, prop := range sortedProperties(inst) {
+		value := inst.Properties[prop]
+		if value == nil {
+			continue
+		}
+		head := rbxTextName(prop) + ": " + rbxTextType(value)
+		if s, ok := rbxTextMultilineString(value); ok {
+			e.line(depth+1, head, " ", rbxTextMultiline)
+			for _, l := range strings.Split(s, "\n") {
+				e.line(depth+2, rbxTextMultiline, l)
+			}
+			continue
+		}
+		fields, err := e.value(value)
+		if err != nil {
+			if e.err == nil {
+				e.err = fmt.Errorf("property %s: %w", prop, err)
+			}
+			return
+		}
+		if len(fields) == 0 {
+			e.line(depth+1, head)
+			continue
+		}
+		e.line(depth+1, head, " ", strings.Join(fields, " "))
+	}
+	for _, child := range inst.Children {
+		e.instance(depth+1, child)
+	}
+}
+
+// rbxTextName returns s as a bare word if possible, or a quoted string
+// otherwise.
+func rbxTextName(s string) string {
+	if s == "" {
+		return `""`
+	}
+	for i, c := range s {
+		switch {
+		case c == '_',
+			'A' <= c && c <= 'Z',
+			'a' <= c && c <= 'z',
+			i > 0 && '0' <= c && c <= '9':
+		default:
+			return strconv.Quote(s)
+		}
+	}
+	return s
+}
+
+// rbxTextType returns the name of the type of v. Optional types are suffixed
+// with "?".
+func rbxTextType(v rbxfile.Value) string {
+	if o, ok := v.(rbxfile.ValueOptional); ok {
+		return o.ValueType().String() + "?"
+	}
+	return v.Type().String()
+}
+
+// rbxTextMultilineString returns the content of v if it is a string that should
+// be written over multiple lines.
+func rbxTextMultilineString(v rbxfile.Value) (s string, ok bool) {
+	switch v := v.(type) {
+	case rbxfile.ValueString:
+		s = string(v)
+	case rbxfile.ValueProtectedString:
+		s = string(v)
+	default:
+		return "", false
+	}
+	if !strings.Contains(s, "\n") || strings.Contains(s, "\r") || !utf8.ValidString(s) {
+		return "", false
+	}
+	return s, true
+}
+
+func rbxTextFloat32(f float32) string {
+	return strconv.FormatFloat(float64(f), 'g', -1, 32)
+}
+
+func rbxTextFloats(f ...float32) []string {
+	s := make([]string, len(f))
+	for i, f := range f {
+		s[i] = rbxTextFloat32(f)
+	}
+	return s
+}
+
+// value returns the components of v.
+func (e *rbxTextEncoder) value(v rbxfile.Value) (fields []string, err error) {
+	switch v := v.(type) {
+	case rbxfile.ValueString:
+		return []string{strconv.Quote(string(v))}, nil
+	case rbxfile.ValueBinaryString:
+		return []string{strconv.Quote(string(v))}, nil
+	case rbxfile.ValueProtectedString:
+		return []string{strconv.Quote(string(v))}, nil
+	case rbxfile.ValueContent:
+		return []string{strconv.Quote(string(v))}, nil
+	case rbxfile.ValueSharedString:
+		return []string{strconv.Quote(string(v))}, nil
+	case rbxfile.ValueBool:
+		return []string{strconv.FormatBool(bool(v))}, nil
+	case rbxfile.ValueInt:
+		return []string{strconv.FormatInt(int64(v), 10)}, nil
+	case rbxfile.ValueInt64:
+		return []string{strconv.FormatInt(int64(v), 10)}, nil
+	case rbxfile.ValueToken:
+		return []string{strconv.FormatUint(uint64(v), 10)}, nil
+	case rbxfile.ValueBrickColor:
+		return []string{strconv.FormatUint(uint64(v), 10)}, nil
+	case rbxfile.ValueFloat:
+		return []string{rbxTextFloat32(float32(v))}, nil
+	case rbxfile.ValueDouble:
+		return []string{strconv.FormatFloat(float64(v), 'g', -1, 64)}, nil
+	case rbxfile.ValueUDim:
+		return []string{rbxTextFloat32(v.Scale), strconv.FormatInt(int64(v.Offset), 10)}, nil
+	case rbxfile.ValueUDim2:
+		return []string{
+			rbxTextFloat32(v.X.Scale), strconv.FormatInt(int64(v.X.Offset), 10),
+			rbxTextFloat32(v.Y.Scale), strconv.FormatInt(int64(v.Y.Offset), 10),
+		}, nil
+	case rbxfile.ValueRay:
+		return rbxTextFloats(
+			v.Origin.X, v.Origin.Y, v.Origin.Z,
+			v.Direction.X, v.Direction.Y, v.Direction.Z,
+		), nil
+	case rbxfile.ValueFaces:
+		return rbxTextFlags(
+			"Right", v.Right, "Top", v.Top, "Back", v.Back,
+			"Left", v.Left, "Bottom", v.Bottom, "Front", v.Front,
+		), nil
+	case rbxfile.ValueAxes:
+		return rbxTextFlags("X", v.X, "Y", v.Y, "Z", v.Z), nil
+	case rbxfile.ValueColor3:
+		return rbxTextFloats(v.R, v.G, v.B), nil
+	case rbxfile.ValueVector2:
+		return rbxTextFloats(v.X, v.Y), nil
+	case rbxfile.ValueVector3:
+		return rbxTextFloats(v.X, v.Y, v.Z), nil
+	case rbxfile.ValueCFrame:
+		return append(rbxTextFloats(v.Position.X, v.Position.Y, v.Position.Z), rbxTextFloats(v.Rotation[:]...)...), nil
+	case rbxfile.ValueReference:
+		if v.Instance == nil {
+			return []string{"nil"}, nil
+		}
+		n, ok := e.labels[v.Instance]
+		if !ok {
+			return nil, errors.New("reference to instance outside of tree")
+		}
+		return []string{"#" + strconv.Itoa(n)}, nil
+	case rbxfile.ValueVector3int16:
+		return []string{strconv.Itoa(int(v.X)), strconv.Itoa(int(v.Y)), strconv.Itoa(int(v.Z))}, nil
+	case rbxfile.ValueVector2int16:
+		return []string{strconv.Itoa(int(v.X)), strconv.Itoa(int(v.Y))}, nil
+	case rbxfile.ValueNumberSequence:
+		fields = make([]string, 0, len(v)*3)
+		for _, k := range v {
+			fields = append(fields, rbxTextFloats(k.Time, k.Value, k.Envelope)...)
+		}
+		return fields, nil
+	case rbxfile.ValueColorSequence:
+		fields = make([]string, 0, len(v)*5)
+		for _, k := range v {
+			fields = append(fields, rbxTextFloats(k.Time, k.Value.R, k.Value.G, k.Value.B, k.Envelope)...)
+		}
+		return fields, nil
+	case rbxfile.ValueNumberRange:
+		return rbxTextFloats(v.Min, v.Max), nil
+	case rbxfile.ValueRect:
+		return rbxTextFloats(v.Min.X, v.Min.Y, v.Max.X, v.Max.Y), nil
+	case rbxfile.ValuePhysicalProperties:
+		return append(
+			[]string{strconv.FormatBool(v.CustomPhysics)},
+			rbxTextFloats(v.Density, v.Friction, v.Elasticity, v.FrictionWeight, v.ElasticityWeight)...,
+		), nil
+	case rbxfile.ValueColor3uint8:
+		return []string{strconv.Itoa(int(v.R)), strconv.Itoa(int(v.G)), strconv.Itoa(int(v.B))}, nil
+	case rbxfile.ValueOptional:
+		if v.Value() == nil {
+			return []string{"nil"}, nil
+		}
+		return e.value(v.Value())
+	case rbxfile.ValueUniqueId:
+		return []string{
+			strconv.FormatInt(v.Random, 10),
+			strconv.FormatUint(uint64(v.Time), 10),
+			strconv.FormatUint(uint64(v.Index), 10),
+		}, nil
+	case rbxfile.ValueFont:
+		return []string{
+			strconv.Quote(string(v.Family)),
+			strconv.FormatUint(uint64(v.Weight), 10),
+			strconv.FormatUint(uint64(v.Style), 10),
+			strconv.Quote(string(v.CachedFaceId)),
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", v.Type())
+}
+
+// rbxTextFlags receives pairs of names and flags, and returns the names of the
+// flags that are set.
+func rbxTextFlags(pairs ...interface{}) []string {
+	var names []string
+	for i := 0; i < len(pairs); i += 2 {
+		if pairs[i+1].(bool) {
+			names = append(names, pairs[i].(string))
+		}
+	}
+	return names
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// decodeRBXText reads a root from r in the text format.
+func decodeRBXText(r io.Reader) (root *rbxfile.Root, err error) {
+	d := rbxTextDecoder{
+		s:      bufio.NewScanner(r),
+		labels: map[int]*rbxfile.Instance{},
+	}
+	d.s.Buffer(nil, 1<<30)
+	if root, err = d.root(); err != nil {
+		return nil, fmt.Errorf("line %d: %w", d.n, err)
+	}
+	for _, ref := range d.refs {
+		inst, ok := d.labels[ref.label]
+		if !ok {
+			return nil, fmt.Errorf("line %d: unknown label #%d", ref.line, ref.label)
+		}
+		ref.inst.Properties[ref.prop] = rbxfile.ValueReference{Instance: inst}
+	}
+	return root, nil
+}
+
+// rbxTextRef holds a reference property to be resolved after the tree is
+// read.
+type rbxTextRef struct {
+	line  int
+	inst  *rbxfile.Instance
+	prop  string
+	label int
+}
+
+// rbxTextDecoder decodes the text format into an rbxfile tree.
+type rbxTextDecoder struct {
+	s      *bufio.Scanner
+	n      int
+	labels map[int]*rbxfile.Instance
+	refs   []rbxTextRef
+	// Line that has been read but not consumed.
+	next    string
+	hasNext bool
+}
+
+// readLine returns the next line split into its depth and content.
+func (d *rbxTextDecoder) readLine() (depth int, line string, ok bool) {
+	if d.hasNext {
+		d.hasNext = false
+		line = d.next
+	} else {
+		if !d.s.Scan() {
+			return 0, "", false
+		}
+		d.n++
+		line = d.s.Text()
+	}
+	for depth < len(line) && line[depth] == '\t' {
+		depth++
+	}
+	return depth, line[depth:], true
+}
+
+// unreadLine causes the next call to readLine to return the line at depth.
+func (d *rbxTextDecoder) unreadLine(depth int, line string) {
+	d.next = strings.Repeat("\t", depth) + line
+	d.hasNext = true
+}
+
+func (d *rbxTextDecoder) root() (root *rbxfile.Root, err error) {
+	root = rbxfile.NewRoot()
+	var parents []*rbxfile.Instance
+	for {
+		depth, line, ok := d.readLine()
+		if !ok {
+			break
+		}
+		if line == "" {
+			if depth == 0 {
+				continue
+			}
+			return nil, errors.New("unexpected indentation")
+		}
+		if depth > len(parents) {
+			return nil, errors.New("unexpected indentation")
+		}
+		parents = parents[:depth]
+		switch {
+		case strings.HasPrefix(line, "+"):
+			inst, err := d.instance(line[1:])
+			if err != nil {
+				return nil, err
+			}
+			if depth == 0 {
+				root.Instances = append(root.Instances, inst)
+			} else {
+				parent := parents[depth-1]
+				parent.Children = append(parent.Children, inst)
+			}
+			parents = append(parents, inst)
+		case depth == 0:
+			tokens, err := rbxTextTokens(line)
+			if err != nil {
+				return nil, err
+			}
+			if len(tokens) != 3 || tokens[0] != "meta" {
+				return nil, errors.New("expected meta or instance")
+			}
+			k, err := rbxTextString(tokens[1])
+			if err != nil {
+				return nil, err
+			}
+			v, err := rbxTextString(tokens[2])
+			if err != nil {
+				return nil, err
+			}
+			root.Metadata[k] = v
+		default:
+			if err := d.property(depth, parents[depth-1], line); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := d.s.Err(); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// instance decodes an instance line, excluding the "+" prefix.
+func (d *rbxTextDecoder) instance(line string) (inst *rbxfile.Instance, err error) {
+	tokens, err := rbxTextTokens(line)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, errors.New("expected class name")
+	}
+	className, err := rbxTextString(tokens[0])
+	if err != nil {
+		return nil, err
+	}
+	inst = rbxfile.NewInstance(className)
+	for _, token := range tokens[1:] {
+		switch {
+		case token == "service":
+			inst.IsService = true
+		case strings.HasPrefix(token, "#"):
+			n, err := strconv.Atoi(token[1:])
+			if err != nil {
+				return nil, fmt.Errorf("invalid label %q", token)
+			}
+			if _, ok := d.labels[n]; ok {
+				return nil, fmt.Errorf("duplicate label %q", token)
+			}
+			d.labels[n] = inst
+		default:
+			return nil, fmt.Errorf("unexpected %q", token)
+		}
+	}
+	return inst, nil
+}
+
+// property decodes a property line at depth into inst.
+func (d *rbxTextDecoder) property(depth int, inst *rbxfile.Instance, line string) error {
+	var prop string
+	var rest string
+	if strings.HasPrefix(line, `"`) {
+		q, err := strconv.QuotedPrefix(line)
+		if err != nil {
+			return err
+		}
+		if prop, err = strconv.Unquote(q); err != nil {
+			return err
+		}
+		rest = line[len(q):]
+		if !strings.HasPrefix(rest, ":") {
+			return errors.New("expected ':' after property name")
+		}
+		rest = rest[1:]
+	} else {
+		i := strings.IndexByte(line, ':')
+		if i < 0 {
+			return errors.New("expected ':' after property name")
+		}
+		prop, rest = line[:i], line[i+1:]
+	}
+	if _, ok := inst.Properties[prop]; ok {
+		return fmt.Errorf("duplicate property %s", prop)
+	}
+	tokens, err := rbxTextTokens(rest)
+	if err != nil {
+		return fmt.Errorf("property %s: %w", prop, err)
+	}
+	if len(tokens) == 0 {
+		return fmt.Errorf("property %s: expected type", prop)
+	}
+	typeName, fields := tokens[0], tokens[1:]
+	optional := strings.HasSuffix(typeName, "?")
+	typ := rbxfile.TypeFromString(strings.TrimSuffix(typeName, "?"))
+	if typ == rbxfile.TypeInvalid || typ == rbxfile.TypeOptional {
+		return fmt.Errorf("property %s: unknown type %q", prop, typeName)
+	}
+
+	if len(fields) == 1 && fields[0] == rbxTextMultiline {
+		var lines []string
+		for {
+			ldepth, l, ok := d.readLine()
+			if !ok {
+				break
+			}
+			if ldepth != depth+1 || !strings.HasPrefix(l, rbxTextMultiline) {
+				d.unreadLine(ldepth, l)
+				break
+			}
+			lines = append(lines, l[len(rbxTextMultiline):])
+		}
+		s := strings.Join(lines, "\n")
+		var value rbxfile.Value
+		switch typ {
+		case rbxfile.TypeString:
+			value = rbxfile.ValueString(s)
+		case rbxfile.TypeProtectedString:
+			value = rbxfile.ValueProtectedString(s)
+		default:
+			return fmt.Errorf("property %s: type %s cannot be multi-line", prop, typ)
+		}
+		if optional {
+			value = rbxfile.Some(value)
+		}
+		inst.Properties[prop] = value
+		return nil
+	}
+
+	if optional && len(fields) == 1 && fields[0] == "nil" {
+		inst.Properties[prop] = rbxfile.None(typ)
+		return nil
+	}
+	if typ == rbxfile.TypeReference {
+		if len(fields) != 1 {
+			return fmt.Errorf("property %s: expected label or nil", prop)
+		}
+		if fields[0] == "nil" {
+			inst.Properties[prop] = rbxfile.ValueReference{}
+			return nil
+		}
+		if !strings.HasPrefix(fields[0], "#") {
+			return fmt.Errorf("property %s: expected label or nil", prop)
+		}
+		n, err := strconv.Atoi(fields[0][1:])
+		if err != nil {
+			return fmt.Errorf("property %s: invalid label %q", prop, fields[0])
+		}
+		// Reserve the property so that duplicates are detected.
+		inst.Properties[prop] = rbxfile.ValueReference{}
+		d.refs = append(d.refs, rbxTextRef{line: d.n, inst: inst, prop: prop, label: n})
+		return nil
+	}
+	value, err := rbxTextValue(typ, fields)
+	if err != nil {
+		return fmt.Errorf("property %s: %w", prop, err)
+	}
+	if optional {
+		value = rbxfile.Some(value)
+	}
+	inst.Properties[prop] = value
+	return nil
+}
+
+// rbxTextTokens splits s into space-separated tokens. A quoted string is a
+// single token.
+func rbxTextTokens(s string) (tokens []string, err error) {
+	for {
+		s = strings.TrimLeft(s, " ")
+		if s == "" {
+			return tokens, nil
+		}
+		if s[0] == '"' {
+			q, err := strconv.QuotedPrefix(s)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, q)
+			s = s[len(q):]
+			if s != "" && s[0] != ' ' {
+				return nil, errors.New("expected space after string")
+			}
+			continue
+		}
+		i := strings.IndexByte(s, ' ')
+		if i < 0 {
+			i = len(s)
+		}
+		tokens = append(tokens, s[:i])
+		s = s[i:]
+	}
+}
+
+// rbxTextString decodes a token that is a bare word or quoted string.
+func rbxTextString(token string) (string, error) {
+	if strings.HasPrefix(token, `"`) {
+		return strconv.Unquote(token)
+	}
+	return token, nil
+}
+
+// rbxTextFields parses the components of a value.
+type rbxTextFields struct {
+	fields []string
+	err    error
+}
+
+func (f *rbxTextFields) check(n int) bool {
+	if len(f.fields) != n {
+		f.err = fmt.Errorf("expected %d components, got %d", n, len(f.fields))
+		return false
+	}
+	return true
+}
+
+func (f *rbxTextFields) float32(i int) float32 {
+	v, err := strconv.ParseFloat(f.fields[i], 32)
+	if err != nil && f.err == nil {
+		f.err = err
+	}
+	return float32(v)
+}
+
+func (f *rbxTextFields) float64(i int) float64 {
+	v, err := strconv.ParseFloat(f.fields[i], 64)
+	if err != nil && f.err == nil {
+		f.err = err
+	}
+	return v
+}
+
+func (f *rbxTextFields) int(i int, bits int) int64 {
+	v, err := strconv.ParseInt(f.fields[i], 10, bits)
+	if err != nil && f.err == nil {
+		f.err = err
+	}
+	return v
+}
+
+func (f *rbxTextFields) uint(i int, bits int) uint64 {
+	v, err := strconv.ParseUint(f.fields[i], 10, bits)
+	if err != nil && f.err == nil {
+		f.err = err
+	}
+	return v
+}
+
+func (f *rbxTextFields) bool(i int) bool {
+	v, err := strconv.ParseBool(f.fields[i])
+	if err != nil && f.err == nil {
+		f.err = err
+	}
+	return v
+}
+
+func (f *rbxTextFields) string(i int) string {
+	if !strings.HasPrefix(f.fields[i], `"`) {
+		if f.err == nil {
+			f.err = fmt.Errorf("expected string, got %s", f.fields[i])
+		}
+		return ""
+	}
+	v, err := strconv.Unquote(f.fields[i])
+	if err != nil && f.err == nil {
+		f.err = err
+	}
+	return v
+}
+
+func (f *rbxTextFields) vector3(i int) rbxfile.ValueVector3 {
+	return rbxfile.ValueVector3{X: f.float32(i), Y: f.float32(i + 1), Z: f.float32(i + 2)}
+}
+
+func (f *rbxTextFields) vector2(i int) rbxfile.ValueVector2 {
+	return rbxfile.ValueVector2{X: f.float32(i), Y: f.float32(i + 1)}
+}
+
+// flags sets each flag corresponding to the names in f.
+func (f *rbxTextFields) flags(flags map[string]*bool) {
+	for _, name := range f.fields {
+		flag, ok := flags[name]
+		if !ok {
+			if f.err == nil {
+				f.err = fmt.Errorf("unexpected %q", name)
+			}
+			return
+		}
+		*flag = true
+	}
+}
+
+// rbxTextValue decodes fields into a value of type typ.
+func rbxTextValue(typ rbxfile.Type, fields []string) (v rbxfile.Value, err error) {
+	f := rbxTextFields{fields: fields}
+	switch typ {
+	case rbxfile.TypeString:
+		if f.check(1) {
+			v = rbxfile.ValueString(f.string(0))
+		}
+	case rbxfile.TypeBinaryString:
+		if f.check(1) {
+			v = rbxfile.ValueBinaryString(f.string(0))
+		}
+	case rbxfile.TypeProtectedString:
+		if f.check(1) {
+			v = rbxfile.ValueProtectedString(f.string(0))
+		}
+	case rbxfile.TypeContent:
+		if f.check(1) {
+			v = rbxfile.ValueContent(f.string(0))
+		}
+	case rbxfile.TypeSharedString:
+		if f.check(1) {
+			v = rbxfile.ValueSharedString(f.string(0))
+		}
+	case rbxfile.TypeBool:
+		if f.check(1) {
+			v = rbxfile.ValueBool(f.bool(0))
+		}
+	case rbxfile.TypeInt:
+		if f.check(1) {
+			v = rbxfile.ValueInt(f.int(0, 32))
+		}
+	case rbxfile.TypeInt64:
+		if f.check(1) {
+			v = rbxfile.ValueInt64(f.int(0, 64))
+		}
+	case rbxfile.TypeToken:
+		if f.check(1) {
+			v = rbxfile.ValueToken(f.uint(0, 32))
+		}
+	case rbxfile.TypeBrickColor:
+		if f.check(1) {
+			v = rbxfile.ValueBrickColor(f.uint(0, 32))
+		}
+	case rbxfile.TypeFloat:
+		if f.check(1) {
+			v = rbxfile.ValueFloat(f.float32(0))
+		}
+	case rbxfile.TypeDouble:
+		if f.check(1) {
+			v = rbxfile.ValueDouble(f.float64(0))
+		}
+	case rbxfile.TypeUDim:
+		if f.check(2) {
+			v = rbxfile.ValueUDim{Scale: f.float32(0), Offset: int32(f.int(1, 32))}
+		}
+	case rbxfile.TypeUDim2:
+		if f.check(4) {
+			v = rbxfile.ValueUDim2{
+				X: rbxfile.ValueUDim{Scale: f.float32(0), Offset: int32(f.int(1, 32))},
+				Y: rbxfile.ValueUDim{Scale: f.float32(2), Offset: int32(f.int(3, 32))},
+			}
+		}
+	case rbxfile.TypeRay:
+		if f.check(6) {
+			v = rbxfile.ValueRay{Origin: f.vector3(0), Direction: f.vector3(3)}
+		}
+	case rbxfile.TypeFaces:
+		var faces rbxfile.ValueFaces
+		f.flags(map[string]*bool{
+			"Right": &faces.Right, "Top": &faces.Top, "Back": &faces.Back,
+			"Left": &faces.Left, "Bottom": &faces.Bottom, "Front": &faces.Front,
+		})
+		v = faces
+	case rbxfile.TypeAxes:
+		var axes rbxfile.ValueAxes
+		f.flags(map[string]*bool{"X": &axes.X, "Y": &axes.Y, "Z": &axes.Z})
+		v = axes
+	case rbxfile.TypeColor3:
+		if f.check(3) {
+			v = rbxfile.ValueColor3{R: f.float32(0), G: f.float32(1), B: f.float32(2)}
+		}
+	case rbxfile.TypeVector2:
+		if f.check(2) {
+			v = f.vector2(0)
+		}
+	case rbxfile.TypeVector3:
+		if f.check(3) {
+			v = f.vector3(0)
+		}
+	case rbxfile.TypeCFrame:
+		if f.check(12) {
+			cf := rbxfile.ValueCFrame{Position: f.vector3(0)}
+			for i := range cf.Rotation {
+				cf.Rotation[i] = f.float32(3 + i)
+			}
+			v = cf
+		}
+	case rbxfile.TypeVector3int16:
+		if f.check(3) {
+			v = rbxfile.ValueVector3int16{X: int16(f.int(0, 16)), Y: int16(f.int(1, 16)), Z: int16(f.int(2, 16))}
+		}
+	case rbxfile.TypeVector2int16:
+		if f.check(2) {
+			v = rbxfile.ValueVector2int16{X: int16(f.int(0, 16)), Y: int16(f.int(1, 16))}
+		}
+	case rbxfile.TypeNumberSequence:
+		if len(fields)%3 != 0 {
+			return nil, errors.New("expected groups of 3 components")
+		}
+		s := make(rbxfile.ValueNumberSequence, len(fields)/3)
+		for i := range s {
+			s[i] = rbxfile.ValueNumberSequenceKeypoint{
+				Time:     f.float32(i * 3),
+				Value:    f.float32(i*3 + 1),
+				Envelope: f.float32(i*3 + 2),
+			}
+		}
+		v = s
+	case rbxfile.TypeColorSequence:
+		if len(fields)%5 != 0 {
+			return nil, errors.New("expected groups of 5 components")
+		}
+		s := make(rbxfile.ValueColorSequence, len(fields)/5)
+		for i := range s {
+			s[i] = rbxfile.ValueColorSequenceKeypoint{
+				Time:     f.float32(i * 5),
+				Value:    rbxfile.ValueColor3{R: f.float32(i*5 + 1), G: f.float32(i*5 + 2), B: f.float32(i*5 + 3)},
+				Envelope: f.float32(i*5 + 4),
+			}
+		}
+		v = s
+	case rbxfile.TypeNumberRange:
+		if f.check(2) {
+			v = rbxfile.ValueNumberRange{Min: f.float32(0), Max: f.float32(1)}
+		}
+	case rbxfile.TypeRect:
+		if f.check(4) {
+			v = rbxfile.ValueRect{Min: f.vector2(0), Max: f.vector2(2)}
+		}
+	case rbxfile.TypePhysicalProperties:
+		if f.check(6) {
+			v = rbxfile.ValuePhysicalProperties{
+				CustomPhysics:    f.bool(0),
+				Density:          f.float32(1),
+				Friction:         f.float32(2),
+				Elasticity:       f.float32(3),
+				FrictionWeight:   f.float32(4),
+				ElasticityWeight: f.float32(5),
+			}
+		}
+	case rbxfile.TypeColor3uint8:
+		if f.check(3) {
+			v = rbxfile.ValueColor3uint8{R: byte(f.uint(0, 8)), G: byte(f.uint(1, 8)), B: byte(f.uint(2, 8))}
+		}
+	case rbxfile.TypeUniqueId:
+		if f.check(3) {
+			v = rbxfile.ValueUniqueId{
+				Random: f.int(0, 64),
+				Time:   uint32(f.uint(1, 32)),
+				Index:  uint32(f.uint(2, 32)),
+			}
+		}
+	case rbxfile.TypeFont:
+		if f.check(4) {
+			v = rbxfile.ValueFont{
+				Family:       rbxfile.ValueContent(f.string(0)),
+				Weight:       rbxfile.FontWeight(f.uint(1, 16)),
+				Style:        rbxfile.FontStyle(f.uint(2, 8)),
+				CachedFaceId: rbxfile.ValueContent(f.string(3)),
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported type %s", typ)
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	return v, nil
+}
@@ -3,9 +3,15 @@ package rbxmk
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
 
+	lua "github.com/anaminus/gopher-lua"
 	"github.com/anaminus/rbxmk/rtypes"
 )
 
@@ -15,14 +21,28 @@ type HttpRequest struct {
 
 	cancel context.CancelFunc
 
-	respch chan *http.Response
-	resp   *rtypes.HttpResponse
+	// Closed when the request completes.
+	done chan struct{}
+	// Set before done is closed.
+	httpResp *http.Response
+	httpBody []byte
+	httpErr  error
 
-	errch chan error
-	err   error
+	resp *rtypes.HttpResponse
+	err  error
 
 	fmt Format
 	sel rtypes.FormatSelector
+
+	// Maximum duration of the request, or 0 for no limit.
+	timeout time.Duration
+	// Function to which progress is reported, or nil.
+	progress *lua.LFunction
+	// Number of bytes transferred so far, accessed atomically.
+	sent, received int64
+	// Total number of bytes to transfer, or -1 if unknown, accessed
+	// atomically.
+	sendTotal, receiveTotal int64
 }
 
 // Type returns a string identifying the type of the value.
@@ -30,43 +50,170 @@ func (*HttpRequest) Type() string {
 	return rtypes.T_HttpRequest
 }
 
-// do concurrently begins the request.
+// HttpProgress reports the number of bytes transferred by an HttpRequest.
+type HttpProgress struct {
+	// Sent is the number of bytes of the request body that have been sent.
+	Sent int64
+	// SendTotal is the size of the request body, or -1 if unknown.
+	SendTotal int64
+	// Received is the number of bytes of the response body that have been
+	// received.
+	Received int64
+	// ReceiveTotal is the size of the response body, or -1 if unknown.
+	ReceiveTotal int64
+}
+
+// progressInterval is the interval at which progress is reported.
+const progressInterval = 100 * time.Millisecond
+
+// Progress returns the current progress of the request.
+func (r *HttpRequest) Progress() HttpProgress {
+	return HttpProgress{
+		Sent:         atomic.LoadInt64(&r.sent),
+		SendTotal:    atomic.LoadInt64(&r.sendTotal),
+		Received:     atomic.LoadInt64(&r.received),
+		ReceiveTotal: atomic.LoadInt64(&r.receiveTotal),
+	}
+}
+
+// ProgressFunc returns the Lua function specified by the Progress field of the
+// options of the request, or nil if unspecified.
+func (r *HttpRequest) ProgressFunc() *lua.LFunction {
+	return r.progress
+}
+
+// LuaProgress returns a function that calls the Progress function of the
+// request within l, or nil if the request has no Progress function.
+func (r *HttpRequest) LuaProgress(l *lua.LState) func(HttpProgress) {
+	return luaProgress(l, r.progress)
+}
+
+// luaProgress returns a function that calls fn within l with the number of
+// bytes transferred, or nil if fn is nil.
+func luaProgress(l *lua.LState, fn *lua.LFunction) func(HttpProgress) {
+	if fn == nil {
+		return nil
+	}
+	total := func(n int64) lua.LValue {
+		if n < 0 {
+			return lua.LNil
+		}
+		return lua.LNumber(n)
+	}
+	return func(p HttpProgress) {
+		l.Push(fn)
+		l.Push(lua.LNumber(p.Sent))
+		l.Push(total(p.SendTotal))
+		l.Push(lua.LNumber(p.Received))
+		l.Push(total(p.ReceiveTotal))
+		l.Call(4, 0)
+	}
+}
+
+// countingReader counts the number of bytes read from a reader.
+type countingReader struct {
+	io.ReadCloser
+	n *int64
+}
+
+func (r countingReader) Read(p []byte) (n int, err error) {
+	n, err = r.ReadCloser.Read(p)
+	atomic.AddInt64(r.n, int64(n))
+	return n, err
+}
+
+// do concurrently performs the request, including reading the response body.
 func (r *HttpRequest) do(client *Client, req *http.Request) {
-	defer close(r.respch)
-	defer close(r.errch)
+	defer close(r.done)
+	release, err := client.acquire(req.Context())
+	if err != nil {
+		r.httpErr = err
+		return
+	}
+	defer release()
+
+	// Start timeout after the request is allowed to run.
+	if r.timeout > 0 {
+		ctx, cancel := context.WithTimeout(req.Context(), r.timeout)
+		defer cancel()
+		req = req.WithContext(ctx)
+	}
+	defer func() {
+		if r.httpErr != nil && errors.Is(r.httpErr, context.DeadlineExceeded) {
+			r.httpErr = fmt.Errorf("request timed out after %s: %w", r.timeout, r.httpErr)
+		}
+	}()
+
+	// Count bytes sent, including those sent again on retries.
+	if req.Body != nil {
+		req.Body = countingReader{ReadCloser: req.Body, n: &r.sent}
+		if getBody := req.GetBody; getBody != nil {
+			req.GetBody = func() (io.ReadCloser, error) {
+				body, err := getBody()
+				if err != nil {
+					return nil, err
+				}
+				atomic.StoreInt64(&r.sent, 0)
+				return countingReader{ReadCloser: body, n: &r.sent}, nil
+			}
+		}
+	}
+
 	resp, err := client.Do(req)
 	if err != nil {
-		r.errch <- err
+		r.httpErr = err
 		return
 	}
-	r.respch <- resp
+	defer resp.Body.Close()
+	r.httpResp = resp
+	if r.fmt.Name != "" {
+		atomic.StoreInt64(&r.receiveTotal, resp.ContentLength)
+		r.httpBody, r.httpErr = io.ReadAll(countingReader{ReadCloser: resp.Body, n: &r.received})
+	}
+}
+
+// Done returns a channel that is closed when the request completes. After the
+// channel is closed, Resolve does not block.
+func (r *HttpRequest) Done() <-chan struct{} {
+	return r.done
 }
 
 // Resolve blocks until the request resolves.
 func (r *HttpRequest) Resolve() (*rtypes.HttpResponse, error) {
+	return r.ResolveProgress(nil)
+}
+
+// ResolveProgress blocks until the request resolves. While blocked, progress is
+// called periodically from the calling goroutine when the number of bytes
+// transferred has changed. progress may be nil.
+func (r *HttpRequest) ResolveProgress(progress func(HttpProgress)) (*rtypes.HttpResponse, error) {
 	if r.resp != nil || r.err != nil {
 		return r.resp, r.err
 	}
-	select {
-	case resp := <-r.respch:
-		defer resp.Body.Close()
-		headers := rtypes.HttpHeaders(resp.Header)
-		r.resp = &rtypes.HttpResponse{
-			Success:       200 <= resp.StatusCode && resp.StatusCode < 300,
-			StatusCode:    resp.StatusCode,
-			StatusMessage: resp.Status,
-			Headers:       headers,
-			Cookies:       headers.RetrieveSetCookies(),
-		}
-		if r.fmt.Name != "" {
-			if r.resp.Body, r.err = r.fmt.Decode(r.global, r.sel, resp.Body); r.err != nil {
-				return nil, r.err
-			}
-		}
-		return r.resp, nil
-	case r.err = <-r.errch:
+	watcher := newProgressWatcher([]*HttpRequest{r}, []func(HttpProgress){progress})
+	defer watcher.stop()
+	watcher.wait(r.done)
+	if r.httpErr != nil {
+		r.err = r.httpErr
 		return nil, r.err
 	}
+	resp := r.httpResp
+	headers := rtypes.HttpHeaders(resp.Header)
+	r.resp = &rtypes.HttpResponse{
+		Success:       200 <= resp.StatusCode && resp.StatusCode < 300,
+		StatusCode:    resp.StatusCode,
+		StatusMessage: resp.Status,
+		Headers:       headers,
+		Cookies:       headers.RetrieveSetCookies(),
+	}
+	if r.fmt.Name != "" {
+		if r.resp.Body, r.err = r.fmt.Decode(r.global, r.sel, bytes.NewReader(r.httpBody)); r.err != nil {
+			r.resp = nil
+			return nil, r.err
+		}
+	}
+	r.httpBody = nil
+	return r.resp, nil
 }
 
 // Cancel cancels the request.
@@ -75,9 +222,152 @@ func (r *HttpRequest) Cancel() {
 		return
 	}
 	r.cancel()
-	defer close(r.respch)
-	defer close(r.errch)
-	r.err = <-r.errch
+	<-r.done
+	if r.err = r.httpErr; r.err == nil {
+		// Request completed before it could be canceled.
+		r.err = context.Canceled
+	}
+}
+
+// progressWatcher reports the progress of a number of requests to
+// corresponding functions.
+type progressWatcher struct {
+	requests []*HttpRequest
+	progress []func(HttpProgress)
+	prev     []HttpProgress
+	ticker   *time.Ticker
+}
+
+// newProgressWatcher returns a progressWatcher that reports the progress of
+// each request to the function at the same index in progress. A function may be
+// nil, and progress may be shorter than requests.
+func newProgressWatcher(requests []*HttpRequest, progress []func(HttpProgress)) *progressWatcher {
+	w := &progressWatcher{
+		requests: requests,
+		progress: progress,
+		prev:     make([]HttpProgress, len(progress)),
+	}
+	for _, fn := range progress {
+		if fn != nil {
+			w.ticker = time.NewTicker(progressInterval)
+			break
+		}
+	}
+	return w
+}
+
+// report calls the function of each request whose progress has changed.
+func (w *progressWatcher) report() {
+	for i, fn := range w.progress {
+		if fn == nil {
+			continue
+		}
+		if p := w.requests[i].Progress(); p != w.prev[i] {
+			w.prev[i] = p
+			fn(p)
+		}
+	}
+}
+
+// tick returns a channel that receives when progress should be reported, or
+// nil if no progress is reported.
+func (w *progressWatcher) tick() <-chan time.Time {
+	if w.ticker == nil {
+		return nil
+	}
+	return w.ticker.C
+}
+
+// wait blocks until done is closed, reporting progress periodically, and once
+// more after done is closed.
+func (w *progressWatcher) wait(done <-chan struct{}) {
+	for {
+		select {
+		case <-done:
+			w.report()
+			return
+		case <-w.tick():
+			w.report()
+		}
+	}
+}
+
+// stop releases the resources of the watcher.
+func (w *progressWatcher) stop() {
+	if w.ticker != nil {
+		w.ticker.Stop()
+	}
+}
+
+// ResolveAll resolves each request, returning the responses in the same order.
+// Because requests run concurrently, the total time is that of the slowest
+// request. If a request fails, then the remaining requests are canceled, and
+// the index of the failed request is returned with the error.
+func ResolveAll(requests []*HttpRequest) (resps []*rtypes.HttpResponse, index int, err error) {
+	return ResolveAllProgress(requests, nil)
+}
+
+// ResolveAllProgress is like ResolveAll, but while blocked, the progress of
+// each request is reported to the function at the same index in progress, as
+// with ResolveProgress. progress, or any function within, may be nil.
+func ResolveAllProgress(requests []*HttpRequest, progress []func(HttpProgress)) (resps []*rtypes.HttpResponse, index int, err error) {
+	watcher := newProgressWatcher(requests, progress)
+	defer watcher.stop()
+	resps = make([]*rtypes.HttpResponse, len(requests))
+	for i, request := range requests {
+		watcher.wait(request.Done())
+		if resps[i], err = request.Resolve(); err != nil {
+			for _, request := range requests[i+1:] {
+				request.Cancel()
+			}
+			return nil, i, err
+		}
+	}
+	return resps, -1, nil
+}
+
+// ResolveAny blocks until any request resolves successfully, returning the
+// response and the index of the request. Other requests are not canceled, and
+// may still be resolved. If every request fails, then an error is returned.
+func ResolveAny(requests []*HttpRequest) (resp *rtypes.HttpResponse, index int, err error) {
+	return ResolveAnyProgress(requests, nil)
+}
+
+// ResolveAnyProgress is like ResolveAny, but while blocked, the progress of
+// each request is reported to the function at the same index in progress, as
+// with ResolveProgress. progress, or any function within, may be nil.
+func ResolveAnyProgress(requests []*HttpRequest, progress []func(HttpProgress)) (resp *rtypes.HttpResponse, index int, err error) {
+	if len(requests) == 0 {
+		return nil, -1, fmt.Errorf("no requests")
+	}
+	watcher := newProgressWatcher(requests, progress)
+	defer watcher.stop()
+	done := make(chan int, len(requests))
+	for i, request := range requests {
+		go func(i int, request *HttpRequest) {
+			<-request.Done()
+			done <- i
+		}(i, request)
+	}
+	var errs []string
+	for range requests {
+		var i int
+	wait:
+		for {
+			select {
+			case i = <-done:
+				watcher.report()
+				break wait
+			case <-watcher.tick():
+				watcher.report()
+			}
+		}
+		if resp, err = requests[i].Resolve(); err == nil {
+			return resp, i, nil
+		}
+		errs = append(errs, fmt.Sprintf("request #%d: %s", i+1, err))
+	}
+	return nil, -1, fmt.Errorf("all requests failed:\n\t%s", strings.Join(errs, "\n\t"))
 }
 
 // BeginHttpRequest begins an HTTP request according to the given options, in
@@ -107,7 +397,7 @@ func BeginHttpRequest(w *World, options rtypes.HttpOptions) (request *HttpReques
 	}
 
 	// Create request.
-	ctx, cancel := context.WithCancel(context.TODO())
+	ctx, cancel := context.WithCancel(context.Background())
 	var req *http.Request
 	if buf != nil {
 		// Use of *bytes.Buffer guarantees that req.GetBody will be set.
@@ -124,27 +414,50 @@ func BeginHttpRequest(w *World, options rtypes.HttpOptions) (request *HttpReques
 	}
 	req.Header = http.Header(options.Headers.AppendCookies(options.Cookies))
 
+	// Override retry policy of client.
+	if options.Retries != nil || options.RetryDelay != nil {
+		policy := w.Client.RetryPolicy()
+		if options.Retries != nil {
+			policy.Retries = *options.Retries
+		}
+		if options.RetryDelay != nil {
+			policy.Delay = *options.RetryDelay
+		}
+		req = req.WithContext(WithRetryPolicy(ctx, policy))
+	}
+
 	// Push request object.
 	request = &HttpRequest{
-		global: w.Global,
-		cancel: cancel,
-		respch: make(chan *http.Response),
-		errch:  make(chan error),
-		fmt:    respfmt,
-		sel:    options.ResponseFormat,
+		global:       w.Global,
+		cancel:       cancel,
+		done:         make(chan struct{}),
+		fmt:          respfmt,
+		sel:          options.ResponseFormat,
+		timeout:      w.Client.Timeout(),
+		progress:     options.Progress,
+		sendTotal:    req.ContentLength,
+		receiveTotal: -1,
+	}
+	if options.Timeout != nil {
+		request.timeout = *options.Timeout
+	}
+	if req.Body == nil {
+		request.sendTotal = 0
 	}
 	go request.do(w.Client, req)
 	return request, nil
 }
 
 // DoHttpRequest begins and resolves an HttpRequest. Returns an error if the
-// reponse did not return a successful status.
+// reponse did not return a successful status. While resolving, progress is
+// reported to the Progress function of options, which is called within the Lua
+// state of the world.
 func DoHttpRequest(w *World, options rtypes.HttpOptions) (resp *rtypes.HttpResponse, err error) {
 	request, err := BeginHttpRequest(w, options)
 	if err != nil {
 		return nil, err
 	}
-	if resp, err = request.Resolve(); err != nil {
+	if resp, err = request.ResolveProgress(request.LuaProgress(w.LuaState())); err != nil {
 		return nil, err
 	}
 	if !resp.Success {
@@ -5,6 +5,7 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 
 	lua "github.com/anaminus/gopher-lua"
 	"github.com/anaminus/rbxmk"
@@ -28,20 +29,23 @@ var FS = rbxmk.Library{
 		reflect.DirEntry,
 		reflect.FileInfo,
 		reflect.FormatSelector,
+		reflect.Instance,
 		reflect.String,
 		reflect.Variant,
 	},
 }
 
 func openFS(s rbxmk.State) *lua.LTable {
-	lib := s.L.CreateTable(0, 7)
+	lib := s.L.CreateTable(0, 9)
 	lib.RawSetString("dir", s.WrapFunc(fsDir))
 	lib.RawSetString("mkdir", s.WrapFunc(fsMkdir))
 	lib.RawSetString("read", s.WrapFunc(fsRead))
+	lib.RawSetString("readdir", s.WrapFunc(fsReadDir))
 	lib.RawSetString("remove", s.WrapFunc(fsRemove))
 	lib.RawSetString("rename", s.WrapFunc(fsRename))
 	lib.RawSetString("stat", s.WrapFunc(fsStat))
 	lib.RawSetString("write", s.WrapFunc(fsWrite))
+	lib.RawSetString("writedir", s.WrapFunc(fsWriteDir))
 	return lib
 }
 
@@ -89,6 +93,19 @@ func fsRead(s rbxmk.State) int {
 	return s.Push(v)
 }
 
+func fsReadDir(s rbxmk.State) int {
+	dirname := string(s.Pull(1, rtypes.T_String).(types.String))
+	inst, err := FSSource{World: s.World}.ReadDir(dirname)
+	if err != nil {
+		return s.RaiseError("%s", err)
+	}
+	if inst == nil {
+		s.L.Push(lua.LNil)
+		return 1
+	}
+	return s.Push(inst)
+}
+
 func fsRemove(s rbxmk.State) int {
 	path := string(s.Pull(1, rtypes.T_String).(types.String))
 	all := bool(s.PullOpt(2, types.Bool(false), rtypes.T_Bool).(types.Bool))
@@ -146,6 +163,16 @@ func fsWrite(s rbxmk.State) int {
 	return 0
 }
 
+func fsWriteDir(s rbxmk.State) int {
+	dirname := string(s.Pull(1, rtypes.T_String).(types.String))
+	inst := s.Pull(2, rtypes.T_Instance).(*rtypes.Instance)
+	selector := s.PullOpt(3, rtypes.FormatSelector{}, rtypes.T_FormatSelector).(rtypes.FormatSelector)
+	if err := (FSSource{World: s.World}).WriteDir(dirname, inst, selector); err != nil {
+		return s.RaiseError("%s", err)
+	}
+	return 0
+}
+
 func dumpFS(s rbxmk.State) dump.Library {
 	return dump.Library{
 		Struct: dump.Struct{
@@ -185,6 +212,17 @@ func dumpFS(s rbxmk.State) dump.Library {
 					Summary:     "Libraries/fs:Fields/read/Summary",
 					Description: "Libraries/fs:Fields/read/Description",
 				},
+				"readdir": dump.Function{
+					Parameters: dump.Parameters{
+						{Name: "path", Type: dt.Prim(rtypes.T_String)},
+					},
+					Returns: dump.Parameters{
+						{Name: "tree", Type: dt.Optional(dt.Prim(rtypes.T_Instance))},
+					},
+					CanError:    true,
+					Summary:     "Libraries/fs:Fields/readdir/Summary",
+					Description: "Libraries/fs:Fields/readdir/Description",
+				},
 				"remove": dump.Function{
 					Parameters: dump.Parameters{
 						{Name: "path", Type: dt.Prim(rtypes.T_String)},
@@ -230,6 +268,16 @@ func dumpFS(s rbxmk.State) dump.Library {
 					Summary:     "Libraries/fs:Fields/write/Summary",
 					Description: "Libraries/fs:Fields/write/Description",
 				},
+				"writedir": dump.Function{
+					Parameters: dump.Parameters{
+						{Name: "path", Type: dt.Prim(rtypes.T_String)},
+						{Name: "tree", Type: dt.Prim(rtypes.T_Instance)},
+						{Name: "format", Type: dt.Optional(dt.Prim(rtypes.T_FormatSelector))},
+					},
+					CanError:    true,
+					Summary:     "Libraries/fs:Fields/writedir/Summary",
+					Description: "Libraries/fs:Fields/writedir/Description",
+				},
 			},
 			Summary:     "Libraries/fs:Summary",
 			Description: "Libraries/fs:Description",
@@ -292,11 +340,16 @@ func (s FSSource) Read(filename string, selector rtypes.FormatSelector) (v types
 		return nil, err
 	}
 	defer r.Close()
-	v, err = format.Decode(s.Global, selector, r)
+	v, err = format.Decode(s.Global, fsResolver{
+		FormatSelector: selector,
+		fs:             s,
+		dir:            filepath.Dir(filename),
+	}, r)
 	if err != nil {
 		return nil, err
 	}
-	if inst, ok := v.(*rtypes.Instance); ok {
+	if inst, ok := v.(*rtypes.Instance); ok && inst.Name() == "" {
+		// Keep the name if it was set by the format.
 		ext := s.Ext(filename)
 		if ext != "" && ext != "." {
 			ext = "." + ext
@@ -308,6 +361,31 @@ func (s FSSource) Read(filename string, selector rtypes.FormatSelector) (v types
 	return v, nil
 }
 
+// fsResolver implements rbxmk.PathResolver, resolving paths relative to a
+// directory.
+type fsResolver struct {
+	rtypes.FormatSelector
+	fs  FSSource
+	dir string
+}
+
+// ResolvePath decodes the file or directory at path, relative to r.dir. A
+// directory is decoded with ReadDir, and a file is decoded with Read, using the
+// format matching the file extension.
+func (r fsResolver) ResolvePath(path string) (types.Value, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(r.dir, filepath.FromSlash(path))
+	}
+	info, err := r.fs.FS.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return r.fs.ReadDir(path)
+	}
+	return r.fs.Read(path, rtypes.FormatSelector{})
+}
+
 // Remove removes a file or directory.
 func (s FSSource) Remove(path string, all bool) (ok bool, err error) {
 	if all {
@@ -383,3 +461,327 @@ func (s FSSource) Write(filename string, value types.Value, selector rtypes.Form
 	}
 	return nil
 }
+
+// dirScriptExts maps a script class to the file extension used to represent it
+// within a directory tree.
+var dirScriptExts = map[string]string{
+	"Script":       "server.lua",
+	"LocalScript":  "client.lua",
+	"ModuleScript": "lua",
+}
+
+// dirInitFiles lists the names of files that, when present in a directory,
+// cause the directory to be decoded as the script described by the file rather
+// than as a Folder. Files are checked in order.
+var dirInitFiles = []string{
+	"init.server.lua",
+	"init.client.lua",
+	"init.lua",
+	"init.server.luau",
+	"init.client.luau",
+	"init.luau",
+}
+
+// dirMetaFormat is the format of files that describe the metadata of an
+// instance within a directory tree. A file named "init.meta.json" describes the
+// instance of the directory, while any other file describes the sibling with
+// the same stem.
+const dirMetaFormat = "meta.json"
+
+// isDirInitFile returns whether name is one of dirInitFiles.
+func isDirInitFile(name string) bool {
+	for _, init := range dirInitFiles {
+		if name == init {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadDir decodes the directory at dirname into a tree of Instances. Returns
+// nil if the directory does not exist.
+//
+// A directory is decoded as a Folder, or as a script if the directory contains
+// one of dirInitFiles. Subdirectories are decoded recursively. Each file is
+// decoded with the format matching its extension, as long as the format can
+// decode into an Instance. Files of other formats are skipped. If the result
+// is a DataModel, as with the rbxm and rbxmx formats, then its children are
+// added instead. Files of dirMetaFormat are applied to their corresponding
+// instances.
+func (s FSSource) ReadDir(dirname string) (inst *rtypes.Instance, err error) {
+	if info, err := s.FS.Stat(dirname); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	} else if !info.IsDir() {
+		return nil, nil
+	}
+	return s.readDir(dirname)
+}
+
+func (s FSSource) readDir(dirname string) (inst *rtypes.Instance, err error) {
+	entries, err := s.FS.ReadDir(dirname)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		names[entry.Name()] = true
+	}
+	for _, init := range dirInitFiles {
+		if !names[init] {
+			continue
+		}
+		v, err := s.Read(filepath.Join(dirname, init), rtypes.FormatSelector{})
+		if err != nil {
+			return nil, err
+		}
+		inst, _ = v.(*rtypes.Instance)
+		break
+	}
+	if inst == nil {
+		inst = rtypes.NewInstance("Folder", nil)
+	}
+	inst.SetName(filepath.Base(dirname))
+
+	var metas []string
+	for _, entry := range entries {
+		path := filepath.Join(dirname, entry.Name())
+		if entry.IsDir() {
+			child, err := s.readDir(path)
+			if err != nil {
+				return nil, err
+			}
+			child.SetParent(inst)
+			continue
+		}
+		if isDirInitFile(entry.Name()) {
+			continue
+		}
+		ext := s.Ext(entry.Name())
+		if ext == "" {
+			continue
+		}
+		if ext == dirMetaFormat {
+			metas = append(metas, entry.Name())
+			continue
+		}
+		selector := rtypes.FormatSelector{Format: ext}
+		format := s.Format(ext)
+		if format.Decode == nil || format.CanDecode == nil || !format.CanDecode(s.Global, selector, rtypes.T_Instance) {
+			continue
+		}
+		v, err := s.Read(path, selector)
+		if err != nil {
+			return nil, err
+		}
+		child, ok := v.(*rtypes.Instance)
+		if !ok {
+			continue
+		}
+		if child.IsDataModel() {
+			for _, c := range child.Children() {
+				c.SetParent(inst)
+			}
+			continue
+		}
+		child.SetParent(inst)
+	}
+
+	for _, name := range metas {
+		target := inst
+		if stem := strings.TrimSuffix(name, "."+dirMetaFormat); stem != "init" {
+			if target = inst.FindFirstChild(stem, false); target == nil {
+				continue
+			}
+		}
+		selector := rtypes.FormatSelector{
+			Format:  dirMetaFormat,
+			Options: rtypes.Dictionary{"ClassName": types.String(target.ClassName)},
+		}
+		v, err := s.Read(filepath.Join(dirname, name), selector)
+		if err != nil {
+			return nil, err
+		}
+		meta, ok := v.(*rtypes.Instance)
+		if !ok {
+			continue
+		}
+		if meta.ClassName != "" && target.ClassName == "Folder" {
+			target.ClassName = meta.ClassName
+		}
+		meta.ForEachProperty(func(name string, value types.PropValue) error {
+			if name != "Name" {
+				target.Set(name, value)
+			}
+			return nil
+		})
+	}
+	return inst, nil
+}
+
+// writeMeta writes the metadata of inst to filename with the meta.json format.
+// Nothing is written if the metadata would not be needed to decode inst from
+// a directory tree. That is, if inst is a Folder or script, and has no
+// properties other than Name and Source. Returns whether the file was written.
+func (s FSSource) writeMeta(filename string, inst *rtypes.Instance) (written bool, err error) {
+	meta := rtypes.NewInstance(inst.ClassName, nil)
+	for name, value := range inst.Properties() {
+		if name != "Name" && name != "Source" {
+			meta.Set(name, value)
+		}
+	}
+	if _, ok := dirScriptExts[inst.ClassName]; ok || inst.ClassName == "Folder" {
+		if len(meta.PropertyNames()) == 0 {
+			return false, nil
+		}
+	}
+	if err := s.Write(filename, meta, rtypes.FormatSelector{Format: dirMetaFormat}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// dirScriptExt returns the extension of the file for a script of the given
+// class named stem. If a file with the Luau variant of the extension already
+// exists, then the Luau extension is returned instead, so that rewriting a
+// tree does not rename its files.
+func dirScriptExt(existing map[string]bool, stem, className string) (ext string, ok bool) {
+	if ext, ok = dirScriptExts[className]; !ok {
+		return "", false
+	}
+	if existing[stem+"."+ext+"u"] {
+		ext += "u"
+	}
+	return ext, true
+}
+
+// isDirTreeEntry returns whether the entry would be decoded by ReadDir as part
+// of a directory tree.
+func (s FSSource) isDirTreeEntry(entry fs.DirEntry) bool {
+	if entry.IsDir() || isDirInitFile(entry.Name()) {
+		return true
+	}
+	ext := s.Ext(entry.Name())
+	if ext == "" {
+		return false
+	}
+	if ext == dirMetaFormat {
+		return true
+	}
+	format := s.Format(ext)
+	if format.Decode == nil || format.CanDecode == nil {
+		return false
+	}
+	return format.CanDecode(s.Global, rtypes.FormatSelector{Format: ext}, rtypes.T_Instance)
+}
+
+// WriteDir encodes inst into a directory tree at dirname. The directory and
+// its parents are created as needed.
+//
+// The directory represents inst, with each child written according to its
+// class. A Folder is written as a directory. A script is written as a file
+// with an extension from dirScriptExts, or as a directory containing an init
+// file if the script has children. If a file with the Luau variant of the
+// extension already exists, then that extension is used instead. Any other
+// instance is written, along with its descendants, as a single file encoded by
+// selector, which defaults to the rbxmx format. The class and properties of a
+// Folder or script that would otherwise be lost are written to a file of
+// dirMetaFormat.
+//
+// Entries of an existing directory that would be decoded by ReadDir, but that
+// were not written, are removed, so that reading the directory produces the
+// same tree. Other entries are left alone.
+//
+// An error is returned if two children have the same name, or if a name cannot
+// be used as a file name.
+func (s FSSource) WriteDir(dirname string, inst *rtypes.Instance, selector rtypes.FormatSelector) error {
+	if selector.Format == "" {
+		selector.Format = "rbxmx"
+	}
+	if err := s.FS.MkdirAll(dirname, 0755); err != nil {
+		return err
+	}
+	entries, err := s.FS.ReadDir(dirname)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		existing[entry.Name()] = true
+	}
+	written := map[string]bool{}
+
+	if ext, ok := dirScriptExt(existing, "init", inst.ClassName); ok {
+		err := s.Write(filepath.Join(dirname, "init."+ext), inst, rtypes.FormatSelector{Format: ext})
+		if err != nil {
+			return err
+		}
+		written["init."+ext] = true
+	}
+	if ok, err := s.writeMeta(filepath.Join(dirname, "init."+dirMetaFormat), inst); err != nil {
+		return err
+	} else if ok {
+		written["init."+dirMetaFormat] = true
+	}
+	names := map[string]bool{}
+	for _, child := range inst.Children() {
+		name := child.Name()
+		switch name {
+		case "", ".", "..":
+			return fmt.Errorf("%s: invalid file name %q", child.GetFullName(), name)
+		}
+		for i := 0; i < len(name); i++ {
+			if os.IsPathSeparator(name[i]) {
+				return fmt.Errorf("%s: invalid file name %q", child.GetFullName(), name)
+			}
+		}
+		if names[name] {
+			return fmt.Errorf("%s: duplicate name %q", inst.GetFullName(), name)
+		}
+		names[name] = true
+
+		path := filepath.Join(dirname, name)
+		if child.ClassName == "Folder" {
+			if err := s.WriteDir(path, child, selector); err != nil {
+				return err
+			}
+			written[name] = true
+			continue
+		}
+		if ext, ok := dirScriptExt(existing, name, child.ClassName); ok {
+			if len(child.Children()) > 0 {
+				if err := s.WriteDir(path, child, selector); err != nil {
+					return err
+				}
+				written[name] = true
+				continue
+			}
+			if err := s.Write(path+"."+ext, child, rtypes.FormatSelector{Format: ext}); err != nil {
+				return err
+			}
+			written[name+"."+ext] = true
+			if ok, err := s.writeMeta(path+"."+dirMetaFormat, child); err != nil {
+				return err
+			} else if ok {
+				written[name+"."+dirMetaFormat] = true
+			}
+			continue
+		}
+		if err := s.Write(path+"."+selector.Format, child, selector); err != nil {
+			return err
+		}
+		written[name+"."+selector.Format] = true
+	}
+
+	for _, entry := range entries {
+		if written[entry.Name()] || !s.isDirTreeEntry(entry) {
+			continue
+		}
+		if err := s.FS.RemoveAll(filepath.Join(dirname, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
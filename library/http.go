@@ -7,6 +7,7 @@ import (
 	"github.com/anaminus/rbxmk/dump/dt"
 	"github.com/anaminus/rbxmk/reflect"
 	"github.com/anaminus/rbxmk/rtypes"
+	"github.com/robloxapi/types"
 )
 
 func init() { register(HTTP) }
@@ -18,19 +19,65 @@ var HTTP = rbxmk.Library{
 	Open:     openHTTP,
 	Dump:     dumpHTTP,
 	Types: []func() rbxmk.Reflector{
+		reflect.Array,
 		reflect.HttpHeaders,
 		reflect.HttpOptions,
 		reflect.HttpRequest,
 		reflect.HttpResponse,
+		reflect.Int,
 	},
 }
 
 func openHTTP(s rbxmk.State) *lua.LTable {
-	lib := s.L.CreateTable(0, 1)
+	lib := s.L.CreateTable(0, 3)
+	lib.RawSetString("all", s.WrapFunc(httpAll))
+	lib.RawSetString("any", s.WrapFunc(httpAny))
 	lib.RawSetString("request", s.WrapFunc(httpRequest))
 	return lib
 }
 
+// pullRequests pulls an array of HttpRequests from argument n.
+func pullRequests(s rbxmk.State, n int) []*rbxmk.HttpRequest {
+	array := s.PullArrayOf(n, rtypes.T_HttpRequest)
+	requests := make([]*rbxmk.HttpRequest, len(array))
+	for i, v := range array {
+		requests[i] = v.(*rbxmk.HttpRequest)
+	}
+	return requests
+}
+
+// progressFuncs returns the progress function of each request, called within
+// the state.
+func progressFuncs(s rbxmk.State, requests []*rbxmk.HttpRequest) []func(rbxmk.HttpProgress) {
+	progress := make([]func(rbxmk.HttpProgress), len(requests))
+	for i, request := range requests {
+		progress[i] = request.LuaProgress(s.L)
+	}
+	return progress
+}
+
+func httpAll(s rbxmk.State) int {
+	requests := pullRequests(s, 1)
+	resps, i, err := rbxmk.ResolveAllProgress(requests, progressFuncs(s, requests))
+	if err != nil {
+		return s.RaiseError("request #%d: %s", i+1, err)
+	}
+	array := make(rtypes.Array, len(resps))
+	for i, resp := range resps {
+		array[i] = *resp
+	}
+	return s.Push(array)
+}
+
+func httpAny(s rbxmk.State) int {
+	requests := pullRequests(s, 1)
+	resp, i, err := rbxmk.ResolveAnyProgress(requests, progressFuncs(s, requests))
+	if err != nil {
+		return s.RaiseError("%s", err)
+	}
+	return s.PushTuple(*resp, types.Int(i+1))
+}
+
 func httpRequest(s rbxmk.State) int {
 	options := s.Pull(1, rtypes.T_HttpOptions).(rtypes.HttpOptions)
 	request, err := rbxmk.BeginHttpRequest(s.World, options)
@@ -44,6 +91,29 @@ func dumpHTTP(s rbxmk.State) dump.Library {
 	lib := dump.Library{
 		Struct: dump.Struct{
 			Fields: dump.Fields{
+				"all": dump.Function{
+					Parameters: dump.Parameters{
+						{Name: "requests", Type: dt.Array(dt.Prim(rtypes.T_HttpRequest))},
+					},
+					Returns: dump.Parameters{
+						{Name: "resps", Type: dt.Array(dt.Prim(rtypes.T_HttpResponse))},
+					},
+					CanError:    true,
+					Summary:     "Libraries/http:Fields/all/Summary",
+					Description: "Libraries/http:Fields/all/Description",
+				},
+				"any": dump.Function{
+					Parameters: dump.Parameters{
+						{Name: "requests", Type: dt.Array(dt.Prim(rtypes.T_HttpRequest))},
+					},
+					Returns: dump.Parameters{
+						{Name: "resp", Type: dt.Prim(rtypes.T_HttpResponse)},
+						{Name: "index", Type: dt.Prim(rtypes.T_Int)},
+					},
+					CanError:    true,
+					Summary:     "Libraries/http:Fields/any/Summary",
+					Description: "Libraries/http:Fields/any/Description",
+				},
 				"request": dump.Function{
 					Parameters: dump.Parameters{
 						{Name: "options", Type: dt.Prim(rtypes.T_HttpOptions)},
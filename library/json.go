@@ -182,6 +182,7 @@ func dumpJSON(s rbxmk.State) dump.Library {
 				"string": dump.Function{
 					Parameters: dump.Parameters{
 						{Name: "value", Type: dt.Prim(rtypes.T_JsonValue)},
+						{Name: "indent", Type: dt.Optional(dt.Prim(rtypes.T_String))},
 					},
 					Returns: dump.Parameters{
 						{Type: dt.Prim(rtypes.T_String)},
@@ -139,6 +139,7 @@ func (s RbxAssetIdSource) Read(options rtypes.RbxAssetOptions) (body types.Value
 		Method:         "GET",
 		ResponseFormat: options.Format,
 		Headers:        rtypes.HttpHeaders{}.AppendCookies(options.Cookies),
+		Progress:       options.Progress,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("get asset content: %w", err)
@@ -157,6 +158,7 @@ func (s RbxAssetIdSource) Write(options rtypes.RbxAssetOptions) error {
 		RequestFormat: options.Format,
 		Headers:       rtypes.HttpHeaders{}.AppendCookies(options.Cookies),
 		Body:          options.Body,
+		Progress:      options.Progress,
 	})
 	return err
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"path/filepath"
 	"strings"
+	"time"
 
 	lua "github.com/anaminus/gopher-lua"
 	"github.com/anaminus/rbxmk"
@@ -25,13 +26,16 @@ var RBXMK = rbxmk.Library{
 	Types: []func() rbxmk.Reflector{
 		reflect.AttrConfig,
 		reflect.Desc,
+		reflect.Double,
 		reflect.Enums,
 		reflect.FormatSelector,
 		reflect.Instance,
+		reflect.Int,
 		reflect.Nil,
 		reflect.String,
 		reflect.Symbol,
 		reflect.Table,
+		reflect.TagConfig,
 	},
 }
 
@@ -255,6 +259,20 @@ func rbxmkOpIndex(s rbxmk.State) int {
 			return s.Push(rtypes.Nil)
 		}
 		return s.Push(attrcfg)
+	case "globalHttpConcurrency":
+		return s.Push(types.Int(s.Client.Concurrency()))
+	case "globalHttpRetries":
+		return s.Push(types.Int(s.Client.RetryPolicy().Retries))
+	case "globalHttpTimeout":
+		return s.Push(types.Double(s.Client.Timeout().Seconds()))
+	case "globalHttpRetryDelay":
+		return s.Push(types.Double(s.Client.RetryPolicy().Delay.Seconds()))
+	case "globalTagConfig":
+		tagcfg := s.TagConfig.Of(nil)
+		if tagcfg == nil {
+			return s.Push(rtypes.Nil)
+		}
+		return s.Push(tagcfg)
 	default:
 		return s.RaiseError("unknown field %q", field)
 	}
@@ -276,6 +294,41 @@ func rbxmkOpNewindex(s rbxmk.State) int {
 		}
 		s.AttrConfig = attrcfg
 		return 0
+	case "globalHttpConcurrency":
+		n := int(s.Pull(3, rtypes.T_Int).(types.Int))
+		if n < 0 {
+			return s.ArgError(3, "concurrency cannot be negative")
+		}
+		s.Client.SetConcurrency(n)
+		return 0
+	case "globalHttpRetries":
+		retries := int(s.Pull(3, rtypes.T_Int).(types.Int))
+		if retries < 0 {
+			return s.ArgError(3, "retries cannot be negative")
+		}
+		policy := s.Client.RetryPolicy()
+		policy.Retries = retries
+		s.Client.SetRetryPolicy(policy)
+		return 0
+	case "globalHttpRetryDelay":
+		delay := float64(s.Pull(3, rtypes.T_Double).(types.Double))
+		if delay < 0 {
+			return s.ArgError(3, "delay cannot be negative")
+		}
+		policy := s.Client.RetryPolicy()
+		policy.Delay = time.Duration(delay * float64(time.Second))
+		s.Client.SetRetryPolicy(policy)
+		return 0
+	case "globalHttpTimeout":
+		timeout := float64(s.Pull(3, rtypes.T_Double).(types.Double))
+		if timeout < 0 {
+			return s.ArgError(3, "timeout cannot be negative")
+		}
+		s.Client.SetTimeout(time.Duration(timeout * float64(time.Second)))
+		return 0
+	case "globalTagConfig":
+		s.TagConfig, _ = s.PullOpt(3, nil, rtypes.T_TagConfig).(*rtypes.TagConfig)
+		return 0
 	default:
 		return s.RaiseError("unknown field %q", field)
 	}
@@ -301,7 +354,7 @@ func dumpRBXMK(s rbxmk.State) dump.Library {
 				"Enum": enumsDump,
 				"decodeFormat": dump.Function{
 					Parameters: dump.Parameters{
-						{Name: "format", Type: dt.Prim(rtypes.T_String)},
+						{Name: "format", Type: dt.Prim(rtypes.T_FormatSelector)},
 						{Name: "bytes", Type: dt.Prim(rtypes.T_BinaryString)},
 					},
 					Returns: dump.Parameters{
@@ -313,7 +366,7 @@ func dumpRBXMK(s rbxmk.State) dump.Library {
 				},
 				"encodeFormat": dump.Function{
 					Parameters: dump.Parameters{
-						{Name: "format", Type: dt.Prim(rtypes.T_String)},
+						{Name: "format", Type: dt.Prim(rtypes.T_FormatSelector)},
 						{Name: "value", Type: dt.Prim(rtypes.T_Any)},
 					},
 					Returns: dump.Parameters{
@@ -325,7 +378,7 @@ func dumpRBXMK(s rbxmk.State) dump.Library {
 				},
 				"formatCanDecode": dump.Function{
 					Parameters: dump.Parameters{
-						{Name: "format", Type: dt.Prim(rtypes.T_String)},
+						{Name: "format", Type: dt.Prim(rtypes.T_FormatSelector)},
 						{Name: "type", Type: dt.Prim(rtypes.T_String)},
 					},
 					Returns: dump.Parameters{
@@ -362,6 +415,31 @@ func dumpRBXMK(s rbxmk.State) dump.Library {
 					Summary:     "Libraries/rbxmk:Fields/globalDesc/Summary",
 					Description: "Libraries/rbxmk:Fields/globalDesc/Description",
 				},
+				"globalHttpConcurrency": dump.Property{
+					ValueType:   dt.Prim(rtypes.T_Int),
+					Summary:     "Libraries/rbxmk:Fields/globalHttpConcurrency/Summary",
+					Description: "Libraries/rbxmk:Fields/globalHttpConcurrency/Description",
+				},
+				"globalHttpRetries": dump.Property{
+					ValueType:   dt.Prim(rtypes.T_Int),
+					Summary:     "Libraries/rbxmk:Fields/globalHttpRetries/Summary",
+					Description: "Libraries/rbxmk:Fields/globalHttpRetries/Description",
+				},
+				"globalHttpRetryDelay": dump.Property{
+					ValueType:   dt.Prim(rtypes.T_Double),
+					Summary:     "Libraries/rbxmk:Fields/globalHttpRetryDelay/Summary",
+					Description: "Libraries/rbxmk:Fields/globalHttpRetryDelay/Description",
+				},
+				"globalHttpTimeout": dump.Property{
+					ValueType:   dt.Prim(rtypes.T_Double),
+					Summary:     "Libraries/rbxmk:Fields/globalHttpTimeout/Summary",
+					Description: "Libraries/rbxmk:Fields/globalHttpTimeout/Description",
+				},
+				"globalTagConfig": dump.Property{
+					ValueType:   dt.Optional(dt.Prim(rtypes.T_TagConfig)),
+					Summary:     "Libraries/rbxmk:Fields/globalTagConfig/Summary",
+					Description: "Libraries/rbxmk:Fields/globalTagConfig/Description",
+				},
 				"loadFile": dump.Function{
 					Parameters: dump.Parameters{
 						{Name: "path", Type: dt.Prim(rtypes.T_String)},
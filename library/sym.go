@@ -23,7 +23,7 @@ var Sym = rbxmk.Library{
 }
 
 func openSym(s rbxmk.State) *lua.LTable {
-	lib := s.L.CreateTable(0, 7)
+	lib := s.L.CreateTable(0, 10)
 	lib.RawSetString(rtypes.T_AttrConfig, s.UserDataOf(rtypes.Symbol{Name: rtypes.T_AttrConfig}, rtypes.T_Symbol))
 	lib.RawSetString(rtypes.T_Desc, s.UserDataOf(rtypes.Symbol{Name: rtypes.T_Desc}, rtypes.T_Symbol))
 	lib.RawSetString("IsService", s.UserDataOf(rtypes.Symbol{Name: "IsService"}, rtypes.T_Symbol))
@@ -31,7 +31,9 @@ func openSym(s rbxmk.State) *lua.LTable {
 	lib.RawSetString("Properties", s.UserDataOf(rtypes.Symbol{Name: "Properties"}, rtypes.T_Symbol))
 	lib.RawSetString("Raw"+rtypes.T_AttrConfig, s.UserDataOf(rtypes.Symbol{Name: "Raw" + rtypes.T_AttrConfig}, rtypes.T_Symbol))
 	lib.RawSetString("Raw"+rtypes.T_Desc, s.UserDataOf(rtypes.Symbol{Name: "Raw" + rtypes.T_Desc}, rtypes.T_Symbol))
+	lib.RawSetString("Raw"+rtypes.T_TagConfig, s.UserDataOf(rtypes.Symbol{Name: "Raw" + rtypes.T_TagConfig}, rtypes.T_Symbol))
 	lib.RawSetString("Reference", s.UserDataOf(rtypes.Symbol{Name: "Reference"}, rtypes.T_Symbol))
+	lib.RawSetString(rtypes.T_TagConfig, s.UserDataOf(rtypes.Symbol{Name: rtypes.T_TagConfig}, rtypes.T_Symbol))
 	return lib
 }
 
@@ -81,12 +83,24 @@ func dumpSym(s rbxmk.State) dump.Library {
 					Summary:     "Libraries/sym:Fields/RawDesc/Summary",
 					Description: "Libraries/sym:Fields/RawDesc/Description",
 				},
+				"Raw" + rtypes.T_TagConfig: dump.Property{
+					ValueType:   dt.Prim(rtypes.T_Symbol),
+					ReadOnly:    true,
+					Summary:     "Libraries/sym:Fields/RawTagConfig/Summary",
+					Description: "Libraries/sym:Fields/RawTagConfig/Description",
+				},
 				"Reference": dump.Property{
 					ValueType:   dt.Prim(rtypes.T_Symbol),
 					ReadOnly:    true,
 					Summary:     "Libraries/sym:Fields/Reference/Summary",
 					Description: "Libraries/sym:Fields/Reference/Description",
 				},
+				rtypes.T_TagConfig: dump.Property{
+					ValueType:   dt.Prim(rtypes.T_Symbol),
+					ReadOnly:    true,
+					Summary:     "Libraries/sym:Fields/TagConfig/Summary",
+					Description: "Libraries/sym:Fields/TagConfig/Description",
+				},
 			},
 			Summary:     "Libraries/sym:Summary",
 			Description: "Libraries/sym:Description",
@@ -0,0 +1,652 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/anaminus/cobra"
+	"github.com/anaminus/gopher-lua/ast"
+	"github.com/anaminus/gopher-lua/parse"
+	"github.com/anaminus/pflag"
+	"github.com/anaminus/rbxmk/dump"
+	"github.com/anaminus/rbxmk/dump/dt"
+)
+
+func init() {
+	var c CheckCommand
+	var cmd = Register.NewCommand(dump.Command{
+		Arguments:   "Commands/check:Arguments",
+		Summary:     "Commands/check:Summary",
+		Description: "Commands/check:Description",
+	}, &cobra.Command{
+		Use:  "check",
+		Args: cobra.MinimumNArgs(1),
+		RunE: c.Run,
+	})
+	c.SetFlags(cmd.Flags())
+	Program.AddCommand(cmd)
+}
+
+type CheckCommand struct {
+	DumpFlags
+}
+
+func (c *CheckCommand) SetFlags(flags *pflag.FlagSet) {
+	c.DumpFlags.SetFlags(flags)
+}
+
+func (c *CheckCommand) Run(cmd *cobra.Command, args []string) error {
+	root, err := GenerateDump(WorldOpt{
+		WorldFlags:       WorldFlags{Debug: false},
+		IncludeLibraries: c.DumpFlags.Libraries(),
+		ExcludeRoots:     true,
+	})
+	if err != nil {
+		return err
+	}
+	var problems []checkProblem
+	for _, file := range args {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("read file: %w", err)
+		}
+		problems = append(problems, checkScript(root, shortenPath(file), b)...)
+	}
+	for _, problem := range problems {
+		cmd.Println(problem)
+	}
+	switch n := len(problems); n {
+	case 0:
+		return nil
+	case 1:
+		return fmt.Errorf("found 1 problem")
+	default:
+		return fmt.Errorf("found %d problems", n)
+	}
+}
+
+// checkProblem is a problem found within a script.
+type checkProblem struct {
+	File    string
+	Line    int
+	Message string
+}
+
+func (p checkProblem) String() string {
+	return fmt.Sprintf("%s:%d: %s", p.File, p.Line, p.Message)
+}
+
+// checkScript parses the script named file with the given source, and returns
+// the problems found by checking its use of the API described by root.
+func checkScript(root dump.Root, file string, source []byte) []checkProblem {
+	chunk, err := parse.Parse(bytes.NewReader(source), file)
+	if err != nil {
+		problem := checkProblem{File: file, Message: strings.TrimSpace(err.Error())}
+		if perr, ok := err.(*parse.Error); ok {
+			problem.Line = perr.Pos.Line
+			problem.Message = perr.Message
+			if perr.Pos.Line == parse.EOF {
+				problem.Line = bytes.Count(bytes.TrimRight(source, "\n"), []byte("\n")) + 1
+			} else if perr.Token != "" {
+				problem.Message += fmt.Sprintf(" near '%s'", perr.Token)
+			}
+		}
+		return []checkProblem{problem}
+	}
+	c := &scriptChecker{
+		root:     root,
+		file:     file,
+		assigned: map[string]bool{},
+	}
+	c.collectAssigned(chunk)
+	c.block(chunk)
+	sort.SliceStable(c.problems, func(i, j int) bool {
+		return c.problems[i].Line < c.problems[j].Line
+	})
+	return c.problems
+}
+
+// scriptChecker verifies the use of an API within a parsed script. Only
+// globals of the environment are checked; values that pass through locals are
+// not tracked.
+type scriptChecker struct {
+	root     dump.Root
+	file     string
+	problems []checkProblem
+
+	// scopes contains the names of locals of each enclosing block.
+	scopes []map[string]bool
+	// assigned contains the paths of globals that are assigned anywhere in
+	// the script, which are excluded from checks.
+	assigned map[string]bool
+}
+
+func (c *scriptChecker) report(node ast.PositionHolder, format string, args ...interface{}) {
+	c.problems = append(c.problems, checkProblem{
+		File:    c.file,
+		Line:    node.Line(),
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// isLocal returns whether name refers to a local variable.
+func (c *scriptChecker) isLocal(name string) bool {
+	for i := len(c.scopes) - 1; i >= 0; i-- {
+		if c.scopes[i][name] {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *scriptChecker) declare(names ...string) {
+	scope := c.scopes[len(c.scopes)-1]
+	for _, name := range names {
+		scope[name] = true
+	}
+}
+
+// path returns the names of an expression that indexes a global with
+// constant keys, such as fs.read. Returns nil if expr is not such an
+// expression.
+func (c *scriptChecker) path(expr ast.Expr) []string {
+	switch expr := expr.(type) {
+	case *ast.IdentExpr:
+		if c.isLocal(expr.Value) {
+			return nil
+		}
+		return []string{expr.Value}
+	case *ast.AttrGetExpr:
+		key, ok := expr.Key.(*ast.StringExpr)
+		if !ok {
+			return nil
+		}
+		if path := c.path(expr.Object); path != nil {
+			return append(path, key.Value)
+		}
+	}
+	return nil
+}
+
+// collectAssigned adds each global path that is the target of an assignment
+// within stmts, recursively.
+func (c *scriptChecker) collectAssigned(stmts []ast.Stmt) {
+	var exprs func(exprs []ast.Expr)
+	exprs = func(list []ast.Expr) {
+		for _, expr := range list {
+			if expr, ok := expr.(*ast.FunctionExpr); ok {
+				c.collectAssigned(expr.Stmts)
+			}
+		}
+	}
+	for _, stmt := range stmts {
+		switch stmt := stmt.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range stmt.Lhs {
+				// Locals are not known at this stage, so a local that shadows a
+				// global also excludes the global.
+				if path := c.path(lhs); path != nil {
+					c.assigned[strings.Join(path, ".")] = true
+				}
+			}
+			exprs(stmt.Rhs)
+		case *ast.LocalAssignStmt:
+			exprs(stmt.Exprs)
+		case *ast.FuncDefStmt:
+			if path := c.path(stmt.Name.Func); path != nil {
+				c.assigned[strings.Join(path, ".")] = true
+			}
+			c.collectAssigned(stmt.Func.Stmts)
+		case *ast.DoBlockStmt:
+			c.collectAssigned(stmt.Stmts)
+		case *ast.WhileStmt:
+			c.collectAssigned(stmt.Stmts)
+		case *ast.RepeatStmt:
+			c.collectAssigned(stmt.Stmts)
+		case *ast.IfStmt:
+			c.collectAssigned(stmt.Then)
+			c.collectAssigned(stmt.Else)
+		case *ast.NumberForStmt:
+			c.collectAssigned(stmt.Stmts)
+		case *ast.GenericForStmt:
+			c.collectAssigned(stmt.Stmts)
+		}
+	}
+}
+
+// isAssigned returns whether path or any of its prefixes is assigned by the
+// script.
+func (c *scriptChecker) isAssigned(path []string) bool {
+	for i := range path {
+		if c.assigned[strings.Join(path[:i+1], ".")] {
+			return true
+		}
+	}
+	return false
+}
+
+// lookup returns the environment value located at path.
+func (c *scriptChecker) lookup(path []string) *dump.EnvRef {
+	env := c.root.Environment
+	for _, name := range path {
+		if env == nil {
+			return nil
+		}
+		env = env.Fields[name]
+	}
+	return env
+}
+
+// block checks a list of statements within a new scope.
+func (c *scriptChecker) block(stmts []ast.Stmt, locals ...string) {
+	c.scopes = append(c.scopes, map[string]bool{})
+	c.declare(locals...)
+	for _, stmt := range stmts {
+		c.stmt(stmt)
+	}
+	c.scopes = c.scopes[:len(c.scopes)-1]
+}
+
+func (c *scriptChecker) stmt(stmt ast.Stmt) {
+	switch stmt := stmt.(type) {
+	case *ast.AssignStmt:
+		for _, expr := range stmt.Rhs {
+			c.expr(expr)
+		}
+		for _, lhs := range stmt.Lhs {
+			if lhs, ok := lhs.(*ast.AttrGetExpr); ok {
+				// Assigned field is not checked.
+				c.expr(lhs.Object)
+				c.expr(lhs.Key)
+			}
+		}
+	case *ast.LocalAssignStmt:
+		for _, expr := range stmt.Exprs {
+			c.expr(expr)
+		}
+		c.declare(stmt.Names...)
+	case *ast.FuncCallStmt:
+		c.expr(stmt.Expr)
+	case *ast.DoBlockStmt:
+		c.block(stmt.Stmts)
+	case *ast.WhileStmt:
+		c.expr(stmt.Condition)
+		c.block(stmt.Stmts)
+	case *ast.RepeatStmt:
+		// The condition is within the scope of the block.
+		c.scopes = append(c.scopes, map[string]bool{})
+		for _, s := range stmt.Stmts {
+			c.stmt(s)
+		}
+		c.expr(stmt.Condition)
+		c.scopes = c.scopes[:len(c.scopes)-1]
+	case *ast.IfStmt:
+		c.expr(stmt.Condition)
+		c.block(stmt.Then)
+		c.block(stmt.Else)
+	case *ast.NumberForStmt:
+		c.expr(stmt.Init)
+		c.expr(stmt.Limit)
+		c.expr(stmt.Step)
+		c.block(stmt.Stmts, stmt.Name)
+	case *ast.GenericForStmt:
+		for _, expr := range stmt.Exprs {
+			c.expr(expr)
+		}
+		c.block(stmt.Stmts, stmt.Names...)
+	case *ast.FuncDefStmt:
+		if stmt.Name.Method != "" {
+			c.expr(stmt.Name.Receiver)
+		} else if ident, ok := stmt.Name.Func.(*ast.IdentExpr); ok && c.isLocal(ident.Value) {
+			// Local function.
+		} else if attr, ok := stmt.Name.Func.(*ast.AttrGetExpr); ok {
+			c.expr(attr.Object)
+		}
+		c.function(stmt.Func, stmt.Name.Method != "")
+	case *ast.ReturnStmt:
+		for _, expr := range stmt.Exprs {
+			c.expr(expr)
+		}
+	}
+}
+
+// function checks the body of a function. method is whether the function
+// receives an implicit self parameter.
+func (c *scriptChecker) function(fn *ast.FunctionExpr, method bool) {
+	var params []string
+	if method {
+		params = append(params, "self")
+	}
+	if fn.ParList != nil {
+		params = append(params, fn.ParList.Names...)
+	}
+	c.block(fn.Stmts, params...)
+}
+
+func (c *scriptChecker) expr(expr ast.Expr) {
+	switch expr := expr.(type) {
+	case nil:
+	case *ast.AttrGetExpr:
+		c.expr(expr.Object)
+		c.expr(expr.Key)
+		c.field(expr)
+	case *ast.TableExpr:
+		for _, field := range expr.Fields {
+			c.expr(field.Key)
+			c.expr(field.Value)
+		}
+	case *ast.FuncCallExpr:
+		c.expr(expr.Func)
+		c.expr(expr.Receiver)
+		for _, arg := range expr.Args {
+			c.expr(arg)
+		}
+		c.call(expr)
+	case *ast.LogicalOpExpr:
+		c.expr(expr.Lhs)
+		c.expr(expr.Rhs)
+	case *ast.RelationalOpExpr:
+		c.expr(expr.Lhs)
+		c.expr(expr.Rhs)
+	case *ast.StringConcatOpExpr:
+		c.expr(expr.Lhs)
+		c.expr(expr.Rhs)
+	case *ast.ArithmeticOpExpr:
+		c.expr(expr.Lhs)
+		c.expr(expr.Rhs)
+	case *ast.UnaryMinusOpExpr:
+		c.expr(expr.Expr)
+	case *ast.UnaryNotOpExpr:
+		c.expr(expr.Expr)
+	case *ast.UnaryLenOpExpr:
+		c.expr(expr.Expr)
+	case *ast.FunctionExpr:
+		c.function(expr, false)
+	}
+}
+
+// field checks that an indexed field of a library exists.
+func (c *scriptChecker) field(expr *ast.AttrGetExpr) {
+	path := c.path(expr)
+	if path == nil || c.isAssigned(path) {
+		return
+	}
+	parent := c.lookup(path[:len(path)-1])
+	if parent == nil || len(parent.Path) > 0 || parent.Fields == nil {
+		// Not a table of the environment.
+		return
+	}
+	if _, ok := parent.Fields[path[len(path)-1]]; !ok {
+		c.report(expr, "unknown field %s in %s", path[len(path)-1], strings.Join(path[:len(path)-1], "."))
+	}
+}
+
+// call checks the arguments of a call to a function of the environment.
+func (c *scriptChecker) call(expr *ast.FuncCallExpr) {
+	if expr.Method != "" {
+		// The type of the receiver is unknown.
+		return
+	}
+	path := c.path(expr.Func)
+	if path == nil || c.isAssigned(path) {
+		return
+	}
+	env := c.lookup(path)
+	if env == nil || len(env.Path) == 0 {
+		return
+	}
+	var fns dump.MultiFunction
+	switch v := c.root.Resolve(env.Path...).(type) {
+	case dump.Function:
+		fns = dump.MultiFunction{v}
+	case dump.MultiFunction:
+		fns = v
+	default:
+		return
+	}
+	name := strings.Join(path, ".")
+	var problems []string
+	counted := 0
+	for _, fn := range fns {
+		count, p := c.checkArgs(name, fn.Parameters, expr.Args)
+		if count == "" && len(p) == 0 {
+			return
+		}
+		if count == "" {
+			// Prefer the problems of a signature that receives the number of
+			// arguments.
+			if counted == 0 {
+				problems = p
+			}
+			counted++
+		} else if len(fns) == 1 {
+			problems = append([]string{count}, p...)
+		}
+	}
+	switch {
+	case len(fns) == 1 || counted == 1:
+		for _, p := range problems {
+			c.report(expr, "%s", p)
+		}
+	case counted == 0:
+		c.report(expr, "no signature of %s receives %d arguments", name, len(expr.Args))
+	default:
+		c.report(expr, "no signature of %s matches the arguments: %s", name, problems[0])
+	}
+}
+
+// isOpenArg returns whether expr can produce any number of values.
+func isOpenArg(expr ast.Expr) bool {
+	switch expr.(type) {
+	case *ast.FuncCallExpr, *ast.Comma3Expr:
+		return true
+	}
+	return false
+}
+
+// isVariadic returns whether p receives the remaining arguments of a call.
+func isVariadic(p dump.Parameter) bool {
+	return p.Name == "..."
+}
+
+// isOptional returns whether a parameter of type t may be omitted.
+func isOptional(t dt.Type) bool {
+	switch k := t.Kind.(type) {
+	case dt.KindOptional:
+		return true
+	case dt.KindPrim:
+		return k == "any" || k == "nil" || k == "Variant" || k == "Tuple"
+	case dt.KindOr:
+		for _, t := range k {
+			if isOptional(t) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkArgs returns the problems of passing args to a function that receives
+// params. count is the problem with the number of arguments, if any.
+func (c *scriptChecker) checkArgs(name string, params dump.Parameters, args []ast.Expr) (count string, problems []string) {
+	open := len(args) > 0 && isOpenArg(args[len(args)-1])
+	fixed := len(args)
+	if open {
+		fixed--
+	}
+	variadic := len(params) > 0 && isVariadic(params[len(params)-1])
+	max := len(params)
+	if variadic {
+		max = -1
+	}
+	required := 0
+	for i, p := range params {
+		if !isVariadic(p) && !isOptional(p.Type) {
+			required = i + 1
+		}
+	}
+	switch {
+	case max >= 0 && fixed > max:
+		count = fmt.Sprintf("too many arguments to %s: expected at most %d, got %d", name, max, fixed)
+	case !open && fixed < required:
+		count = fmt.Sprintf("not enough arguments to %s: expected at least %d, got %d", name, required, fixed)
+	}
+	for i := 0; i < fixed; i++ {
+		var p dump.Parameter
+		switch {
+		case variadic && i >= len(params)-1:
+			p = params[len(params)-1]
+		case i < len(params):
+			p = params[i]
+		default:
+			continue
+		}
+		kind := literalKind(args[i])
+		if kind == "" {
+			continue
+		}
+		if !c.accepts(p.Type, kind, map[string]bool{}) {
+			problems = append(problems, fmt.Sprintf("argument #%d to %s: expected %s, got %s", i+1, name, p.Type, kind))
+			continue
+		}
+		if isFormatSelector(p.Type) {
+			problems = append(problems, c.checkFormatSelector(args[i])...)
+		}
+	}
+	return count, problems
+}
+
+// literalKind returns the Lua type of a literal expression, or an empty string
+// if expr is not a literal.
+func literalKind(expr ast.Expr) string {
+	switch expr.(type) {
+	case *ast.NilExpr:
+		return "nil"
+	case *ast.TrueExpr, *ast.FalseExpr:
+		return "boolean"
+	case *ast.NumberExpr:
+		return "number"
+	case *ast.StringExpr:
+		return "string"
+	case *ast.TableExpr:
+		return "table"
+	case *ast.FunctionExpr:
+		return "function"
+	}
+	return ""
+}
+
+// checkPrimKinds maps a primitive type to the Lua type of the values it
+// accepts.
+var checkPrimKinds = map[string]string{
+	"nil":             "nil",
+	"bool":            "boolean",
+	"boolean":         "boolean",
+	"number":          "number",
+	"float":           "number",
+	"double":          "number",
+	"int":             "number",
+	"int64":           "number",
+	"token":           "number",
+	"string":          "string",
+	"BinaryString":    "string",
+	"ProtectedString": "string",
+	"Content":         "string",
+	"SharedString":    "string",
+	"table":           "table",
+	"Array":           "table",
+	"Dictionary":      "table",
+	"function":        "function",
+}
+
+// accepts returns whether a value of the Lua type kind may be passed as type t.
+// Types that are not well known are assumed to accept any value. visited
+// guards against recursive underlying types.
+func (c *scriptChecker) accepts(t dt.Type, kind string, visited map[string]bool) bool {
+	switch k := t.Kind.(type) {
+	case dt.KindPrim:
+		if want, ok := checkPrimKinds[string(k)]; ok {
+			return want == kind
+		}
+		if def, ok := c.root.Types[string(k)]; ok && def.Underlying != nil && !visited[string(k)] {
+			visited[string(k)] = true
+			return c.accepts(*def.Underlying, kind, visited)
+		}
+		return true
+	case dt.KindOptional:
+		return kind == "nil" || c.accepts(k.Type, kind, visited)
+	case dt.KindGroup:
+		return c.accepts(k.Type, kind, visited)
+	case dt.KindOr:
+		for _, t := range k {
+			if c.accepts(t, kind, visited) {
+				return true
+			}
+		}
+		return false
+	case dt.KindArray, dt.KindDictionary, dt.KindMap, dt.KindStruct, dt.KindTable:
+		return kind == "table"
+	case dt.KindFunction, dt.KindMultiFunctionType:
+		return kind == "function"
+	}
+	return true
+}
+
+// isFormatSelector returns whether t is a FormatSelector, or an optional
+// FormatSelector.
+func isFormatSelector(t dt.Type) bool {
+	switch k := t.Kind.(type) {
+	case dt.KindPrim:
+		return k == "FormatSelector"
+	case dt.KindOptional:
+		return isFormatSelector(k.Type)
+	}
+	return false
+}
+
+// checkFormatSelector returns the problems of a literal passed as a
+// FormatSelector: an unknown format name, or an option not supported by the
+// format.
+func (c *scriptChecker) checkFormatSelector(expr ast.Expr) []string {
+	switch expr := expr.(type) {
+	case *ast.StringExpr:
+		if _, ok := c.format(expr.Value); !ok {
+			return []string{fmt.Sprintf("unknown format %q", expr.Value)}
+		}
+	case *ast.TableExpr:
+		var name *ast.StringExpr
+		for _, field := range expr.Fields {
+			if key, ok := field.Key.(*ast.StringExpr); ok && key.Value == "Format" {
+				name, _ = field.Value.(*ast.StringExpr)
+			}
+		}
+		if name == nil {
+			return nil
+		}
+		format, ok := c.format(name.Value)
+		if !ok {
+			return []string{fmt.Sprintf("unknown format %q", name.Value)}
+		}
+		var problems []string
+		for _, field := range expr.Fields {
+			key, ok := field.Key.(*ast.StringExpr)
+			if !ok || key.Value == "Format" {
+				continue
+			}
+			if _, ok := format.Options[key.Value]; !ok {
+				problems = append(problems, fmt.Sprintf("unknown option %s for format %s", key.Value, strings.TrimPrefix(name.Value, ".")))
+			}
+		}
+		return problems
+	}
+	return nil
+}
+
+// format returns the format of the given name.
+func (c *scriptChecker) format(name string) (format dump.Format, ok bool) {
+	format, ok = c.root.Formats[strings.TrimPrefix(name, ".")]
+	return format, ok
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckScript(t *testing.T) {
+	root, err := GenerateDump(WorldOpt{
+		IncludeLibraries: DumpFlags{Test: true}.Libraries(),
+		ExcludeRoots:     true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		source   string
+		problems []string
+	}{
+		{`local v = fs.read("a.txt")`, nil},
+		{`fs.reed("a.txt")`, []string{"script.lua:1: unknown field reed in fs"}},
+		{`fs.read()`, []string{"script.lua:1: not enough arguments to fs.read: expected at least 1, got 0"}},
+		{`fs.read(42)`, []string{"script.lua:1: argument #1 to fs.read: expected string, got number"}},
+		{`fs.read(f())`, nil},
+		{`local fs = {}; fs.reed(42)`, nil},
+		{`fs.reed = nil; fs.reed()`, nil},
+		{`Vector3.new(1, 2, 3)`, nil},
+		{`fs.read("a.txt", "nonextant")`, []string{`script.lua:1: unknown format "nonextant"`}},
+		{`fs.read("a.txt", {Format="base64", Foo=1})`, []string{"script.lua:1: unknown option Foo for format base64"}},
+		{`rbxmk.encodeFormat({Format="base64", Width=4}, "")`, nil},
+		{`describe("a", function() it("b", function() expect(1).toBe(1) end) end)`, nil},
+		{`it(42, function() end)`, []string{"script.lua:1: argument #1 to it: expected string, got number"}},
+		{"\nlocal =", []string{"script.lua:2: syntax error near '='"}},
+	}
+	for _, test := range tests {
+		var problems []string
+		for _, problem := range checkScript(root, "script.lua", []byte(test.source)) {
+			problems = append(problems, problem.String())
+		}
+		if got, want := strings.Join(problems, "\n"), strings.Join(test.problems, "\n"); got != want {
+			t.Errorf("%q:\nexpected:\n%s\ngot:\n%s", test.source, want, got)
+		}
+	}
+}
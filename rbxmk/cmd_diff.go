@@ -0,0 +1,393 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/anaminus/cobra"
+	"github.com/anaminus/pflag"
+	"github.com/anaminus/rbxmk"
+	"github.com/anaminus/rbxmk/dump"
+	"github.com/anaminus/rbxmk/rtypes"
+	"github.com/robloxapi/types"
+)
+
+func init() {
+	var c DiffCommand
+	var cmd = Register.NewCommand(dump.Command{
+		Arguments:   "Commands/diff:Arguments",
+		Summary:     "Commands/diff:Summary",
+		Description: "Commands/diff:Description",
+	}, &cobra.Command{
+		Use:  "diff",
+		RunE: c.Run,
+	})
+	c.SetFlags(cmd.Flags())
+	Program.AddCommand(cmd)
+}
+
+type DiffCommand struct {
+	DescFlags
+	Format       string
+	FileFormat   string
+	Mode         string
+	AttrProperty string
+}
+
+func (c *DiffCommand) SetFlags(flags *pflag.FlagSet) {
+	c.DescFlags.SetFlags(flags)
+
+	flags.StringVarP(&c.Format, "format", "f", "text", "")
+	Register.NewFlag(dump.Flag{Description: "Commands/diff:Flags/format"}, flags, "format")
+
+	flags.StringVar(&c.FileFormat, "file-format", "", "")
+	Register.NewFlag(dump.Flag{Description: "Commands/diff:Flags/file-format"}, flags, "file-format")
+
+	flags.StringVar(&c.Mode, "mode", "path", "")
+	Register.NewFlag(dump.Flag{Description: "Commands/diff:Flags/mode"}, flags, "mode")
+
+	flags.StringVar(&c.AttrProperty, "attr-property", "", "")
+	Register.NewFlag(dump.Flag{Description: "Commands/diff:Flags/attr-property"}, flags, "attr-property")
+}
+
+func (c *DiffCommand) Run(cmd *cobra.Command, args []string) error {
+	var name, prevFile, nextFile string
+	switch len(args) {
+	case 2:
+		prevFile, nextFile = args[0], args[1]
+	case 7:
+		// Invoked by git as an external diff driver:
+		//     path old-file old-hex old-mode new-file new-hex new-mode
+		name, prevFile, nextFile = args[0], args[1], args[4]
+	default:
+		return cmd.Usage()
+	}
+
+	var mode rtypes.InstanceDiffMode
+	switch c.Mode {
+	case "path":
+		mode = rtypes.DiffByPath
+	case "reference":
+		mode = rtypes.DiffByReference
+	default:
+		return fmt.Errorf("unknown mode %q", c.Mode)
+	}
+	var write func(w io.Writer, entries []*diffEntry) error
+	switch c.Format {
+	case "text":
+		write = writeDiffText
+	case "json":
+		write = writeDiffJSON
+	default:
+		return fmt.Errorf("unknown format %q", c.Format)
+	}
+
+	// Initialize world.
+	world, err := InitWorld(WorldOpt{
+		WorldFlags:     WorldFlags{Debug: false},
+		ExcludeRoots:   true,
+		ExcludeProgram: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Initialize global descriptor.
+	world.Desc, err = c.DescFlags.Resolve(world.Client)
+	if err != nil {
+		return err
+	}
+	if c.AttrProperty != "" {
+		world.AttrConfig = &rtypes.AttrConfig{Property: c.AttrProperty}
+	}
+
+	prev, err := c.decode(world, prevFile, name)
+	if err != nil {
+		return err
+	}
+	next, err := c.decode(world, nextFile, name)
+	if err != nil {
+		return err
+	}
+	return write(cmd.OutOrStdout(), diffEntries(world.Global, prev, prev.Diff(next, mode)))
+}
+
+// decode decodes the file at path into an instance. The format is determined
+// by the --file-format flag, the extension of path, or the extension of name,
+// in that order. An empty file, such as the null device, decodes into an empty
+// DataModel.
+func (c *DiffCommand) decode(world *rbxmk.World, path, name string) (inst *rtypes.Instance, err error) {
+	if info, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	} else if info.Size() == 0 {
+		return rtypes.NewDataModel(), nil
+	}
+	selector := rtypes.FormatSelector{Format: c.FileFormat}
+	if selector.Format == "" {
+		if selector.Format = world.Ext(path); selector.Format == "" {
+			selector.Format = world.Ext(name)
+		}
+	}
+	return decodeInstanceFile(world, path, selector)
+}
+
+// diffAttrProperty is the default property that holds the serialized
+// attributes of an instance.
+const diffAttrProperty = "AttributesSerialize"
+
+// attrProperty returns the name of the property that holds the serialized
+// attributes of inst, according to the AttrConfig of inst, or of g if inst is
+// nil or has no AttrConfig.
+func attrProperty(g rtypes.Global, inst *rtypes.Instance) string {
+	if attrcfg := g.AttrConfig.Of(inst); attrcfg != nil && attrcfg.Property != "" {
+		return attrcfg.Property
+	}
+	return diffAttrProperty
+}
+
+// diffValue is the representation of a property or attribute value reported
+// by the diff command.
+type diffValue struct {
+	Type  string
+	Value string
+
+	// Whether the value is quoted as text.
+	quoted bool
+}
+
+// diffEntry is a single difference reported by the diff command.
+type diffEntry struct {
+	// Type is the kind of difference: Add, Remove, Move, or Change.
+	Type string
+	// Path is the path of the instance within the previous tree, or, for an
+	// Add entry, within the next tree.
+	Path []string
+	// Parent is the path of the new parent of a Move entry.
+	Parent []string `json:",omitempty"`
+	// ClassName is the class of the instance of an Add entry.
+	ClassName string `json:",omitempty"`
+	// Properties and Attributes are the values of the instance of an Add
+	// entry.
+	Properties map[string]*diffValue `json:",omitempty"`
+	Attributes map[string]*diffValue `json:",omitempty"`
+	// Property or Attribute is the name of the value that differs for a Change
+	// entry. Prev and Next are the values, which are nil if not set.
+	Property  string     `json:",omitempty"`
+	Attribute string     `json:",omitempty"`
+	Prev      *diffValue `json:",omitempty"`
+	Next      *diffValue `json:",omitempty"`
+}
+
+// instancePath returns the path of inst from the root of its tree.
+func instancePath(inst *rtypes.Instance) []string {
+	path := []string{}
+	for ; inst.Parent() != nil; inst = inst.Parent() {
+		path = append(path, inst.Name())
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// newDiffValue returns the representation of v, or nil if v is nil.
+func newDiffValue(v types.Value) *diffValue {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case *rtypes.Instance:
+		return &diffValue{Type: rtypes.T_Instance, Value: formatDiffPath(instancePath(v))}
+	case types.Stringlike:
+		return &diffValue{Type: v.Type(), Value: v.Stringlike(), quoted: true}
+	}
+	return &diffValue{Type: v.Type(), Value: fmt.Sprint(v)}
+}
+
+// decodeDiffAttributes decodes v as serialized attributes. Returns false if v
+// could not be decoded.
+func decodeDiffAttributes(v types.PropValue) (attrs rtypes.Dictionary, ok bool) {
+	switch v := v.(type) {
+	case nil:
+		return rtypes.Dictionary{}, true
+	case types.Stringlike:
+		d, err := rtypes.DecodeAttributes(strings.NewReader(v.Stringlike()))
+		if err != nil {
+			return nil, false
+		}
+		return d.(rtypes.Dictionary), true
+	}
+	return nil, false
+}
+
+// diffEntries converts actions into a list of entries. An added instance
+// produces an entry for it and each of its descendants, and a change to
+// serialized attributes produces an entry for each attribute that differs. The
+// property that holds serialized attributes is determined by the AttrConfig of
+// the target of the action within root, or by g.
+func diffEntries(g rtypes.Global, root *rtypes.Instance, actions rtypes.InstanceActions) []*diffEntry {
+	entries := []*diffEntry{}
+	for _, action := range actions {
+		path := action.Path
+		if path == nil {
+			path = []string{}
+		}
+		switch action.Action {
+		case rtypes.InstanceAdd:
+			entries = appendDiffAdd(g, entries, action.Parent, action.Instance)
+		case rtypes.InstanceRemove:
+			entries = append(entries, &diffEntry{Type: "Remove", Path: path})
+		case rtypes.InstanceMove:
+			parent := action.Parent
+			if parent == nil {
+				parent = []string{}
+			}
+			entries = append(entries, &diffEntry{Type: "Move", Path: path, Parent: parent})
+		case rtypes.InstanceChange:
+			if action.Property == attrProperty(g, action.Target(root)) {
+				prev, okp := decodeDiffAttributes(action.Prev)
+				next, okn := decodeDiffAttributes(action.Next)
+				if okp && okn {
+					names := map[string]bool{}
+					for name := range prev {
+						names[name] = true
+					}
+					for name := range next {
+						names[name] = true
+					}
+					for _, name := range sortedNames(names) {
+						if reflect.DeepEqual(prev[name], next[name]) {
+							continue
+						}
+						entries = append(entries, &diffEntry{
+							Type:      "Change",
+							Path:      path,
+							Attribute: name,
+							Prev:      newDiffValue(prev[name]),
+							Next:      newDiffValue(next[name]),
+						})
+					}
+					continue
+				}
+			}
+			entries = append(entries, &diffEntry{
+				Type:     "Change",
+				Path:     path,
+				Property: action.Property,
+				Prev:     newDiffValue(action.Prev),
+				Next:     newDiffValue(action.Next),
+			})
+		}
+	}
+	return entries
+}
+
+// appendDiffAdd appends an Add entry for inst, added to parent, and each of its
+// descendants.
+func appendDiffAdd(g rtypes.Global, entries []*diffEntry, parent []string, inst *rtypes.Instance) []*diffEntry {
+	path := make([]string, len(parent), len(parent)+1)
+	copy(path, parent)
+	path = append(path, inst.Name())
+	entry := &diffEntry{Type: "Add", Path: path, ClassName: inst.ClassName}
+	for _, name := range inst.PropertyNames() {
+		value := inst.Get(name)
+		if name == "Name" {
+			// Included in path.
+			continue
+		}
+		if name == attrProperty(g, inst) {
+			if attrs, ok := decodeDiffAttributes(value); ok {
+				for attr, v := range attrs {
+					if entry.Attributes == nil {
+						entry.Attributes = map[string]*diffValue{}
+					}
+					entry.Attributes[attr] = newDiffValue(v)
+				}
+				continue
+			}
+		}
+		if entry.Properties == nil {
+			entry.Properties = map[string]*diffValue{}
+		}
+		entry.Properties[name] = newDiffValue(value)
+	}
+	entries = append(entries, entry)
+	for _, child := range inst.Children() {
+		entries = appendDiffAdd(g, entries, path, child)
+	}
+	return entries
+}
+
+// sortedNames returns the keys of m in sorted order.
+func sortedNames[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// formatDiffPath returns the text representation of a path.
+func formatDiffPath(path []string) string {
+	if len(path) == 0 {
+		return "<root>"
+	}
+	return strings.Join(path, ".")
+}
+
+// formatDiffValue returns the text representation of a value.
+func formatDiffValue(v *diffValue) string {
+	switch {
+	case v == nil:
+		return "nil"
+	case v.quoted:
+		return fmt.Sprintf("%q", v.Value)
+	}
+	return v.Value
+}
+
+// writeDiffText writes entries to w as human-readable text, one line per
+// difference. Properties and attributes of added instances follow on indented
+// lines.
+func writeDiffText(w io.Writer, entries []*diffEntry) error {
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		path := formatDiffPath(entry.Path)
+		switch entry.Type {
+		case "Add":
+			fmt.Fprintf(&buf, "+ %s (%s)\n", path, entry.ClassName)
+			for _, name := range sortedNames(entry.Properties) {
+				fmt.Fprintf(&buf, "\t%s: %s\n", name, formatDiffValue(entry.Properties[name]))
+			}
+			for _, name := range sortedNames(entry.Attributes) {
+				fmt.Fprintf(&buf, "\t@%s: %s\n", name, formatDiffValue(entry.Attributes[name]))
+			}
+		case "Remove":
+			fmt.Fprintf(&buf, "- %s\n", path)
+		case "Move":
+			fmt.Fprintf(&buf, "> %s -> %s\n", path, formatDiffPath(entry.Parent))
+		case "Change":
+			name := "." + entry.Property
+			if entry.Attribute != "" {
+				name = "@" + entry.Attribute
+			}
+			fmt.Fprintf(&buf, "~ %s%s: %s -> %s\n", path, name, formatDiffValue(entry.Prev), formatDiffValue(entry.Next))
+		}
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// writeDiffJSON writes entries to w as an indented JSON array.
+func writeDiffJSON(w io.Writer, entries []*diffEntry) error {
+	j := json.NewEncoder(w)
+	j.SetIndent("", "\t")
+	j.SetEscapeHTML(false)
+	return j.Encode(entries)
+}
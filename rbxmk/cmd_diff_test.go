@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/anaminus/rbxmk/rtypes"
+	"github.com/robloxapi/types"
+)
+
+func TestDiffText(t *testing.T) {
+	prev := rtypes.NewDataModel()
+	workspace := rtypes.NewInstance("Workspace", prev)
+	workspace.SetName("Workspace")
+	part := rtypes.NewInstance("Part", workspace)
+	part.SetName("Part")
+	part.Set("Size", types.Vector3{X: 1, Y: 2, Z: 3})
+	var attrs bytes.Buffer
+	if err := rtypes.EncodeAttributes(&attrs, rtypes.Dictionary{"Health": types.Double(100)}); err != nil {
+		t.Fatal(err)
+	}
+	part.Set(diffAttrProperty, types.BinaryString(attrs.Bytes()))
+	rtypes.NewInstance("Folder", workspace).SetName("Old")
+
+	next := prev.Clone()
+	nextWorkspace := next.Descend("Workspace")
+	nextWorkspace.Descend("Old").SetParent(nil)
+	nextPart := nextWorkspace.Descend("Part")
+	nextPart.Set("Size", types.Vector3{X: 2, Y: 2, Z: 2})
+	attrs.Reset()
+	if err := rtypes.EncodeAttributes(&attrs, rtypes.Dictionary{"Health": types.Double(50)}); err != nil {
+		t.Fatal(err)
+	}
+	nextPart.Set(diffAttrProperty, types.BinaryString(attrs.Bytes()))
+	value := rtypes.NewInstance("StringValue", nextWorkspace)
+	value.SetName("Value")
+	value.Set("Value", types.String("hello"))
+
+	var w bytes.Buffer
+	if err := writeDiffText(&w, diffEntries(rtypes.Global{}, prev, prev.Diff(next, rtypes.DiffByPath))); err != nil {
+		t.Fatal(err)
+	}
+	const want = "+ Workspace.Value (StringValue)\n" +
+		"\tValue: \"hello\"\n" +
+		"~ Workspace.Part@Health: 100 -> 50\n" +
+		"~ Workspace.Part.Size: 1, 2, 3 -> 2, 2, 2\n" +
+		"- Workspace.Old\n"
+	if got := w.String(); got != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDiffAttrProperty(t *testing.T) {
+	prev := rtypes.NewDataModel()
+	part := rtypes.NewInstance("Part", prev)
+	part.SetName("Part")
+	var attrs bytes.Buffer
+	if err := rtypes.EncodeAttributes(&attrs, rtypes.Dictionary{"Health": types.Double(100)}); err != nil {
+		t.Fatal(err)
+	}
+	part.Set("Attrs", types.BinaryString(attrs.Bytes()))
+
+	next := prev.Clone()
+	attrs.Reset()
+	if err := rtypes.EncodeAttributes(&attrs, rtypes.Dictionary{"Health": types.Double(50)}); err != nil {
+		t.Fatal(err)
+	}
+	next.Descend("Part").Set("Attrs", types.BinaryString(attrs.Bytes()))
+
+	g := rtypes.Global{AttrConfig: &rtypes.AttrConfig{Property: "Attrs"}}
+	var w bytes.Buffer
+	if err := writeDiffText(&w, diffEntries(g, prev, prev.Diff(next, rtypes.DiffByPath))); err != nil {
+		t.Fatal(err)
+	}
+	const want = "~ Part@Health: 100 -> 50\n"
+	if got := w.String(); got != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDiffInstanceAttrConfig(t *testing.T) {
+	prev := rtypes.NewDataModel()
+	part := rtypes.NewInstance("Part", prev)
+	part.SetName("Part")
+	part.SetAttrConfig(&rtypes.AttrConfig{Property: "Attrs"}, false)
+	var attrs bytes.Buffer
+	if err := rtypes.EncodeAttributes(&attrs, rtypes.Dictionary{"Health": types.Double(100)}); err != nil {
+		t.Fatal(err)
+	}
+	part.Set("Attrs", types.BinaryString(attrs.Bytes()))
+
+	next := prev.Clone()
+	attrs.Reset()
+	if err := rtypes.EncodeAttributes(&attrs, rtypes.Dictionary{"Health": types.Double(50)}); err != nil {
+		t.Fatal(err)
+	}
+	next.Descend("Part").Set("Attrs", types.BinaryString(attrs.Bytes()))
+
+	var w bytes.Buffer
+	if err := writeDiffText(&w, diffEntries(rtypes.Global{}, prev, prev.Diff(next, rtypes.DiffByPath))); err != nil {
+		t.Fatal(err)
+	}
+	const want = "~ Part@Health: 100 -> 50\n"
+	if got := w.String(); got != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/anaminus/cobra"
+	"github.com/anaminus/pflag"
 	"github.com/anaminus/rbxmk"
 	"github.com/anaminus/rbxmk/dump"
 	"github.com/anaminus/rbxmk/dumpformats"
@@ -48,6 +49,32 @@ func GenerateDump(opt WorldOpt) (root dump.Root, err error) {
 	return root, nil
 }
 
+// DumpFlags contains flags that determine which libraries are included when
+// generating a dump.
+type DumpFlags struct {
+	// Test includes the test library, which is otherwise available only to the
+	// test command.
+	Test bool
+}
+
+func (d *DumpFlags) SetFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(&d.Test, "test", false, "")
+	Register.NewFlag(dump.Flag{Description: "Flags/dump:Flags/test"}, flags, "test")
+}
+
+// Libraries returns the libraries included when generating a dump.
+func (d DumpFlags) Libraries() rbxmk.Libraries {
+	libs := library.All()
+	if d.Test {
+		libs = append(libs, testLibrary(nil))
+	}
+	return libs
+}
+
+// dumpFlags contains the flags of the dump command, shared by each of its
+// sub-commands.
+var dumpFlags DumpFlags
+
 func init() {
 	var Dump = Register.NewCommand(dump.Command{
 		Arguments:   "Commands/dump:Arguments",
@@ -57,6 +84,7 @@ func init() {
 		Use:  "dump",
 		Args: cobra.NoArgs,
 	})
+	dumpFlags.SetFlags(Dump.PersistentFlags())
 
 	for _, format := range dumpformats.All() {
 		name := format.Name
@@ -71,7 +99,7 @@ func init() {
 			RunE: func(cmd *cobra.Command, args []string) error {
 				root, err := GenerateDump(WorldOpt{
 					WorldFlags:       WorldFlags{Debug: false},
-					IncludeLibraries: library.All(),
+					IncludeLibraries: dumpFlags.Libraries(),
 					ExcludeRoots:     true,
 				})
 				if err != nil {
@@ -104,6 +132,16 @@ func init() {
 	})
 	Dump.AddCommand(plugin)
 
+	var markdown = Register.NewCommand(dump.Command{
+		Summary:     "Commands/dump/markdown:Summary",
+		Description: "Commands/dump/markdown:Description",
+	}, &cobra.Command{
+		Use:  "markdown",
+		Args: cobra.NoArgs,
+		RunE: runDumpMarkdownCommand,
+	})
+	Dump.AddCommand(markdown)
+
 	Program.AddCommand(Dump)
 }
 
@@ -117,7 +155,7 @@ func runDumpPluginCommand(cmd *cobra.Command, args []string) error {
 	// Generate dump.
 	root, err := GenerateDump(WorldOpt{
 		ExcludeRoots:     true,
-		IncludeLibraries: library.All(),
+		IncludeLibraries: dumpFlags.Libraries(),
 	})
 	if err != nil {
 		return err
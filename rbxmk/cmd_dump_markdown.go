@@ -0,0 +1,835 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/anaminus/cobra"
+	"github.com/anaminus/rbxmk/dump"
+	"github.com/anaminus/rbxmk/dump/dt"
+	"github.com/anaminus/rbxmk/rbxmk/render/markdown"
+)
+
+func runDumpMarkdownCommand(cmd *cobra.Command, args []string) error {
+	root, err := GenerateDump(WorldOpt{
+		WorldFlags:       WorldFlags{Debug: false},
+		IncludeLibraries: dumpFlags.Libraries(),
+		ExcludeRoots:     true,
+	})
+	if err != nil {
+		return err
+	}
+	d := newMarkdownDump(root)
+	d.writeDocument()
+	_, err = cmd.OutOrStdout().Write(d.buf.Bytes())
+	return err
+}
+
+// markdownDump generates a Markdown document that references the API of a
+// dump, combined with the content of the fragments referred to by the dump.
+//
+// Each entry in the document is preceded by an anchor, which is the target of
+// links between entries:
+//
+//	libraries             Libraries section.
+//	lib.NAME              Library.
+//	api.NAME.FIELD        Field of a library, as named in the environment.
+//	types                 Types section.
+//	type.NAME             Type.
+//	type.NAME.MEMBER      Constructor, property, or method of a type.
+//	type.NAME.sym.SYMBOL  Symbol of a type.
+//	type.NAME.op.OP       Operator of a type.
+//	enums                 Enums section.
+//	enum.NAME             Enum.
+//	formats               Formats section.
+//	format.NAME           Format.
+//	format.NAME.OPTION    Option of a format.
+//	commands              Commands section.
+//	cmd.NAME.SUB          Command.
+type markdownDump struct {
+	root  dump.Root
+	enums dump.Enums
+	buf   bytes.Buffer
+}
+
+func newMarkdownDump(root dump.Root) *markdownDump {
+	d := &markdownDump{root: root, enums: dump.Enums{}}
+	for name, enum := range root.Enums {
+		d.enums[name] = enum
+	}
+	for _, lib := range root.Libraries {
+		for name, enum := range lib.Enums {
+			d.enums[name] = enum
+		}
+		d.collectEnums(lib.Struct.Fields)
+	}
+	for _, typ := range root.Types {
+		for name, enum := range typ.Enums {
+			d.enums[name] = enum
+		}
+	}
+	return d
+}
+
+// collectEnums adds enums that are values of fields, recursively.
+func (d *markdownDump) collectEnums(fields dump.Fields) {
+	for name, value := range fields {
+		switch value := value.(type) {
+		case dump.Enum:
+			d.enums[name] = value
+		case dump.Struct:
+			d.collectEnums(value.Fields)
+		}
+	}
+}
+
+// resolveLink converts the target of a link within a fragment to a target
+// within the document. Returns an empty string if the target cannot be
+// resolved.
+func (d *markdownDump) resolveLink(link string) string {
+	switch {
+	case strings.HasPrefix(link, "type:"):
+		link = strings.TrimPrefix(link, "type:")
+		name, member := link, ""
+		if i := strings.IndexAny(link, ".["); i >= 0 {
+			name, member = link[:i], link[i:]
+		}
+		if _, ok := d.root.Types[name]; !ok {
+			return ""
+		}
+		switch {
+		case member == "":
+			return "#type." + name
+		case strings.HasPrefix(member, "[sym."):
+			return "#type." + name + ".sym." + strings.TrimSuffix(strings.TrimPrefix(member, "[sym."), "]")
+		}
+		return "#type." + name + member
+	case strings.HasPrefix(link, "format:"):
+		name := strings.TrimPrefix(link, "format:")
+		if _, ok := d.root.Formats[name]; !ok {
+			return ""
+		}
+		return "#format." + name
+	case strings.HasPrefix(link, "api:"):
+		return d.apiLink(strings.Split(strings.TrimPrefix(link, "api:"), "."))
+	case link == "frag:formats":
+		return "#formats"
+	case strings.HasPrefix(link, "#"):
+		// Refers to a section of the same fragment, which is not retained.
+		return ""
+	}
+	return link
+}
+
+// apiLink returns a link to the entry of the value located at the given path
+// in the environment, or an empty string if the value could not be found.
+func (d *markdownDump) apiLink(path []string) string {
+	env := d.root.Environment
+	for _, name := range path {
+		if env == nil {
+			return ""
+		}
+		env = env.Fields[name]
+	}
+	if env == nil || len(env.Path) == 0 {
+		return ""
+	}
+	if env.Path[0] == "Types" && len(env.Path) == 4 {
+		return "#type." + env.Path[1] + "." + env.Path[3]
+	}
+	return "#api." + strings.Join(path, ".")
+}
+
+// content returns the rendered content of the fragment referred to by ref.
+// level is the heading level of sections within the fragment.
+func (d *markdownDump) content(ref string, level int) string {
+	if ref == "" {
+		return ""
+	}
+	r := markdown.NewRenderer()
+	r.HeadingLevel = level
+	r.ResolveLink = d.resolveLink
+	return strings.TrimSpace(Frag.ResolveWith(ref, FragOptions{
+		Renderer: r.Render,
+		Inner:    true,
+	}))
+}
+
+// summary returns the content of the fragment referred to by ref as a single
+// line suitable for a table cell.
+func (d *markdownDump) summary(ref string) string {
+	s := d.content(ref, 6)
+	s = strings.Join(strings.Fields(s), " ")
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// text returns the plain text content of the fragment referred to by ref.
+func (d *markdownDump) text(ref string) string {
+	if ref == "" {
+		return ""
+	}
+	return strings.TrimSpace(Frag.ResolveWith(ref, FragOptions{
+		Renderer: func(w io.Writer, s *goquery.Selection) error {
+			_, err := io.WriteString(w, s.Text())
+			return err
+		},
+		Inner: true,
+	}))
+}
+
+func (d *markdownDump) printf(format string, a ...interface{}) {
+	fmt.Fprintf(&d.buf, format, a...)
+}
+
+// heading writes a heading of the given level, preceded by an anchor.
+func (d *markdownDump) heading(level int, id, text string) {
+	d.printf("<a id=%q></a>\n%s %s\n\n", id, strings.Repeat("#", level), text)
+}
+
+// block writes s as a block, if it is not empty.
+func (d *markdownDump) block(s string) {
+	if s != "" {
+		d.printf("%s\n\n", s)
+	}
+}
+
+// code writes each line as a code span.
+func (d *markdownDump) code(lines ...string) {
+	for i, line := range lines {
+		if i > 0 {
+			d.printf("<br>\n")
+		}
+		d.printf("<code>%s</code>", line)
+	}
+	d.printf("\n\n")
+}
+
+// table writes a table with the given header and rows.
+func (d *markdownDump) table(header []string, rows [][]string) {
+	if len(rows) == 0 {
+		return
+	}
+	d.printf("| %s |\n", strings.Join(header, " | "))
+	d.printf("|%s\n", strings.Repeat("---|", len(header)))
+	for _, row := range rows {
+		d.printf("| %s |\n", strings.Join(row, " | "))
+	}
+	d.printf("\n")
+}
+
+// link returns a link with the given text and target.
+func link(text, target string) string {
+	return "[" + text + "](" + target + ")"
+}
+
+// typeString returns a representation of t that links to types.
+func (d *markdownDump) typeString(t dt.Type) string {
+	switch k := t.Kind.(type) {
+	case dt.KindPrim:
+		if _, ok := d.root.Types[string(k)]; ok {
+			return link(markdown.Escape(string(k)), "#type."+string(k))
+		}
+		if _, ok := d.enums[strings.TrimPrefix(string(k), "Enum.")]; ok {
+			return link(markdown.Escape(string(k)), "#enum."+strings.TrimPrefix(string(k), "Enum."))
+		}
+		return markdown.Escape(string(k))
+	case dt.KindOptional:
+		return d.typeString(k.Type) + "?"
+	case dt.KindGroup:
+		return "(" + d.typeString(k.Type) + ")"
+	case dt.KindOr:
+		sep := " | "
+		for _, t := range k {
+			if _, ok := t.Kind.(dt.KindPrim); !ok {
+				sep = "|"
+				break
+			}
+		}
+		s := make([]string, len(k))
+		for i, t := range k {
+			s[i] = d.typeString(t)
+		}
+		return strings.Join(s, sep)
+	case dt.KindArray:
+		return "{" + d.typeString(k.Type) + "}"
+	case dt.KindDictionary:
+		return `{\[` + d.typeString(dt.Prim("string")) + `\]: ` + d.typeString(k.Type) + "}"
+	case dt.KindMap:
+		return `{\[` + d.typeString(k.K) + `\]: ` + d.typeString(k.V) + "}"
+	case dt.KindStruct:
+		return "{" + d.structString(k) + "}"
+	case dt.KindTable:
+		s := `{\[` + d.typeString(k.Key) + `\]: ` + d.typeString(k.Value)
+		if len(k.Fields) > 0 {
+			s += ", " + d.structString(k.Fields)
+		}
+		return s + "}"
+	case dt.KindFunction:
+		return "(" + d.parameterString(k.Parameters) + ") -> (" + d.parameterString(k.Returns) + ")"
+	case nil:
+		return ""
+	}
+	return markdown.Escape(t.Kind.String())
+}
+
+// structString returns the fields of k, separated by commas.
+func (d *markdownDump) structString(k dt.KindStruct) string {
+	names := sortedNames(k)
+	s := make([]string, 0, len(names))
+	for _, name := range names {
+		if name == "..." {
+			continue
+		}
+		s = append(s, markdown.Escape(name)+": "+d.typeString(k[name]))
+	}
+	if v, ok := k["..."]; ok {
+		s = append(s, "...: "+d.typeString(v))
+	}
+	return strings.Join(s, ", ")
+}
+
+// parameterString returns a list of parameters, separated by commas.
+func (d *markdownDump) parameterString(params dump.Parameters) string {
+	s := make([]string, len(params))
+	for i, p := range params {
+		if p.Name != "" {
+			s[i] = markdown.Escape(p.Name) + ": "
+		}
+		s[i] += d.typeString(p.Type)
+	}
+	return strings.Join(s, ", ")
+}
+
+// functionString returns the signature of fn, with name as the name of the
+// function.
+func (d *markdownDump) functionString(name string, fn dump.Function) string {
+	s := name + "(" + d.parameterString(fn.Parameters) + ")"
+	if len(fn.Returns) > 0 {
+		s += ": (" + d.parameterString(fn.Returns) + ")"
+	}
+	return s
+}
+
+// valueString returns the signatures of a value, with name as the name of the
+// value.
+func (d *markdownDump) valueString(name string, value dump.Value) []string {
+	switch value := value.(type) {
+	case dump.Function:
+		return []string{d.functionString(name, value)}
+	case dump.MultiFunction:
+		s := make([]string, len(value))
+		for i, fn := range value {
+			s[i] = d.functionString(name, fn)
+		}
+		return s
+	case dump.Property:
+		return []string{d.propertyString(name, value)}
+	}
+	return []string{name + ": " + d.typeString(value.Type())}
+}
+
+// propertyString returns the signature of a property, with name as the name of
+// the property.
+func (d *markdownDump) propertyString(name string, prop dump.Property) string {
+	s := name + ": " + d.typeString(prop.ValueType)
+	if prop.ReadOnly {
+		s += " (read-only)"
+	}
+	return s
+}
+
+// multiContent returns the combined content of the unique descriptions of
+// each function in fns.
+func (d *markdownDump) multiContent(fns dump.MultiFunction, level int) string {
+	var s []string
+	seen := map[string]bool{}
+	for _, fn := range fns {
+		if fn.Description == "" || seen[fn.Description] {
+			continue
+		}
+		seen[fn.Description] = true
+		if c := d.content(fn.Description, level); c != "" {
+			s = append(s, c)
+		}
+	}
+	return strings.Join(s, "\n\n")
+}
+
+// multiSummary returns the summary of the first function in fns that has one.
+func (d *markdownDump) multiSummary(fns dump.MultiFunction) string {
+	for _, fn := range fns {
+		if fn.Summary != "" {
+			return d.summary(fn.Summary)
+		}
+	}
+	return ""
+}
+
+func (d *markdownDump) writeDocument() {
+	d.printf("# rbxmk reference\n\n")
+	d.printf("This document is a reference to the Lua API and command-line interface of rbxmk.\n\n")
+	d.printf("1. [Libraries](#libraries)\n")
+	d.printf("2. [Types](#types)\n")
+	d.printf("3. [Enums](#enums)\n")
+	d.printf("4. [Formats](#formats)\n")
+	d.printf("5. [Commands](#commands)\n\n")
+	d.writeLibraries()
+	d.writeTypes()
+	d.writeEnums()
+	d.writeFormats()
+	d.writeCommands()
+	d.buf.Truncate(len(bytes.TrimRight(d.buf.Bytes(), "\n")))
+	d.printf("\n")
+}
+
+// sortedLibraries returns the names of visible libraries in order of priority.
+func (d *markdownDump) sortedLibraries() []string {
+	var names []string
+	for name, lib := range d.root.Libraries {
+		if !lib.Hidden {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := d.root.Libraries[names[i]], d.root.Libraries[names[j]]
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+func (d *markdownDump) writeLibraries() {
+	d.heading(2, "libraries", "Libraries")
+	names := d.sortedLibraries()
+	rows := make([][]string, 0, len(names))
+	for _, name := range names {
+		lib := d.root.Libraries[name]
+		rows = append(rows, []string{link(markdown.Escape(name), "#lib."+name), d.summary(lib.Struct.Summary)})
+	}
+	d.table([]string{"Library", "Summary"}, rows)
+	for _, name := range names {
+		lib := d.root.Libraries[name]
+		d.heading(3, "lib."+name, markdown.Escape(name))
+		d.block(d.content(lib.Struct.Description, 4))
+		d.writeFields(lib.Import, lib.Struct.Fields)
+	}
+}
+
+// writeFields writes a table of the fields, followed by an entry for each
+// field. path is the location of the fields within the environment.
+func (d *markdownDump) writeFields(path []string, fields dump.Fields) {
+	names := make([]string, 0, len(fields))
+	for _, name := range sortedNames(fields) {
+		if !isHiddenValue(fields[name]) {
+			names = append(names, name)
+		}
+	}
+	rows := make([][]string, 0, len(names))
+	for _, name := range names {
+		full := append(append([]string{}, path...), name)
+		target := "#api." + strings.Join(full, ".")
+		if enum, ok := fields[name].(dump.Enum); ok {
+			rows = append(rows, []string{link(markdown.Escape(strings.Join(full, ".")), "#enum."+name), d.summary(enum.Summary)})
+			continue
+		}
+		rows = append(rows, []string{link(markdown.Escape(strings.Join(full, ".")), target), d.summary(valueSummary(fields[name]))})
+	}
+	d.table([]string{"Field", "Summary"}, rows)
+	for _, name := range names {
+		full := append(append([]string{}, path...), name)
+		fullName := markdown.Escape(strings.Join(full, "."))
+		switch value := fields[name].(type) {
+		case dump.Enum:
+			// Written in the Enums section.
+		case dump.Struct:
+			d.heading(4, "api."+strings.Join(full, "."), fullName)
+			d.block(d.content(value.Description, 5))
+			d.writeFields(full, value.Fields)
+		case dump.MultiFunction:
+			d.heading(4, "api."+strings.Join(full, "."), fullName)
+			d.code(d.valueString(fullName, value)...)
+			d.block(d.multiContent(value, 5))
+		default:
+			d.heading(4, "api."+strings.Join(full, "."), fullName)
+			d.code(d.valueString(fullName, value)...)
+			d.block(d.content(valueDescription(value), 5))
+		}
+	}
+}
+
+// isHiddenValue returns whether v is hidden from the public API.
+func isHiddenValue(v dump.Value) bool {
+	switch v := v.(type) {
+	case dump.Property:
+		return v.Hidden
+	case dump.Function:
+		return v.Hidden
+	case dump.Struct:
+		return v.Hidden
+	case dump.Enum:
+		return v.Hidden
+	}
+	return false
+}
+
+// valueSummary returns the summary fragment reference of v.
+func valueSummary(v dump.Value) string {
+	switch v := v.(type) {
+	case dump.Property:
+		return v.Summary
+	case dump.Function:
+		return v.Summary
+	case dump.Struct:
+		return v.Summary
+	case dump.Enum:
+		return v.Summary
+	case dump.MultiFunction:
+		for _, fn := range v {
+			if fn.Summary != "" {
+				return fn.Summary
+			}
+		}
+	}
+	return ""
+}
+
+// valueDescription returns the description fragment reference of v.
+func valueDescription(v dump.Value) string {
+	switch v := v.(type) {
+	case dump.Property:
+		return v.Description
+	case dump.Function:
+		return v.Description
+	case dump.Struct:
+		return v.Description
+	case dump.Enum:
+		return v.Description
+	}
+	return ""
+}
+
+func (d *markdownDump) writeTypes() {
+	d.heading(2, "types", "Types")
+	var names []string
+	for _, name := range sortedNames(d.root.Types) {
+		if !d.root.Types[name].Hidden {
+			names = append(names, name)
+		}
+	}
+	rows := make([][]string, 0, len(names))
+	for _, name := range names {
+		rows = append(rows, []string{link(markdown.Escape(name), "#type."+name), d.summary(d.root.Types[name].Summary)})
+	}
+	d.table([]string{"Type", "Summary"}, rows)
+	for _, name := range names {
+		d.writeType(name, d.root.Types[name])
+	}
+}
+
+func (d *markdownDump) writeType(name string, t dump.TypeDef) {
+	id := "type." + name
+	d.heading(3, id, markdown.Escape(name))
+	d.block(d.content(t.Description, 4))
+	if t.Underlying != nil {
+		d.printf("Underlying type: <code>%s</code>\n\n", d.typeString(*t.Underlying))
+	}
+	if len(t.Requires) > 0 {
+		s := make([]string, len(t.Requires))
+		for i, req := range t.Requires {
+			s[i] = d.typeString(dt.Prim(req))
+		}
+		d.printf("Requires: %s\n\n", strings.Join(s, ", "))
+	}
+
+	escName := markdown.Escape(name)
+	var rows [][]string
+	var entries []func()
+
+	for _, member := range sortedNames(t.Constructors) {
+		ctor := t.Constructors[member]
+		member := member
+		rows = append(rows, []string{link(escName+"."+markdown.Escape(member), "#"+id+"."+member), "Constructor", d.multiSummary(ctor)})
+		entries = append(entries, func() {
+			sig := escName + "." + markdown.Escape(member)
+			d.heading(4, id+"."+member, sig)
+			d.code(d.valueString(sig, ctor)...)
+			d.block(d.multiContent(ctor, 5))
+		})
+	}
+	for _, member := range sortedNames(t.Properties) {
+		prop := t.Properties[member]
+		if prop.Hidden {
+			continue
+		}
+		member := member
+		rows = append(rows, []string{link(escName+"."+markdown.Escape(member), "#"+id+"."+member), "Property", d.summary(prop.Summary)})
+		entries = append(entries, func() {
+			sig := escName + "." + markdown.Escape(member)
+			d.heading(4, id+"."+member, sig)
+			d.code(d.propertyString(sig, prop))
+			d.block(d.content(prop.Description, 5))
+		})
+	}
+	for _, member := range sortedNames(t.Symbols) {
+		prop := t.Symbols[member]
+		if prop.Hidden {
+			continue
+		}
+		member := member
+		sig := escName + `\[sym.` + markdown.Escape(member) + `\]`
+		rows = append(rows, []string{link(sig, "#"+id+".sym."+member), "Symbol", d.summary(prop.Summary)})
+		entries = append(entries, func() {
+			d.heading(4, id+".sym."+member, sig)
+			d.code(d.propertyString(sig, prop))
+			d.block(d.content(prop.Description, 5))
+		})
+	}
+	for _, member := range sortedNames(t.Methods) {
+		method := t.Methods[member]
+		if method.Hidden {
+			continue
+		}
+		member := member
+		rows = append(rows, []string{link(escName+":"+markdown.Escape(member), "#"+id+"."+member), "Method", d.summary(method.Summary)})
+		entries = append(entries, func() {
+			sig := escName + ":" + markdown.Escape(member)
+			d.heading(4, id+"."+member, sig)
+			d.code(d.functionString(sig, method))
+			d.block(d.content(method.Description, 5))
+		})
+	}
+	for _, op := range d.operators(name, t.Operators) {
+		op := op
+		rows = append(rows, []string{link(op.name, "#"+id+".op."+op.id), "Operator", d.summary(op.summary)})
+		entries = append(entries, func() {
+			d.heading(4, id+".op."+op.id, op.name)
+			d.code(op.signatures...)
+			d.block(d.content(op.description, 5))
+		})
+	}
+
+	d.table([]string{"Member", "Kind", "Summary"}, rows)
+	for _, entry := range entries {
+		entry()
+	}
+}
+
+// markdownOperator describes an operator of a type.
+type markdownOperator struct {
+	id          string
+	name        string
+	signatures  []string
+	summary     string
+	description string
+}
+
+// operators returns the operators of a type.
+func (d *markdownDump) operators(name string, ops *dump.Operators) []markdownOperator {
+	if ops == nil {
+		return nil
+	}
+	var list []markdownOperator
+	self := d.typeString(dt.Prim(name))
+	binop := func(id, symbol string, binops []dump.Binop) {
+		if len(binops) == 0 {
+			return
+		}
+		op := markdownOperator{
+			id:          id,
+			name:        "__" + strings.ToLower(id),
+			summary:     binops[0].Summary,
+			description: binops[0].Description,
+		}
+		for _, b := range binops {
+			op.signatures = append(op.signatures, self+" "+symbol+" "+d.typeString(b.Operand)+": "+d.typeString(b.Result))
+		}
+		list = append(list, op)
+	}
+	cmpop := func(id, symbol string, c *dump.Cmpop) {
+		if c == nil {
+			return
+		}
+		list = append(list, markdownOperator{
+			id:          id,
+			name:        "__" + strings.ToLower(id),
+			signatures:  []string{self + " " + symbol + " " + self + ": " + d.typeString(dt.Prim("bool"))},
+			summary:     c.Summary,
+			description: c.Description,
+		})
+	}
+	unop := func(id, symbol string, u *dump.Unop) {
+		if u == nil {
+			return
+		}
+		list = append(list, markdownOperator{
+			id:          id,
+			name:        "__" + strings.ToLower(id),
+			signatures:  []string{symbol + self + ": " + d.typeString(u.Result)},
+			summary:     u.Summary,
+			description: u.Description,
+		})
+	}
+	function := func(id string, fn *dump.Function) {
+		if fn == nil {
+			return
+		}
+		list = append(list, markdownOperator{
+			id:          id,
+			name:        "__" + strings.ToLower(id),
+			signatures:  []string{d.functionString(self, *fn)},
+			summary:     fn.Summary,
+			description: fn.Description,
+		})
+	}
+	binop("Add", "+", ops.Add)
+	binop("Sub", "-", ops.Sub)
+	binop("Mul", `\*`, ops.Mul)
+	binop("Div", "/", ops.Div)
+	binop("Mod", "%", ops.Mod)
+	binop("Pow", "^", ops.Pow)
+	binop("Concat", "..", ops.Concat)
+	cmpop("Eq", "==", ops.Eq)
+	cmpop("Le", "<=", ops.Le)
+	cmpop("Lt", `\<`, ops.Lt)
+	unop("Len", "#", ops.Len)
+	unop("Unm", "-", ops.Unm)
+	function("Call", ops.Call)
+	function("Index", ops.Index)
+	function("Newindex", ops.Newindex)
+	for i := range list {
+		list[i].name = markdown.Escape(list[i].name)
+	}
+	return list
+}
+
+func (d *markdownDump) writeEnums() {
+	d.heading(2, "enums", "Enums")
+	var names []string
+	for _, name := range sortedNames(d.enums) {
+		if !d.enums[name].Hidden {
+			names = append(names, name)
+		}
+	}
+	rows := make([][]string, 0, len(names))
+	for _, name := range names {
+		rows = append(rows, []string{link(markdown.Escape(name), "#enum."+name), d.summary(d.enums[name].Summary)})
+	}
+	d.table([]string{"Enum", "Summary"}, rows)
+	for _, name := range names {
+		enum := d.enums[name]
+		d.heading(3, "enum."+name, markdown.Escape(name))
+		d.block(d.content(enum.Description, 4))
+		items := make([]string, 0, len(enum.Items))
+		for item := range enum.Items {
+			if !enum.Items[item].Hidden {
+				items = append(items, item)
+			}
+		}
+		sort.Slice(items, func(i, j int) bool {
+			return enum.Items[items[i]].Value < enum.Items[items[j]].Value
+		})
+		rows := make([][]string, 0, len(items))
+		for _, item := range items {
+			rows = append(rows, []string{
+				markdown.Escape(item),
+				fmt.Sprint(enum.Items[item].Value),
+				d.summary(enum.Items[item].Summary),
+			})
+		}
+		d.table([]string{"Item", "Value", "Summary"}, rows)
+	}
+}
+
+func (d *markdownDump) writeFormats() {
+	d.heading(2, "formats", "Formats")
+	var names []string
+	for _, name := range sortedNames(d.root.Formats) {
+		if !d.root.Formats[name].Hidden {
+			names = append(names, name)
+		}
+	}
+	rows := make([][]string, 0, len(names))
+	for _, name := range names {
+		rows = append(rows, []string{link(markdown.Escape(name), "#format."+name), d.summary(d.root.Formats[name].Summary)})
+	}
+	d.table([]string{"Format", "Summary"}, rows)
+	for _, name := range names {
+		format := d.root.Formats[name]
+		d.heading(3, "format."+name, markdown.Escape(name))
+		d.block(d.content(format.Description, 4))
+		var options []string
+		for _, option := range sortedNames(format.Options) {
+			if !format.Options[option].Hidden {
+				options = append(options, option)
+			}
+		}
+		for _, option := range options {
+			opt := format.Options[option]
+			sig := markdown.Escape(option) + ": " + d.typeString(opt.Type)
+			if opt.Default != "" {
+				sig += " = " + markdown.Escape(opt.Default)
+			}
+			d.heading(4, "format."+name+"."+option, markdown.Escape(name+"."+option))
+			d.code(sig)
+			d.block(d.content(opt.Description, 5))
+		}
+	}
+}
+
+func (d *markdownDump) writeCommands() {
+	d.heading(2, "commands", "Commands")
+	d.writeCommand([]string{"rbxmk"}, d.root.Program)
+}
+
+// writeCommand writes the subcommands of cmd, recursively. path is the full
+// name of cmd.
+func (d *markdownDump) writeCommand(path []string, cmd dump.Command) {
+	var names []string
+	for _, name := range sortedNames(cmd.Commands) {
+		if !cmd.Commands[name].Hidden {
+			names = append(names, name)
+		}
+	}
+	for _, name := range names {
+		sub := cmd.Commands[name]
+		full := append(append([]string{}, path...), name)
+		d.heading(3, "cmd."+strings.Join(full[1:], "."), markdown.Escape(strings.Join(full, " ")))
+		d.block(d.content(sub.Summary, 4))
+		usage := strings.Join(full, " ")
+		if args := d.text(sub.Arguments); args != "" {
+			usage += " " + args
+		}
+		d.printf("```\n%s\n```\n\n", usage)
+		if sub.Deprecated != "" {
+			d.block("**Deprecated:** " + d.summary(sub.Deprecated))
+		}
+		d.block(d.content(sub.Description, 4))
+		var flags []string
+		for _, flag := range sortedNames(sub.Flags) {
+			if !sub.Flags[flag].Hidden {
+				flags = append(flags, flag)
+			}
+		}
+		rows := make([][]string, 0, len(flags))
+		for _, flag := range flags {
+			f := sub.Flags[flag]
+			name := "<code>--" + flag + "</code>"
+			if f.Shorthand != "" {
+				name = "<code>-" + f.Shorthand + "</code>, " + name
+			}
+			def := ""
+			if f.Default != "" {
+				def = "<code>" + strings.ReplaceAll(markdown.Escape(f.Default), "|", `\|`) + "</code>"
+			}
+			rows = append(rows, []string{name, markdown.Escape(f.Type), def, d.summary(f.Description)})
+		}
+		d.table([]string{"Flag", "Type", "Default", "Description"}, rows)
+		d.writeCommand(full, sub)
+	}
+}
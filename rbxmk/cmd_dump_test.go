@@ -5,7 +5,6 @@ import (
 	"testing"
 
 	"github.com/anaminus/rbxmk/dump"
-	"github.com/anaminus/rbxmk/library"
 )
 
 func walkDumpEnvRef(ref *dump.EnvRef, visit func(ref *dump.EnvRef, path []string), path ...string) {
@@ -25,7 +24,7 @@ func walkDumpEnvRef(ref *dump.EnvRef, visit func(ref *dump.EnvRef, path []string
 
 func TestDumpEnv(t *testing.T) {
 	root, err := GenerateDump(WorldOpt{
-		IncludeLibraries: library.All(),
+		IncludeLibraries: DumpFlags{Test: true}.Libraries(),
 		ExcludeRoots:     true,
 	})
 	if err != nil {
@@ -37,3 +36,20 @@ func TestDumpEnv(t *testing.T) {
 		}
 	})
 }
+
+func TestDumpFlagsTest(t *testing.T) {
+	for _, test := range []bool{false, true} {
+		root, err := GenerateDump(WorldOpt{
+			IncludeLibraries: DumpFlags{Test: test}.Libraries(),
+			ExcludeRoots:     true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range []string{"describe", "it", "expect"} {
+			if _, ok := root.Environment.Fields[name]; ok != test {
+				t.Errorf("test %v: expected %s to be included: %v", test, name, test)
+			}
+		}
+	}
+}
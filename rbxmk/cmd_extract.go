@@ -0,0 +1,279 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/anaminus/cobra"
+	"github.com/anaminus/pflag"
+	"github.com/anaminus/rbxmk"
+	"github.com/anaminus/rbxmk/dump"
+	"github.com/anaminus/rbxmk/formats"
+	"github.com/anaminus/rbxmk/rtypes"
+	"github.com/robloxapi/types"
+)
+
+func init() {
+	var c ExtractCommand
+	var cmd = Register.NewCommand(dump.Command{
+		Arguments:   "Commands/extract:Arguments",
+		Summary:     "Commands/extract:Summary",
+		Description: "Commands/extract:Description",
+	}, &cobra.Command{
+		Use:  "extract",
+		Args: cobra.ExactArgs(2),
+		RunE: c.Run,
+	})
+	c.SetFlags(cmd.Flags())
+	Program.AddCommand(cmd)
+}
+
+// scriptFormats maps a script class to the format of the file it is extracted
+// to.
+var scriptFormats = map[string]string{
+	"Script":       formats.F_ServerLua,
+	"LocalScript":  formats.F_ClientLua,
+	"ModuleScript": formats.F_Lua,
+}
+
+// scriptFormatsLuau maps a script class to the format of the file it is
+// extracted to, when extracting Luau files.
+var scriptFormatsLuau = map[string]string{
+	"Script":       formats.F_ServerLuau,
+	"LocalScript":  formats.F_ClientLuau,
+	"ModuleScript": formats.F_Luau,
+}
+
+// scriptInitName is the stem of a file that contains the source of the script
+// represented by the file's directory.
+const scriptInitName = "init"
+
+// decodeInstanceFile decodes the file at path into an instance. The format is
+// determined by the extension of path unless format is specified.
+func decodeInstanceFile(world *rbxmk.World, path string, selector rtypes.FormatSelector) (inst *rtypes.Instance, err error) {
+	if selector.Format == "" {
+		if selector.Format = world.Ext(path); selector.Format == "" {
+			return nil, fmt.Errorf("unknown format of %s", path)
+		}
+	}
+	format := world.Format(selector.Format)
+	if format.Name == "" {
+		return nil, fmt.Errorf("unknown format %q", selector.Format)
+	}
+	if format.Decode == nil || format.CanDecode == nil || !format.CanDecode(world.Global, selector, rtypes.T_Instance) {
+		return nil, fmt.Errorf("cannot decode instance with format %s", format.Name)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+	v, err := format.Decode(world.Global, selector, bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %w", path, err)
+	}
+	if inst, ok := v.(*rtypes.Instance); ok {
+		return inst, nil
+	}
+	return nil, fmt.Errorf("decode %s: expected %s, got %s", path, rtypes.T_Instance, v.Type())
+}
+
+// encodeInstanceFile encodes inst to the file at path. The format is
+// determined by the extension of path unless format is specified.
+func encodeInstanceFile(world *rbxmk.World, path string, selector rtypes.FormatSelector, inst *rtypes.Instance) (err error) {
+	if selector.Format == "" {
+		if selector.Format = world.Ext(path); selector.Format == "" {
+			return fmt.Errorf("unknown format of %s", path)
+		}
+	}
+	format := world.Format(selector.Format)
+	if format.Name == "" {
+		return fmt.Errorf("unknown format %q", selector.Format)
+	}
+	if format.Encode == nil {
+		return fmt.Errorf("cannot encode with format %s", format.Name)
+	}
+	var w bytes.Buffer
+	if err := format.Encode(world.Global, selector, &w, inst); err != nil {
+		return fmt.Errorf("encode %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, w.Bytes(), 0666); err != nil {
+		return fmt.Errorf("write file: %w", err)
+	}
+	return nil
+}
+
+type ExtractCommand struct {
+	DescFlags
+	FileFormat string
+	Luau       bool
+}
+
+func (c *ExtractCommand) SetFlags(flags *pflag.FlagSet) {
+	c.DescFlags.SetFlags(flags)
+
+	flags.StringVar(&c.FileFormat, "file-format", "", "")
+	Register.NewFlag(dump.Flag{Description: "Commands/extract:Flags/file-format"}, flags, "file-format")
+
+	flags.BoolVar(&c.Luau, "luau", false, "")
+	Register.NewFlag(dump.Flag{Description: "Commands/extract:Flags/luau"}, flags, "luau")
+}
+
+func (c *ExtractCommand) Run(cmd *cobra.Command, args []string) error {
+	file, dir := args[0], args[1]
+
+	// Initialize world.
+	world, err := InitWorld(WorldOpt{
+		WorldFlags:     WorldFlags{Debug: false},
+		ExcludeRoots:   true,
+		ExcludeProgram: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Initialize global descriptor.
+	world.Desc, err = c.DescFlags.Resolve(world.Client)
+	if err != nil {
+		return err
+	}
+
+	// Skip decoding other instances if possible.
+	selector := rtypes.FormatSelector{Format: c.FileFormat}
+	if selector.Format == "" {
+		selector.Format = world.Ext(file)
+	}
+	if _, ok := world.Format(selector.Format).Options["Classes"]; ok {
+		classes := make(rtypes.Array, 0, len(scriptFormats))
+		for _, class := range sortedNames(scriptFormats) {
+			classes = append(classes, types.String(class))
+		}
+		selector.Options = rtypes.Dictionary{"Classes": classes}
+	}
+	root, err := decodeInstanceFile(world, file, selector)
+	if err != nil {
+		return err
+	}
+
+	e := scriptExtractor{
+		world:   world,
+		formats: scriptFormats,
+		stderr:  cmd.ErrOrStderr(),
+	}
+	if c.Luau {
+		e.formats = scriptFormatsLuau
+	}
+	return e.extract(dir, root)
+}
+
+// scriptExtractor writes the scripts within a tree of instances to files.
+type scriptExtractor struct {
+	world   *rbxmk.World
+	formats map[string]string
+	stderr  io.Writer
+}
+
+// hasScripts returns whether inst or any of its descendants is a script.
+func (e scriptExtractor) hasScripts(inst *rtypes.Instance) bool {
+	if _, ok := e.formats[inst.ClassName]; ok {
+		return true
+	}
+	for _, child := range inst.Children() {
+		if e.hasScripts(child) {
+			return true
+		}
+	}
+	return false
+}
+
+// write writes the source of script to path with the given format.
+func (e scriptExtractor) write(path, format string, script *rtypes.Instance) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	source := script.Get("Source")
+	if source == nil {
+		source = types.ProtectedString("")
+	}
+	var w bytes.Buffer
+	if err := e.world.Format(format).Encode(e.world.Global, rtypes.FormatSelector{Format: format}, &w, source); err != nil {
+		return fmt.Errorf("%s: %w", script.GetFullName(), err)
+	}
+	return os.WriteFile(path, w.Bytes(), 0666)
+}
+
+// extract writes each script that is a descendant of inst to a file within
+// dir.
+//
+// Each instance is represented by a file or directory of the same name. A
+// script is written as a file with an extension from e.formats, or as a
+// directory containing an init file if the script has descendant scripts. Any
+// other instance that has descendant scripts is written as a directory.
+//
+// Instances that cannot be located unambiguously by path, such as siblings
+// with the same name that both contain scripts, are skipped with a warning.
+func (e scriptExtractor) extract(dir string, inst *rtypes.Instance) error {
+	var children []*rtypes.Instance
+	names := map[string]int{}
+	for _, child := range inst.Children() {
+		if e.hasScripts(child) {
+			children = append(children, child)
+			names[child.Name()]++
+		}
+	}
+	for _, child := range children {
+		name := child.Name()
+		if !isValidFileName(name) || name == scriptInitName {
+			fmt.Fprintf(e.stderr, "skipping %s: invalid file name %q\n", child.GetFullName(), name)
+			continue
+		}
+		if names[name] > 1 {
+			fmt.Fprintf(e.stderr, "skipping %s: duplicate name %q\n", child.GetFullName(), name)
+			continue
+		}
+		path := filepath.Join(dir, name)
+		format, ok := e.formats[child.ClassName]
+		if !ok {
+			if err := e.extract(path, child); err != nil {
+				return err
+			}
+			continue
+		}
+		hasDescendants := false
+		for _, c := range child.Children() {
+			if e.hasScripts(c) {
+				hasDescendants = true
+				break
+			}
+		}
+		if !hasDescendants {
+			if err := e.write(path+"."+format, format, child); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := e.write(filepath.Join(path, scriptInitName+"."+format), format, child); err != nil {
+			return err
+		}
+		if err := e.extract(path, child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isValidFileName returns whether name can be used as the name of a file.
+func isValidFileName(name string) bool {
+	switch name {
+	case "", ".", "..":
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		if os.IsPathSeparator(name[i]) {
+			return false
+		}
+	}
+	return true
+}
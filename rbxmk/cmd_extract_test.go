@@ -0,0 +1,110 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/anaminus/cobra"
+	"github.com/anaminus/rbxmk/rtypes"
+	"github.com/robloxapi/types"
+)
+
+func TestExtractInject(t *testing.T) {
+	world, err := InitWorld(WorldOpt{ExcludeRoots: true, ExcludeProgram: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := rtypes.NewDataModel()
+	service := rtypes.NewInstance("ServerScriptService", root)
+	service.SetName("ServerScriptService")
+	main := rtypes.NewInstance("Script", service)
+	main.SetName("Main")
+	main.Set("Source", types.ProtectedString("print('main')"))
+	module := rtypes.NewInstance("ModuleScript", main)
+	module.SetName("Util")
+	module.Set("Source", types.ProtectedString("return {}"))
+	rtypes.NewInstance("Folder", service).SetName("Main")
+	rtypes.NewInstance("Part", root).SetName("Part")
+
+	dir := t.TempDir()
+	e := scriptExtractor{world: world, formats: scriptFormats, stderr: io.Discard}
+	if err := e.extract(dir, root); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"ServerScriptService/Main/init.server.lua": "print('main')",
+		"ServerScriptService/Main/Util.lua":        "return {}",
+	}
+	for name, want := range files {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(b); got != want {
+			t.Errorf("%s: expected %q, got %q", name, want, got)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "Part")); !os.IsNotExist(err) {
+		t.Errorf("unexpected file for instance without scripts")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "ServerScriptService/Main/Util.lua"), []byte("return 42"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := injectScripts(world, dir, root); err != nil {
+		t.Fatal(err)
+	}
+	if got := module.Get("Source"); got != types.ProtectedString("return 42") {
+		t.Errorf("expected injected source, got %q", got)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "ServerScriptService/Main/Util.client.lua"), nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := injectScripts(world, dir, root); err == nil {
+		t.Errorf("expected error for mismatched class")
+	}
+}
+
+func TestExtractInjectArguments(t *testing.T) {
+	world, err := InitWorld(WorldOpt{ExcludeRoots: true, ExcludeProgram: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := rtypes.NewDataModel()
+	script := rtypes.NewInstance("Script", root)
+	script.SetName("Main")
+	script.Set("Source", types.ProtectedString("print('main')"))
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "place.rbxmx")
+	src := filepath.Join(dir, "src")
+	if err := encodeInstanceFile(world, file, rtypes.FormatSelector{}, root); err != nil {
+		t.Fatal(err)
+	}
+
+	// Both commands take the file first, then the directory.
+	cmd := &cobra.Command{}
+	cmd.SetErr(io.Discard)
+	if err := (&ExtractCommand{}).Run(cmd, []string{file, src}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "Main.server.lua"), []byte("print('edited')"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := (&InjectCommand{}).Run(cmd, []string{file, src}); err != nil {
+		t.Fatal(err)
+	}
+
+	root, err = decodeInstanceFile(world, file, rtypes.FormatSelector{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := root.Descend("Main").Get("Source"); got != types.ProtectedString("print('edited')") {
+		t.Errorf("expected injected source, got %q", got)
+	}
+}
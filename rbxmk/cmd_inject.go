@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/anaminus/cobra"
+	"github.com/anaminus/pflag"
+	"github.com/anaminus/rbxmk"
+	"github.com/anaminus/rbxmk/dump"
+	"github.com/anaminus/rbxmk/rtypes"
+)
+
+func init() {
+	var c InjectCommand
+	var cmd = Register.NewCommand(dump.Command{
+		Arguments:   "Commands/inject:Arguments",
+		Summary:     "Commands/inject:Summary",
+		Description: "Commands/inject:Description",
+	}, &cobra.Command{
+		Use:  "inject",
+		Args: cobra.ExactArgs(2),
+		RunE: c.Run,
+	})
+	c.SetFlags(cmd.Flags())
+	Program.AddCommand(cmd)
+}
+
+type InjectCommand struct {
+	DescFlags
+	FileFormat string
+	Output     string
+}
+
+func (c *InjectCommand) SetFlags(flags *pflag.FlagSet) {
+	c.DescFlags.SetFlags(flags)
+
+	flags.StringVar(&c.FileFormat, "file-format", "", "")
+	Register.NewFlag(dump.Flag{Description: "Commands/inject:Flags/file-format"}, flags, "file-format")
+
+	flags.StringVarP(&c.Output, "output", "o", "", "")
+	Register.NewFlag(dump.Flag{
+		Type:        "path",
+		Description: "Commands/inject:Flags/output",
+	}, flags, "output")
+}
+
+func (c *InjectCommand) Run(cmd *cobra.Command, args []string) error {
+	file, dir := args[0], args[1]
+
+	// Initialize world.
+	world, err := InitWorld(WorldOpt{
+		WorldFlags:     WorldFlags{Debug: false},
+		ExcludeRoots:   true,
+		ExcludeProgram: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Initialize global descriptor.
+	world.Desc, err = c.DescFlags.Resolve(world.Client)
+	if err != nil {
+		return err
+	}
+
+	selector := rtypes.FormatSelector{Format: c.FileFormat}
+	root, err := decodeInstanceFile(world, file, selector)
+	if err != nil {
+		return err
+	}
+	if err := injectScripts(world, dir, root); err != nil {
+		return err
+	}
+	output := c.Output
+	if output == "" {
+		output = file
+	}
+	return encodeInstanceFile(world, output, selector, root)
+}
+
+// scriptFormatClass returns the script class that corresponds to the format
+// of a file extracted by the extract command, or an empty string if there is
+// no such class.
+func scriptFormatClass(format string) string {
+	for _, formats := range []map[string]string{scriptFormats, scriptFormatsLuau} {
+		for class, f := range formats {
+			if f == format {
+				return class
+			}
+		}
+	}
+	return ""
+}
+
+// locateScript returns the instance at path within root. Among siblings, only
+// instances that are or contain scripts are considered, and only one must
+// have the name of each path element.
+func locateScript(root *rtypes.Instance, path []string) (inst *rtypes.Instance, err error) {
+	e := scriptExtractor{formats: scriptFormats}
+	inst = root
+	for i, name := range path {
+		var found *rtypes.Instance
+		for _, child := range inst.Children() {
+			if child.Name() != name || !e.hasScripts(child) {
+				continue
+			}
+			if found != nil {
+				return nil, fmt.Errorf("ambiguous path %s", strings.Join(path[:i+1], "/"))
+			}
+			found = child
+		}
+		if found == nil {
+			return nil, fmt.Errorf("no script at %s", strings.Join(path[:i+1], "/"))
+		}
+		inst = found
+	}
+	return inst, nil
+}
+
+// injectScripts sets the Source of each script within root from the
+// corresponding file within dir, as written by the extract command. The script
+// of each file is located by path. Files of other formats are ignored.
+//
+// Returns an error if a file does not correspond to a script of the same
+// class, in which case root may be partially modified.
+func injectScripts(world *rbxmk.World, dir string, root *rtypes.Instance) error {
+	return filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		format := world.Ext(entry.Name())
+		class := scriptFormatClass(format)
+		if class == "" {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		names := strings.Split(filepath.ToSlash(rel), "/")
+		stem := strings.TrimSuffix(names[len(names)-1], "."+format)
+		if stem == scriptInitName {
+			names = names[:len(names)-1]
+		} else {
+			names[len(names)-1] = stem
+		}
+		script, err := locateScript(root, names)
+		if err != nil {
+			return fmt.Errorf("%s: %w", rel, err)
+		}
+		if script.ClassName != class {
+			return fmt.Errorf("%s: expected %s, got %s", rel, class, script.ClassName)
+		}
+		source, err := decodeInstanceFile(world, path, rtypes.FormatSelector{Format: format})
+		if err != nil {
+			return err
+		}
+		script.Set("Source", source.Get("Source"))
+		return nil
+	})
+}
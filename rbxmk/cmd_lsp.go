@@ -0,0 +1,731 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/anaminus/cobra"
+	"github.com/anaminus/pflag"
+	"github.com/anaminus/rbxmk/dump"
+	"github.com/anaminus/rbxmk/rbxmk/render/markdown"
+)
+
+func init() {
+	var c LSPCommand
+	var cmd = Register.NewCommand(dump.Command{
+		Summary:     "Commands/lsp:Summary",
+		Description: "Commands/lsp:Description",
+	}, &cobra.Command{
+		Use:  "lsp",
+		Args: cobra.NoArgs,
+		RunE: c.Run,
+	})
+	c.SetFlags(cmd.Flags())
+	Program.AddCommand(cmd)
+}
+
+type LSPCommand struct {
+	DumpFlags
+	Stdio bool
+}
+
+func (c *LSPCommand) SetFlags(flags *pflag.FlagSet) {
+	c.DumpFlags.SetFlags(flags)
+
+	flags.BoolVar(&c.Stdio, "stdio", true, "")
+	Register.NewFlag(dump.Flag{Description: "Commands/lsp:Flags/stdio"}, flags, "stdio")
+}
+
+func (c *LSPCommand) Run(cmd *cobra.Command, args []string) error {
+	if !c.Stdio {
+		return fmt.Errorf("only stdio is supported")
+	}
+	root, err := GenerateDump(WorldOpt{
+		WorldFlags:       WorldFlags{Debug: false},
+		IncludeLibraries: c.DumpFlags.Libraries(),
+		ExcludeRoots:     true,
+	})
+	if err != nil {
+		return err
+	}
+	return newLSPServer(root, cmd.InOrStdin(), cmd.OutOrStdout()).serve()
+}
+
+// JSON-RPC error codes.
+const (
+	lspParseError     = -32700
+	lspInvalidParams  = -32602
+	lspMethodNotFound = -32601
+)
+
+// LSP completion item kinds.
+const (
+	lspKindMethod   = 2
+	lspKindFunction = 3
+	lspKindField    = 5
+	lspKindClass    = 7
+	lspKindModule   = 9
+	lspKindEnum     = 13
+)
+
+// lspMessage is a JSON-RPC request or notification received from the client.
+type lspMessage struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+type lspPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type lspTextDocumentPosition struct {
+	TextDocument struct {
+		URI string `json:"uri"`
+	} `json:"textDocument"`
+	Position lspPosition `json:"position"`
+}
+
+type lspMarkup struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+type lspCompletionItem struct {
+	Label         string     `json:"label"`
+	Kind          int        `json:"kind,omitempty"`
+	Detail        string     `json:"detail,omitempty"`
+	Documentation *lspMarkup `json:"documentation,omitempty"`
+}
+
+type lspSignature struct {
+	Label         string          `json:"label"`
+	Documentation *lspMarkup      `json:"documentation,omitempty"`
+	Parameters    []lspLabelValue `json:"parameters"`
+}
+
+type lspLabelValue struct {
+	Label string `json:"label"`
+}
+
+// lspServer implements a language server that provides completion, hover
+// documentation, and signature help for the rbxmk API described by a dump.
+// Messages are received from r and sent to w.
+type lspServer struct {
+	root dump.Root
+	r    *bufio.Reader
+	w    io.Writer
+
+	// docs maps the URI of an open document to its content.
+	docs map[string]string
+	// shutdown is set when the client has requested a shutdown.
+	shutdown bool
+}
+
+func newLSPServer(root dump.Root, r io.Reader, w io.Writer) *lspServer {
+	return &lspServer{
+		root: root,
+		r:    bufio.NewReader(r),
+		w:    w,
+		docs: map[string]string{},
+	}
+}
+
+// errLSPExit is returned by handle when the client requests an exit.
+var errLSPExit = errors.New("exit")
+
+// serve handles messages until the client exits or the input is closed.
+func (s *lspServer) serve() error {
+	for {
+		b, err := s.read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		var msg lspMessage
+		if err := json.Unmarshal(b, &msg); err != nil {
+			if err := s.respondError(json.RawMessage("null"), lspParseError, err.Error()); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := s.handle(msg); err != nil {
+			if err == errLSPExit {
+				if !s.shutdown {
+					return fmt.Errorf("exit without shutdown")
+				}
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// read reads the content of the next message.
+func (s *lspServer) read() ([]byte, error) {
+	header, err := textproto.NewReader(s.r).ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid content length: %w", err)
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(s.r, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// write sends v as a message.
+func (s *lspServer) write(v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(s.w, "Content-Length: %d\r\n\r\n%s", len(b), b)
+	return err
+}
+
+// respond sends the result of the request with the given ID.
+func (s *lspServer) respond(id json.RawMessage, result interface{}) error {
+	return s.write(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"result":  result,
+	})
+}
+
+// respondError sends an error for the request with the given ID.
+func (s *lspServer) respondError(id json.RawMessage, code int, message string) error {
+	return s.write(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"error": map[string]interface{}{
+			"code":    code,
+			"message": message,
+		},
+	})
+}
+
+// handle handles a single message. Notifications receive no response.
+func (s *lspServer) handle(msg lspMessage) error {
+	var result interface{}
+	var err error
+	switch msg.Method {
+	case "initialize":
+		result = s.initialize()
+	case "shutdown":
+		s.shutdown = true
+	case "exit":
+		return errLSPExit
+	case "textDocument/didOpen":
+		var params struct {
+			TextDocument struct {
+				URI  string `json:"uri"`
+				Text string `json:"text"`
+			} `json:"textDocument"`
+		}
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			s.docs[params.TextDocument.URI] = params.TextDocument.Text
+		}
+	case "textDocument/didChange":
+		var params struct {
+			TextDocument struct {
+				URI string `json:"uri"`
+			} `json:"textDocument"`
+			ContentChanges []struct {
+				Text string `json:"text"`
+			} `json:"contentChanges"`
+		}
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			// Documents are synchronized in full, so only the last change is
+			// relevant.
+			if n := len(params.ContentChanges); n > 0 {
+				s.docs[params.TextDocument.URI] = params.ContentChanges[n-1].Text
+			}
+		}
+	case "textDocument/didClose":
+		var params lspTextDocumentPosition
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			delete(s.docs, params.TextDocument.URI)
+		}
+	case "textDocument/completion":
+		var params lspTextDocumentPosition
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			result = s.completion(s.prefix(params))
+		}
+	case "textDocument/hover":
+		var params lspTextDocumentPosition
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			result = s.hover(s.docs[params.TextDocument.URI], s.offset(params))
+		}
+	case "textDocument/signatureHelp":
+		var params lspTextDocumentPosition
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			result = s.signatureHelp(s.prefix(params))
+		}
+	default:
+		if msg.ID == nil {
+			// Unhandled notification.
+			return nil
+		}
+		return s.respondError(msg.ID, lspMethodNotFound, "method not found: "+msg.Method)
+	}
+	if msg.ID == nil {
+		return nil
+	}
+	if err != nil {
+		return s.respondError(msg.ID, lspInvalidParams, err.Error())
+	}
+	return s.respond(msg.ID, result)
+}
+
+// initialize returns the capabilities of the server.
+func (s *lspServer) initialize() interface{} {
+	return map[string]interface{}{
+		"capabilities": map[string]interface{}{
+			// Full synchronization.
+			"textDocumentSync": 1,
+			"completionProvider": map[string]interface{}{
+				"triggerCharacters": []string{".", ":"},
+			},
+			"hoverProvider": true,
+			"signatureHelpProvider": map[string]interface{}{
+				"triggerCharacters": []string{"(", ","},
+			},
+		},
+		"serverInfo": map[string]interface{}{
+			"name":    "rbxmk",
+			"version": VersionString(),
+		},
+	}
+}
+
+// offset returns the byte offset within the document of the given position.
+// Characters of a position are counted in UTF-16 code units.
+func (s *lspServer) offset(params lspTextDocumentPosition) int {
+	text := s.docs[params.TextDocument.URI]
+	i := 0
+	for line := 0; line < params.Position.Line; line++ {
+		j := strings.IndexByte(text[i:], '\n')
+		if j < 0 {
+			return len(text)
+		}
+		i += j + 1
+	}
+	for n := 0; n < params.Position.Character && i < len(text) && text[i] != '\n'; {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		n += len(utf16.Encode([]rune{r}))
+		i += size
+	}
+	return i
+}
+
+// prefix returns the content of the document that precedes the given
+// position.
+func (s *lspServer) prefix(params lspTextDocumentPosition) string {
+	return s.docs[params.TextDocument.URI][:s.offset(params)]
+}
+
+// isLSPIdent returns whether c can be a part of a Lua identifier.
+func isLSPIdent(c byte) bool {
+	return 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '_'
+}
+
+// lspExprBefore returns the indexing expression, such as "fs.read", that ends
+// at the end of text.
+func lspExprBefore(text string) string {
+	i := len(text)
+	for i > 0 && (isLSPIdent(text[i-1]) || text[i-1] == '.' || text[i-1] == ':') {
+		i--
+	}
+	return text[i:]
+}
+
+// splitLSPExpr splits an indexing expression into its names. method is
+// whether the last name is indexed as a method.
+func splitLSPExpr(expr string) (names []string, method bool) {
+	if i := strings.LastIndexByte(expr, ':'); i >= 0 {
+		method = true
+		expr = expr[:i] + "." + expr[i+1:]
+	}
+	return strings.Split(expr, "."), method
+}
+
+// lookup returns the value in the environment located at path.
+func (s *lspServer) lookup(path []string) *dump.EnvRef {
+	env := s.root.Environment
+	for _, name := range path {
+		if env == nil {
+			return nil
+		}
+		env = env.Fields[name]
+	}
+	return env
+}
+
+// describe returns the dump object that describes the value at path. Returns
+// nil if no such object exists.
+func (s *lspServer) describe(path []string, env *dump.EnvRef) interface{} {
+	if env == nil {
+		return nil
+	}
+	if len(env.Path) > 0 {
+		return s.root.Resolve(env.Path...)
+	}
+	name := strings.Join(path, ".")
+	if t, ok := s.root.Types[name]; ok {
+		return t
+	}
+	// A table of fields from one or more libraries.
+	for _, lib := range s.root.Libraries {
+		if len(lib.Import) > 0 && strings.Join(lib.Import, ".") == name {
+			return lib.Struct
+		}
+	}
+	for _, lib := range s.root.Libraries {
+		if len(lib.Import) == 0 {
+			if v, ok := lib.Struct.Fields[name].(dump.Struct); ok {
+				return v
+			}
+		}
+	}
+	return nil
+}
+
+// content returns the fragment referred to by ref, rendered as Markdown. Links
+// to other parts of the documentation are rendered as plain text.
+func (s *lspServer) content(ref string) string {
+	if ref == "" {
+		return ""
+	}
+	r := markdown.NewRenderer()
+	r.HeadingLevel = 3
+	r.ResolveLink = func(link string) string {
+		if strings.HasPrefix(link, "https:") || strings.HasPrefix(link, "http:") {
+			return link
+		}
+		return ""
+	}
+	return strings.TrimSpace(Frag.ResolveWith(ref, FragOptions{
+		Renderer: r.Render,
+		Inner:    true,
+	}))
+}
+
+// lspParameters returns a list of parameters, separated by commas.
+func lspParameters(params dump.Parameters) []string {
+	s := make([]string, len(params))
+	for i, p := range params {
+		if p.Name != "" {
+			s[i] = p.Name + ": "
+		}
+		s[i] += p.Type.String()
+	}
+	return s
+}
+
+// lspFunction returns the signature of fn, with name as the name of the
+// function.
+func lspFunction(name string, fn dump.Function) string {
+	s := name + "(" + strings.Join(lspParameters(fn.Parameters), ", ") + ")"
+	if len(fn.Returns) > 0 {
+		s += ": (" + strings.Join(lspParameters(fn.Returns), ", ") + ")"
+	}
+	return s
+}
+
+// lspSignatures returns the signatures of v, with name as the name of the value.
+func lspSignatures(name string, v interface{}) []string {
+	switch v := v.(type) {
+	case dump.Function:
+		return []string{lspFunction(name, v)}
+	case dump.MultiFunction:
+		s := make([]string, len(v))
+		for i, fn := range v {
+			s[i] = lspFunction(name, fn)
+		}
+		return s
+	case dump.Property:
+		s := name + ": " + v.ValueType.String()
+		if v.ReadOnly {
+			s += " (read-only)"
+		}
+		return []string{s}
+	case dump.TypeDef:
+		return []string{"type " + name}
+	case dump.Struct:
+		// Fields are listed by completion.
+		return nil
+	case dump.Value:
+		return []string{name + ": " + v.Type().String()}
+	}
+	return nil
+}
+
+// lspDocRefs returns the summary and description fragment references of v.
+func lspDocRefs(v interface{}) (summary, description string) {
+	switch v := v.(type) {
+	case dump.Function:
+		return v.Summary, v.Description
+	case dump.MultiFunction:
+		for _, fn := range v {
+			if fn.Description != "" {
+				return fn.Summary, fn.Description
+			}
+		}
+	case dump.Property:
+		return v.Summary, v.Description
+	case dump.Struct:
+		return v.Summary, v.Description
+	case dump.Enum:
+		return v.Summary, v.Description
+	case dump.TypeDef:
+		return v.Summary, v.Description
+	}
+	return "", ""
+}
+
+// lspKind returns the completion item kind of v.
+func lspKind(v interface{}, method bool) int {
+	switch v.(type) {
+	case dump.Function, dump.MultiFunction:
+		if method {
+			return lspKindMethod
+		}
+		return lspKindFunction
+	case dump.Property:
+		return lspKindField
+	case dump.Enum:
+		return lspKindEnum
+	case dump.TypeDef:
+		return lspKindClass
+	}
+	return lspKindModule
+}
+
+// completion returns the completion items of the expression that ends at the
+// end of text.
+func (s *lspServer) completion(text string) interface{} {
+	names, method := splitLSPExpr(lspExprBefore(text))
+	partial := names[len(names)-1]
+	parent := names[:len(names)-1]
+	env := s.lookup(parent)
+	items := []lspCompletionItem{}
+	if env == nil {
+		return items
+	}
+	for _, name := range sortedNames(env.Fields) {
+		if !strings.HasPrefix(name, partial) {
+			continue
+		}
+		path := append(append([]string{}, parent...), name)
+		v := s.describe(path, env.Fields[name])
+		item := lspCompletionItem{
+			Label: name,
+			Kind:  lspKind(v, method),
+		}
+		if sigs := lspSignatures(strings.Join(path, "."), v); len(sigs) > 0 {
+			item.Detail = strings.Join(sigs, "\n")
+		}
+		if summary, _ := lspDocRefs(v); summary != "" {
+			item.Documentation = &lspMarkup{Kind: "markdown", Value: s.content(summary)}
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// hover returns hover information for the expression at the given offset
+// within text.
+func (s *lspServer) hover(text string, offset int) interface{} {
+	j := offset
+	for j < len(text) && isLSPIdent(text[j]) {
+		j++
+	}
+	expr := lspExprBefore(text[:j])
+	if expr == "" {
+		return nil
+	}
+	names, _ := splitLSPExpr(expr)
+	v := s.describe(names, s.lookup(names))
+	if v == nil {
+		return nil
+	}
+	var value strings.Builder
+	if sigs := lspSignatures(strings.Join(names, "."), v); len(sigs) > 0 {
+		value.WriteString("```lua\n")
+		value.WriteString(strings.Join(sigs, "\n"))
+		value.WriteString("\n```")
+	}
+	if _, description := lspDocRefs(v); description != "" {
+		if c := s.content(description); c != "" {
+			if value.Len() > 0 {
+				value.WriteString("\n\n")
+			}
+			value.WriteString(c)
+		}
+	}
+	if value.Len() == 0 {
+		return nil
+	}
+	return map[string]interface{}{
+		"contents": lspMarkup{Kind: "markdown", Value: value.String()},
+	}
+}
+
+// lspCall returns the callee of the innermost function call that is open at
+// the end of text, and the index of the argument being written.
+func lspCall(text string) (callee string, arg int, ok bool) {
+	type frame struct {
+		callee string
+		args   int
+	}
+	var stack []frame
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; c {
+		case '-':
+			if !strings.HasPrefix(text[i:], "--") {
+				continue
+			}
+			if n := lspLongBracket(text[i+2:]); n >= 0 {
+				i = lspSkipLong(text, i+2, n)
+				continue
+			}
+			if j := strings.IndexByte(text[i:], '\n'); j >= 0 {
+				i += j
+			} else {
+				i = len(text)
+			}
+		case '"', '\'':
+			for i++; i < len(text) && text[i] != c && text[i] != '\n'; i++ {
+				if text[i] == '\\' {
+					i++
+				}
+			}
+		case '[':
+			if n := lspLongBracket(text[i:]); n >= 0 {
+				i = lspSkipLong(text, i, n)
+				continue
+			}
+			stack = append(stack, frame{})
+		case '(':
+			stack = append(stack, frame{callee: lspExprBefore(strings.TrimRight(text[:i], " \t\r\n"))})
+		case '{':
+			stack = append(stack, frame{})
+		case ')', '}', ']':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case ',':
+			if len(stack) > 0 {
+				stack[len(stack)-1].args++
+			}
+		}
+	}
+	if len(stack) == 0 {
+		return "", 0, false
+	}
+	top := stack[len(stack)-1]
+	return top.callee, top.args, top.callee != ""
+}
+
+// lspLongBracket returns the level of the opening long bracket at the start of
+// text, or -1 if there is no long bracket.
+func lspLongBracket(text string) int {
+	if !strings.HasPrefix(text, "[") {
+		return -1
+	}
+	n := 0
+	for n+1 < len(text) && text[n+1] == '=' {
+		n++
+	}
+	if n+1 < len(text) && text[n+1] == '[' {
+		return n
+	}
+	return -1
+}
+
+// lspSkipLong returns the index of the last character of the long bracket of
+// level n that starts at i, or the end of text if the bracket is not closed.
+func lspSkipLong(text string, i, n int) int {
+	end := "]" + strings.Repeat("=", n) + "]"
+	if j := strings.Index(text[i+n+2:], end); j >= 0 {
+		return i + n + 2 + j + len(end) - 1
+	}
+	return len(text)
+}
+
+// signatureHelp returns the signatures of the function being called at the
+// end of text.
+func (s *lspServer) signatureHelp(text string) interface{} {
+	callee, arg, ok := lspCall(text)
+	if !ok {
+		return nil
+	}
+	names, method := splitLSPExpr(callee)
+	if method {
+		// The type of the receiver is unknown.
+		return nil
+	}
+	v := s.describe(names, s.lookup(names))
+	var fns dump.MultiFunction
+	switch v := v.(type) {
+	case dump.Function:
+		fns = dump.MultiFunction{v}
+	case dump.MultiFunction:
+		fns = v
+	default:
+		return nil
+	}
+	name := strings.Join(names, ".")
+	signatures := make([]lspSignature, len(fns))
+	active := -1
+	for i, fn := range fns {
+		sig := lspSignature{
+			Label:      lspFunction(name, fn),
+			Parameters: []lspLabelValue{},
+		}
+		for _, p := range lspParameters(fn.Parameters) {
+			sig.Parameters = append(sig.Parameters, lspLabelValue{Label: p})
+		}
+		if fn.Summary != "" {
+			sig.Documentation = &lspMarkup{Kind: "markdown", Value: s.content(fn.Summary)}
+		}
+		signatures[i] = sig
+		if active < 0 && lspActiveParameter(fn.Parameters, arg) < len(fn.Parameters) {
+			// First signature that can receive the argument.
+			active = i
+		}
+	}
+	if active < 0 {
+		active = 0
+	}
+	return map[string]interface{}{
+		"signatures":      signatures,
+		"activeSignature": active,
+		"activeParameter": lspActiveParameter(fns[active].Parameters, arg),
+	}
+}
+
+// lspActiveParameter returns the index of the parameter that receives the
+// argument at index arg, accounting for a variadic last parameter.
+func lspActiveParameter(params dump.Parameters, arg int) int {
+	if n := len(params); n > 0 && arg >= n && params[n-1].Name == "..." {
+		return n - 1
+	}
+	return arg
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/anaminus/rbxmk/library"
+)
+
+func TestLSP(t *testing.T) {
+	root, err := GenerateDump(WorldOpt{
+		IncludeLibraries: library.All(),
+		ExcludeRoots:     true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var in bytes.Buffer
+	send := func(id int, method string, params interface{}) {
+		msg := map[string]interface{}{"jsonrpc": "2.0", "method": method, "params": params}
+		if id > 0 {
+			msg["id"] = id
+		}
+		b, _ := json.Marshal(msg)
+		fmt.Fprintf(&in, "Content-Length: %d\r\n\r\n%s", len(b), b)
+	}
+	const uri = "file:///script.lua"
+	const text = "local v = fs.rea\nfs.write(\"a.txt\", "
+	position := func(line, char int) map[string]interface{} {
+		return map[string]interface{}{
+			"textDocument": map[string]interface{}{"uri": uri},
+			"position":     map[string]interface{}{"line": line, "character": char},
+		}
+	}
+	send(1, "initialize", map[string]interface{}{})
+	send(0, "initialized", map[string]interface{}{})
+	send(0, "textDocument/didOpen", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri, "text": text},
+	})
+	send(2, "textDocument/completion", position(0, 16))
+	send(3, "textDocument/hover", position(1, 4))
+	send(4, "textDocument/signatureHelp", position(1, 18))
+	send(5, "unknown", nil)
+	send(6, "shutdown", nil)
+	send(0, "exit", nil)
+
+	var out bytes.Buffer
+	if err := newLSPServer(root, &in, &out).serve(); err != nil {
+		t.Fatal(err)
+	}
+
+	responses := map[int]json.RawMessage{}
+	r := bufio.NewReader(&out)
+	for {
+		s := newLSPServer(root, r, nil)
+		b, err := s.read()
+		if err != nil {
+			break
+		}
+		var msg struct {
+			ID     int
+			Result json.RawMessage
+			Error  json.RawMessage
+		}
+		if err := json.Unmarshal(b, &msg); err != nil {
+			t.Fatal(err)
+		}
+		if msg.Error != nil {
+			responses[msg.ID] = msg.Error
+		} else {
+			responses[msg.ID] = msg.Result
+		}
+	}
+
+	var completion []lspCompletionItem
+	json.Unmarshal(responses[2], &completion)
+	var labels []string
+	for _, item := range completion {
+		labels = append(labels, item.Label)
+	}
+	if got := strings.Join(labels, ","); got != "read,readdir" {
+		t.Errorf("completion: expected read,readdir, got %s", got)
+	}
+
+	var hover struct{ Contents lspMarkup }
+	json.Unmarshal(responses[3], &hover)
+	if !strings.HasPrefix(hover.Contents.Value, "```lua\nfs.write(") {
+		t.Errorf("hover: unexpected content %q", hover.Contents.Value)
+	}
+
+	var help struct {
+		Signatures      []lspSignature
+		ActiveParameter int
+	}
+	json.Unmarshal(responses[4], &help)
+	if len(help.Signatures) != 1 || help.ActiveParameter != 1 {
+		t.Errorf("signature help: unexpected result %s", responses[4])
+	}
+
+	if !bytes.Contains(responses[5], []byte(`"code":-32601`)) {
+		t.Errorf("unknown method: unexpected result %s", responses[5])
+	}
+	if string(responses[6]) != "null" {
+		t.Errorf("shutdown: unexpected result %s", responses[6])
+	}
+}
+
+func TestLSPCall(t *testing.T) {
+	tests := []struct {
+		text   string
+		callee string
+		arg    int
+		ok     bool
+	}{
+		{"f(", "f", 0, true},
+		{"fs.read(a, ", "fs.read", 1, true},
+		{"f(g(a), {1, 2}, ", "f", 2, true},
+		{"f(\"a, (\", ", "f", 1, true},
+		{"f(a) -- g(", "", 0, false},
+		{"f([[a, (]], b", "f", 1, true},
+		{"x:Foo(a", "x:Foo", 0, true},
+		{"f(a)", "", 0, false},
+	}
+	for _, test := range tests {
+		callee, arg, ok := lspCall(test.text)
+		if callee != test.callee || arg != test.arg || ok != test.ok {
+			t.Errorf("%q: expected (%q, %d, %v), got (%q, %d, %v)", test.text, test.callee, test.arg, test.ok, callee, arg, ok)
+		}
+	}
+}
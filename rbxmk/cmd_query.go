@@ -0,0 +1,233 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/anaminus/cobra"
+	"github.com/anaminus/pflag"
+	"github.com/anaminus/rbxmk/dump"
+	"github.com/anaminus/rbxmk/rtypes"
+)
+
+func init() {
+	var c QueryCommand
+	var cmd = Register.NewCommand(dump.Command{
+		Arguments:   "Commands/query:Arguments",
+		Summary:     "Commands/query:Summary",
+		Description: "Commands/query:Description",
+	}, &cobra.Command{
+		Use:  "query",
+		Args: cobra.RangeArgs(1, 2),
+		RunE: c.Run,
+	})
+	c.SetFlags(cmd.Flags())
+	Program.AddCommand(cmd)
+}
+
+type QueryCommand struct {
+	DescFlags
+	Format       string
+	FileFormat   string
+	Properties   []string
+	AttrProperty string
+}
+
+func (c *QueryCommand) SetFlags(flags *pflag.FlagSet) {
+	c.DescFlags.SetFlags(flags)
+
+	flags.StringVarP(&c.Format, "format", "f", "tree", "")
+	Register.NewFlag(dump.Flag{Description: "Commands/query:Flags/format"}, flags, "format")
+
+	flags.StringVar(&c.FileFormat, "file-format", "", "")
+	Register.NewFlag(dump.Flag{Description: "Commands/query:Flags/file-format"}, flags, "file-format")
+
+	flags.StringArrayVarP(&c.Properties, "property", "p", nil, "")
+	Register.NewFlag(dump.Flag{Description: "Commands/query:Flags/property"}, flags, "property")
+
+	flags.StringVar(&c.AttrProperty, "attr-property", "", "")
+	Register.NewFlag(dump.Flag{Description: "Commands/query:Flags/attr-property"}, flags, "attr-property")
+}
+
+func (c *QueryCommand) Run(cmd *cobra.Command, args []string) error {
+	file := args[0]
+	selector := "*"
+	if len(args) > 1 {
+		selector = args[1]
+	}
+	sel, err := rtypes.ParseSelector(selector)
+	if err != nil {
+		return err
+	}
+
+	var write func(w io.Writer, entries []*queryEntry) error
+	switch c.Format {
+	case "tree":
+		write = writeQueryTree
+	case "properties":
+		write = writeQueryProperties
+	case "json":
+		write = writeQueryJSON
+	default:
+		return fmt.Errorf("unknown format %q", c.Format)
+	}
+	if c.Format == "tree" && len(c.Properties) > 0 {
+		// The tree format prints no values, so there is nothing to limit.
+		return fmt.Errorf("--property cannot be used with the tree format")
+	}
+
+	// Initialize world.
+	world, err := InitWorld(WorldOpt{
+		WorldFlags:     WorldFlags{Debug: false},
+		ExcludeRoots:   true,
+		ExcludeProgram: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Initialize global descriptor.
+	world.Desc, err = c.DescFlags.Resolve(world.Client)
+	if err != nil {
+		return err
+	}
+	if c.AttrProperty != "" {
+		world.AttrConfig = &rtypes.AttrConfig{Property: c.AttrProperty}
+	}
+
+	root, err := decodeInstanceFile(world, file, rtypes.FormatSelector{Format: c.FileFormat})
+	if err != nil {
+		return err
+	}
+
+	var matches []*rtypes.Instance
+	if c.Format == "tree" && len(args) == 1 {
+		// Show the entire tree.
+		matches = root.Children()
+	} else {
+		matches = sel.QueryAll(world.Global, root)
+	}
+	entries := make([]*queryEntry, len(matches))
+	for i, inst := range matches {
+		entries[i] = newQueryEntry(world.Global, inst, c.Properties, c.Format == "tree")
+	}
+	return write(cmd.OutOrStdout(), entries)
+}
+
+// queryEntry is a single instance reported by the query command.
+type queryEntry struct {
+	// Path is the path of the instance from the root of the file.
+	Path      []string
+	ClassName string
+	// Properties and Attributes are the values of the instance, excluding
+	// Name, which is included in Path. Attributes is omitted when the instance
+	// has none.
+	Properties map[string]*diffValue
+	Attributes map[string]*diffValue `json:",omitempty"`
+
+	// Entries for each child, when the tree is included.
+	children []*queryEntry
+	// Name of the instance, which is the last element of Path.
+	name string
+}
+
+// newQueryEntry returns an entry for inst. If tree is true, then entries for
+// descendants are included instead of values. Otherwise, if properties is not
+// empty, then only the listed properties and attributes are included. g
+// determines the property that holds serialized attributes.
+func newQueryEntry(g rtypes.Global, inst *rtypes.Instance, properties []string, tree bool) *queryEntry {
+	entry := &queryEntry{
+		Path:       instancePath(inst),
+		ClassName:  inst.ClassName,
+		Properties: map[string]*diffValue{},
+		Attributes: map[string]*diffValue{},
+		name:       inst.Name(),
+	}
+	if tree {
+		for _, child := range inst.Children() {
+			entry.children = append(entry.children, newQueryEntry(g, child, nil, true))
+		}
+		return entry
+	}
+	include := func(name string) bool {
+		if len(properties) == 0 {
+			return true
+		}
+		for _, p := range properties {
+			if p == name {
+				return true
+			}
+		}
+		return false
+	}
+	for _, name := range inst.PropertyNames() {
+		value := inst.Get(name)
+		if name == "Name" {
+			continue
+		}
+		if name == attrProperty(g, inst) {
+			if attrs, ok := decodeDiffAttributes(value); ok {
+				for attr, v := range attrs {
+					if include(attr) {
+						entry.Attributes[attr] = newDiffValue(v)
+					}
+				}
+				continue
+			}
+		}
+		if include(name) {
+			entry.Properties[name] = newDiffValue(value)
+		}
+	}
+	return entry
+}
+
+// writeQueryTree writes each entry to w as its path, followed by a tree of its
+// descendants, one instance per line.
+func writeQueryTree(w io.Writer, entries []*queryEntry) error {
+	var buf bytes.Buffer
+	var walk func(entries []*queryEntry, indent string)
+	walk = func(entries []*queryEntry, indent string) {
+		for i, entry := range entries {
+			branch, next := "├─ ", "│  "
+			if i == len(entries)-1 {
+				branch, next = "└─ ", "   "
+			}
+			fmt.Fprintf(&buf, "%s%s%s (%s)\n", indent, branch, entry.name, entry.ClassName)
+			walk(entry.children, indent+next)
+		}
+	}
+	for _, entry := range entries {
+		fmt.Fprintf(&buf, "%s (%s)\n", formatDiffPath(entry.Path), entry.ClassName)
+		walk(entry.children, "")
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// writeQueryProperties writes each entry to w as its path, followed by its
+// properties and attributes on indented lines.
+func writeQueryProperties(w io.Writer, entries []*queryEntry) error {
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		fmt.Fprintf(&buf, "%s (%s)\n", formatDiffPath(entry.Path), entry.ClassName)
+		for _, name := range sortedNames(entry.Properties) {
+			fmt.Fprintf(&buf, "\t%s: %s\n", name, formatDiffValue(entry.Properties[name]))
+		}
+		for _, name := range sortedNames(entry.Attributes) {
+			fmt.Fprintf(&buf, "\t@%s: %s\n", name, formatDiffValue(entry.Attributes[name]))
+		}
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// writeQueryJSON writes entries to w as an indented JSON array.
+func writeQueryJSON(w io.Writer, entries []*queryEntry) error {
+	j := json.NewEncoder(w)
+	j.SetIndent("", "\t")
+	j.SetEscapeHTML(false)
+	return j.Encode(entries)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/anaminus/cobra"
+	"github.com/anaminus/rbxmk/rtypes"
+	"github.com/robloxapi/types"
+)
+
+func TestQuery(t *testing.T) {
+	root := rtypes.NewDataModel()
+	workspace := rtypes.NewInstance("Workspace", root)
+	workspace.SetName("Workspace")
+	model := rtypes.NewInstance("Model", workspace)
+	model.SetName("Model")
+	part := rtypes.NewInstance("Part", model)
+	part.SetName("Part")
+	part.Set("CanCollide", types.Bool(false))
+	part.Set("Size", types.Vector3{X: 1, Y: 2, Z: 3})
+	var attrs bytes.Buffer
+	if err := rtypes.EncodeAttributes(&attrs, rtypes.Dictionary{"Health": types.Double(100)}); err != nil {
+		t.Fatal(err)
+	}
+	part.Set(diffAttrProperty, types.BinaryString(attrs.Bytes()))
+	rtypes.NewInstance("Part", model).SetName("Other")
+
+	var w bytes.Buffer
+	entries := []*queryEntry{}
+	for _, inst := range root.Children() {
+		entries = append(entries, newQueryEntry(rtypes.Global{}, inst, nil, true))
+	}
+	if err := writeQueryTree(&w, entries); err != nil {
+		t.Fatal(err)
+	}
+	want := "Workspace (Workspace)\n" +
+		"└─ Model (Model)\n" +
+		"   ├─ Part (Part)\n" +
+		"   └─ Other (Part)\n"
+	if got := w.String(); got != want {
+		t.Errorf("unexpected tree output:\n%s\nwant:\n%s", got, want)
+	}
+
+	sel, err := rtypes.ParseSelector("Model > Part[.CanCollide=false]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries = entries[:0]
+	for _, inst := range sel.QueryAll(rtypes.Global{}, root) {
+		entries = append(entries, newQueryEntry(rtypes.Global{}, inst, []string{"Size", "Health"}, false))
+	}
+	w.Reset()
+	if err := writeQueryProperties(&w, entries); err != nil {
+		t.Fatal(err)
+	}
+	want = "Workspace.Model.Part (Part)\n" +
+		"\tSize: 1, 2, 3\n" +
+		"\t@Health: 100\n"
+	if got := w.String(); got != want {
+		t.Errorf("unexpected properties output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestQueryJSON(t *testing.T) {
+	root := rtypes.NewDataModel()
+	part := rtypes.NewInstance("Part", root)
+	part.SetName("Part")
+	part.Set("CanCollide", types.Bool(false))
+
+	var w bytes.Buffer
+	entries := []*queryEntry{newQueryEntry(rtypes.Global{}, part, nil, false)}
+	if err := writeQueryJSON(&w, entries); err != nil {
+		t.Fatal(err)
+	}
+	var got []map[string]interface{}
+	if err := json.Unmarshal(w.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := got[0]["Properties"]; !ok {
+		t.Errorf("expected Properties in JSON output")
+	}
+	if _, ok := got[0]["Attributes"]; ok {
+		t.Errorf("unexpected empty Attributes in JSON output")
+	}
+}
+
+func TestQueryTreeProperty(t *testing.T) {
+	c := QueryCommand{Format: "tree", Properties: []string{"Size"}}
+	err := c.Run(&cobra.Command{}, []string{filepath.Join(t.TempDir(), "place.rbxl")})
+	if err == nil || !strings.Contains(err.Error(), "--property") {
+		t.Errorf("expected error for --property with tree format, got %v", err)
+	}
+}
@@ -0,0 +1,525 @@
+package main
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anaminus/cobra"
+	lua "github.com/anaminus/gopher-lua"
+	"github.com/anaminus/pflag"
+	"github.com/anaminus/rbxmk"
+	"github.com/anaminus/rbxmk/dump"
+	"github.com/anaminus/rbxmk/dump/dt"
+	"github.com/anaminus/rbxmk/library"
+	"github.com/anaminus/rbxmk/rtypes"
+)
+
+func init() {
+	var c TestCommand
+	var cmd = Register.NewCommand(dump.Command{
+		Arguments:   "Commands/test:Arguments",
+		Summary:     "Commands/test:Summary",
+		Description: "Commands/test:Description",
+	}, &cobra.Command{
+		Use:  "test",
+		RunE: c.Run,
+	})
+	c.SetFlags(cmd.Flags())
+	Program.AddCommand(cmd)
+}
+
+// testFileSuffixes are the suffixes of files discovered as test scripts.
+var testFileSuffixes = []string{".test.lua", ".test.luau"}
+
+// isTestFile returns whether name is the name of a test script.
+func isTestFile(name string) bool {
+	for _, suffix := range testFileSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// findTestFiles returns the test scripts within each path. A path that is a
+// file is returned as-is. A path that is a directory is walked for test
+// scripts.
+func findTestFiles(paths []string) (files []string, err error) {
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		err = filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() && isTestFile(info.Name()) {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+type TestCommand struct {
+	WorldFlags
+	DescFlags
+	Format string
+}
+
+func (c *TestCommand) SetFlags(flags *pflag.FlagSet) {
+	c.WorldFlags.SetFlags(flags)
+	c.DescFlags.SetFlags(flags)
+
+	flags.StringVar(&c.Format, "format", "tap", "")
+	Register.NewFlag(dump.Flag{Description: "Commands/test:Flags/format"}, flags, "format")
+}
+
+func (c *TestCommand) Run(cmd *cobra.Command, args []string) error {
+	var report func(w io.Writer, suites []*testSuite) error
+	switch c.Format {
+	case "tap":
+		report = writeTAP
+	case "junit":
+		report = writeJUnit
+	default:
+		return fmt.Errorf("unknown report format %q", c.Format)
+	}
+
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+	files, err := findTestFiles(args)
+	if err != nil {
+		return err
+	}
+
+	var desc *rtypes.Desc
+	var descResolved bool
+	suites := make([]*testSuite, 0, len(files))
+	for _, file := range files {
+		suite := &testSuite{File: filepath.ToSlash(shortenPath(filepath.Clean(file)))}
+		suites = append(suites, suite)
+
+		// Each file runs within a fresh world.
+		world, err := InitWorld(WorldOpt{
+			WorldFlags:       c.WorldFlags,
+			IncludeLibraries: append(library.All(), testLibrary(suite)),
+		})
+		if err != nil {
+			return err
+		}
+		injectSSLKeyLogFile(world, cmd.ErrOrStderr())
+
+		// Resolve the global descriptor once, and share it between worlds.
+		if !descResolved {
+			if desc, err = c.DescFlags.Resolve(world.Client); err != nil {
+				return err
+			}
+			descResolved = true
+		}
+		world.Desc = desc
+		world.SetEnumGlobal()
+
+		start := time.Now()
+		if err := world.DoFile(shortenPath(filepath.Clean(file)), 0); err != nil {
+			suite.Error = testErrorMessage(err)
+		}
+		suite.Time = time.Since(start)
+	}
+
+	if err := report(cmd.OutOrStdout(), suites); err != nil {
+		return err
+	}
+
+	var total, failed int
+	for _, suite := range suites {
+		total += len(suite.Cases)
+		failed += suite.Failures()
+		if suite.Error != "" {
+			total++
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d tests failed", failed, total)
+	}
+	return nil
+}
+
+// testSuite contains the results of running a test script.
+type testSuite struct {
+	// File is the path to the script.
+	File string
+	// Cases are the results of each test made by the script.
+	Cases []testCase
+	// Error is set if the script failed outside of a test.
+	Error string
+	// Time is the duration of the script.
+	Time time.Duration
+
+	// scope is the stack of names of the currently running describe blocks.
+	scope []string
+}
+
+// Failures returns the number of failed cases.
+func (s *testSuite) Failures() int {
+	n := 0
+	for _, c := range s.Cases {
+		if c.Failure != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// testCase contains the result of a single test.
+type testCase struct {
+	// Name is the name of the test, prefixed by the names of enclosing describe
+	// blocks.
+	Name string
+	// Failure is the error produced by the test, or empty if the test passed.
+	Failure string
+	// Time is the duration of the test.
+	Time time.Duration
+}
+
+// testErrorMessage returns the message of an error produced by a script,
+// excluding any stack trace.
+func testErrorMessage(err error) string {
+	if err, ok := err.(*lua.ApiError); ok {
+		return err.Object.String()
+	}
+	return err.Error()
+}
+
+// testLibrary returns a library that provides functions for writing tests.
+// Results are recorded to suite, which may be nil if the library is opened only
+// to be dumped.
+func testLibrary(suite *testSuite) rbxmk.Library {
+	return rbxmk.Library{
+		Name:     "test",
+		Priority: 10,
+		Open: func(s rbxmk.State) *lua.LTable {
+			lib := s.L.CreateTable(0, 3)
+			lib.RawSetString("describe", s.WrapFunc(func(s rbxmk.State) int {
+				return testDescribe(s, suite)
+			}))
+			lib.RawSetString("it", s.WrapFunc(func(s rbxmk.State) int {
+				return testIt(s, suite)
+			}))
+			lib.RawSetString("expect", s.WrapFunc(testExpect))
+			return lib
+		},
+		Dump: func(s rbxmk.State) dump.Library {
+			return dump.Library{
+				Struct: dump.Struct{
+					Fields: dump.Fields{
+						"describe": dump.Function{
+							Parameters: dump.Parameters{
+								{Name: "name", Type: dt.Prim(rtypes.T_String)},
+								{Name: "body", Type: dt.Prim(rtypes.T_LuaFunction)},
+							},
+							Summary:     "Libraries/test:Fields/describe/Summary",
+							Description: "Libraries/test:Fields/describe/Description",
+						},
+						"it": dump.Function{
+							Parameters: dump.Parameters{
+								{Name: "name", Type: dt.Prim(rtypes.T_String)},
+								{Name: "body", Type: dt.Prim(rtypes.T_LuaFunction)},
+							},
+							Summary:     "Libraries/test:Fields/it/Summary",
+							Description: "Libraries/test:Fields/it/Description",
+						},
+						"expect": dump.Function{
+							Parameters: dump.Parameters{
+								{Name: "value", Type: dt.Prim(rtypes.T_Any)},
+							},
+							Returns: dump.Parameters{
+								{Type: dt.Prim(rtypes.T_LuaTable)},
+							},
+							Summary:     "Libraries/test:Fields/expect/Summary",
+							Description: "Libraries/test:Fields/expect/Description",
+						},
+					},
+					Summary:     "Libraries/test:Summary",
+					Description: "Libraries/test:Description",
+				},
+			}
+		},
+	}
+}
+
+// testDescribe groups the tests made within a function under a name.
+func testDescribe(s rbxmk.State, suite *testSuite) int {
+	name := s.CheckString(1)
+	body := s.CheckFunction(2)
+	suite.scope = append(suite.scope, name)
+	s.L.Push(body)
+	err := s.L.PCall(0, 0, nil)
+	suite.scope = suite.scope[:len(suite.scope)-1]
+	if err != nil {
+		// An error outside of a test fails the entire group, without
+		// preventing other groups from running.
+		suite.Cases = append(suite.Cases, testCase{
+			Name:    strings.Join(append(suite.scope, name), " "),
+			Failure: testErrorMessage(err),
+		})
+	}
+	return 0
+}
+
+// testIt runs a function as a single test, which fails if the function throws
+// an error.
+func testIt(s rbxmk.State, suite *testSuite) int {
+	name := s.CheckString(1)
+	body := s.CheckFunction(2)
+	c := testCase{Name: strings.Join(append(suite.scope, name), " ")}
+	start := time.Now()
+	s.L.Push(body)
+	if err := s.L.PCall(0, 0, nil); err != nil {
+		c.Failure = testErrorMessage(err)
+	}
+	c.Time = time.Since(start)
+	suite.Cases = append(suite.Cases, c)
+	return 0
+}
+
+// testExpect returns a table of matchers that assert properties of a value.
+// Each matcher throws an error if its assertion does not hold. The "never"
+// field contains the same matchers with each assertion negated.
+func testExpect(s rbxmk.State) int {
+	value := s.CheckAny(1)
+	matchers := testMatchers(s, value, false)
+	matchers.RawSetString("never", testMatchers(s, value, true))
+	s.L.Push(matchers)
+	return 1
+}
+
+// testMatchers returns a table of matchers for value. If negate is true, then
+// each assertion is negated.
+func testMatchers(s rbxmk.State, value lua.LValue, negate bool) *lua.LTable {
+	matchers := s.L.CreateTable(0, 8)
+	matcher := func(name string, match func(s rbxmk.State) (ok bool, desc string)) {
+		matchers.RawSetString(name, s.WrapFunc(func(s rbxmk.State) int {
+			ok, desc := match(s)
+			if ok == negate {
+				not := ""
+				if negate {
+					not = "not "
+				}
+				// Locate the error at the caller of the matcher.
+				where := s.L.Where(1)
+				if where != "" {
+					where += " "
+				}
+				return s.RaiseError("%sexpected %s %s%s", where, testFormatValue(s, value), not, desc)
+			}
+			return 0
+		}))
+	}
+	matcher("toBe", func(s rbxmk.State) (bool, string) {
+		other := s.CheckAny(1)
+		return s.L.Equal(value, other), "to be " + testFormatValue(s, other)
+	})
+	matcher("toEqual", func(s rbxmk.State) (bool, string) {
+		other := s.CheckAny(1)
+		return testDeepEqual(s, value, other, map[[2]lua.LValue]bool{}), "to equal " + testFormatValue(s, other)
+	})
+	matcher("toBeTruthy", func(s rbxmk.State) (bool, string) {
+		return lua.LVAsBool(value), "to be truthy"
+	})
+	matcher("toBeFalsy", func(s rbxmk.State) (bool, string) {
+		return !lua.LVAsBool(value), "to be falsy"
+	})
+	matcher("toBeNil", func(s rbxmk.State) (bool, string) {
+		return value == lua.LNil, "to be nil"
+	})
+	matcher("toBeType", func(s rbxmk.State) (bool, string) {
+		t := s.CheckString(1)
+		return s.World.Typeof(value) == t, "to be of type " + t
+	})
+	matcher("toThrow", func(s rbxmk.State) (bool, string) {
+		msg := s.OptString(1, "")
+		fn, ok := value.(*lua.LFunction)
+		if !ok {
+			s.RaiseError("expected function, got %s", s.World.Typeof(value))
+			return false, ""
+		}
+		s.L.Push(fn)
+		err := s.L.PCall(0, 0, nil)
+		if msg == "" {
+			return err != nil, "to throw"
+		}
+		return err != nil && strings.Contains(testErrorMessage(err), msg), "to throw " + strconv.Quote(msg)
+	})
+	return matchers
+}
+
+// testFormatValue returns a representation of a value for use in a message.
+func testFormatValue(s rbxmk.State, v lua.LValue) string {
+	if v, ok := v.(lua.LString); ok {
+		return strconv.Quote(string(v))
+	}
+	return s.L.ToStringMeta(v).String()
+}
+
+// testDeepEqual returns whether a and b are equal, comparing the contents of
+// tables recursively. visited contains pairs of tables that are already being
+// compared.
+func testDeepEqual(s rbxmk.State, a, b lua.LValue, visited map[[2]lua.LValue]bool) bool {
+	ta, ok := a.(*lua.LTable)
+	if !ok {
+		return s.L.Equal(a, b)
+	}
+	tb, ok := b.(*lua.LTable)
+	if !ok {
+		return false
+	}
+	if ta == tb || visited[[2]lua.LValue{ta, tb}] {
+		return true
+	}
+	visited[[2]lua.LValue{ta, tb}] = true
+	errUnequal := errors.New("unequal")
+	err := ta.ForEach(func(k, v lua.LValue) error {
+		if !testDeepEqual(s, v, tb.RawGet(k), visited) {
+			return errUnequal
+		}
+		return nil
+	})
+	if err != nil {
+		return false
+	}
+	err = tb.ForEach(func(k, v lua.LValue) error {
+		if ta.RawGet(k) == lua.LNil {
+			return errUnequal
+		}
+		return nil
+	})
+	return err == nil
+}
+
+// writeTAP writes the results of suites in the Test Anything Protocol format.
+func writeTAP(w io.Writer, suites []*testSuite) error {
+	var b strings.Builder
+	b.WriteString("TAP version 13\n")
+	n := 0
+	point := func(name, failure string) {
+		n++
+		if failure == "" {
+			fmt.Fprintf(&b, "ok %d - %s\n", n, name)
+			return
+		}
+		fmt.Fprintf(&b, "not ok %d - %s\n", n, name)
+		fmt.Fprintf(&b, "  ---\n  message: %s\n  ...\n", strconv.Quote(failure))
+	}
+	for _, suite := range suites {
+		for _, c := range suite.Cases {
+			point(suite.File+": "+c.Name, c.Failure)
+		}
+		if suite.Error != "" {
+			point(suite.File, suite.Error)
+		}
+	}
+	fmt.Fprintf(&b, "1..%d\n", n)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+	Error    *junitFailure   `xml:"error,omitempty"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Content string `xml:",chardata"`
+}
+
+// junitTime formats a duration as seconds.
+func junitTime(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+}
+
+// writeJUnit writes the results of suites in the JUnit XML format.
+func writeJUnit(w io.Writer, suites []*testSuite) error {
+	var root junitTestSuites
+	var total time.Duration
+	for _, suite := range suites {
+		s := junitTestSuite{
+			Name:     suite.File,
+			Tests:    len(suite.Cases),
+			Failures: suite.Failures(),
+			Time:     junitTime(suite.Time),
+		}
+		for _, c := range suite.Cases {
+			tc := junitTestCase{
+				Name:      c.Name,
+				ClassName: suite.File,
+				Time:      junitTime(c.Time),
+			}
+			if c.Failure != "" {
+				tc.Failure = &junitFailure{Message: c.Failure, Content: c.Failure}
+			}
+			s.Cases = append(s.Cases, tc)
+		}
+		if suite.Error != "" {
+			s.Errors = 1
+			s.Error = &junitFailure{Message: suite.Error, Content: suite.Error}
+		}
+		root.Suites = append(root.Suites, s)
+		root.Tests += s.Tests
+		root.Failures += s.Failures
+		root.Errors += s.Errors
+		total += suite.Time
+	}
+	root.Time = junitTime(total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	e := xml.NewEncoder(w)
+	e.Indent("", "\t")
+	if err := e.Encode(root); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/anaminus/cobra"
+)
+
+const testRunnerScript = `
+describe("math", function()
+	it("adds", function()
+		expect(1 + 1).toBe(2)
+	end)
+	it("compares tables", function()
+		expect({1, {a = 2}}).toEqual({1, {a = 2}})
+		expect({1, {a = 2}}).never.toEqual({1, {a = 3}})
+	end)
+	it("fails", function()
+		expect(1).toBe(2)
+	end)
+end)
+it("throws", function()
+	expect(function() error("boom") end).toThrow("boom")
+	expect(Vector3.new(1, 2, 3)).toBeType("Vector3")
+	expect(nil).toBeNil()
+end)
+`
+
+func runTestCommand(args ...string) (out string, err error) {
+	program := &cobra.Command{
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	var c TestCommand
+	cmd := &cobra.Command{
+		Use:  "test",
+		RunE: c.Run,
+	}
+	c.SetFlags(cmd.Flags())
+	program.AddCommand(cmd)
+
+	var buf bytes.Buffer
+	program.SetOut(&buf)
+	program.SetArgs(append([]string{"test"}, args...))
+	err = program.Execute()
+	return buf.String(), err
+}
+
+func TestTestCommand(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "math.test.lua"), []byte(testRunnerScript), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "error.test.lua"), []byte(`error("outside")`), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "ignored.lua"), []byte(`error("not a test")`), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runTestCommand(dir)
+	if err == nil || err.Error() != "2 of 5 tests failed" {
+		t.Errorf("expected 2 of 5 tests to fail, got error %v", err)
+	}
+	lines := strings.Split(out, "\n")
+	var points []string
+	for _, line := range lines {
+		if strings.HasPrefix(line, "ok ") || strings.HasPrefix(line, "not ok ") {
+			// Strip directory of file.
+			if i := strings.Index(line, " - "); i >= 0 {
+				line = line[:i+3] + filepath.Base(line[i+3:])
+			}
+			points = append(points, line)
+		}
+	}
+	want := []string{
+		"not ok 1 - error.test.lua",
+		"ok 2 - math.test.lua: math adds",
+		"ok 3 - math.test.lua: math compares tables",
+		"not ok 4 - math.test.lua: math fails",
+		"ok 5 - math.test.lua: throws",
+	}
+	if got := strings.Join(points, "\n"); got != strings.Join(want, "\n") {
+		t.Errorf("unexpected test points:\n%s", got)
+	}
+	if !strings.Contains(out, `message: "`) || !strings.Contains(out, `:11: expected 1 to be 2"`) {
+		t.Errorf("expected failure message with location:\n%s", out)
+	}
+	if lines[0] != "TAP version 13" || !strings.Contains(out, "\n1..5\n") {
+		t.Errorf("unexpected TAP framing:\n%s", out)
+	}
+
+	out, _ = runTestCommand("--format", "junit", filepath.Join(dir, "math.test.lua"))
+	var suites junitTestSuites
+	if err := xml.Unmarshal([]byte(out), &suites); err != nil {
+		t.Fatalf("decode junit: %s", err)
+	}
+	if suites.Tests != 4 || suites.Failures != 1 || len(suites.Suites) != 1 {
+		t.Errorf("unexpected junit results: %+v", suites)
+	}
+}
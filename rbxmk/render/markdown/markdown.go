@@ -0,0 +1,399 @@
+// The markdown package implements rendering content as Markdown.
+package markdown
+
+import (
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// Renderer implements rendering HTML content as Markdown.
+type Renderer struct {
+	// HeadingLevel is the level of the heading produced for the outer-most
+	// section. Nested sections produce headings of increasing levels. If <= 0,
+	// then the level is 1.
+	HeadingLevel int
+
+	// ResolveLink receives the target of a link and returns the target to be
+	// rendered. If an empty string is returned, then only the content of the
+	// link is rendered.
+	ResolveLink func(link string) string
+}
+
+func NewRenderer() Renderer {
+	return Renderer{}
+}
+
+func (r Renderer) Render(w io.Writer, s *goquery.Selection) error {
+	level := r.HeadingLevel
+	if level <= 0 {
+		level = 1
+	}
+	var nodes []*html.Node
+	for _, node := range s.Nodes {
+		if isElement(node, "body") {
+			// Top-level sections of a document are rendered with headings.
+			for c := node.FirstChild; c != nil; c = c.NextSibling {
+				nodes = append(nodes, c)
+			}
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+	blocks := r.blocks(nodes, level)
+	if len(blocks) == 0 {
+		return nil
+	}
+	_, err := io.WriteString(w, strings.Join(blocks, "\n\n")+"\n")
+	return err
+}
+
+func isElement(node *html.Node, tag string) bool {
+	return node.Type == html.ElementNode && node.Data == tag
+}
+
+// block contains elements that are rendered as blocks.
+var block = map[string]bool{
+	"body":       true,
+	"blockquote": true,
+	"div":        true,
+	"h1":         true,
+	"h2":         true,
+	"h3":         true,
+	"h4":         true,
+	"h5":         true,
+	"h6":         true,
+	"ol":         true,
+	"p":          true,
+	"pre":        true,
+	"section":    true,
+	"table":      true,
+	"ul":         true,
+}
+
+// isBlock returns whether node is rendered as a block.
+func isBlock(node *html.Node) bool {
+	return node.Type == html.ElementNode && block[node.Data]
+}
+
+// blocks renders each node as a list of blocks. Runs of inline nodes are
+// rendered as paragraphs. level is the level of headings produced by sections.
+func (r Renderer) blocks(nodes []*html.Node, level int) []string {
+	var blocks []string
+	var inline []*html.Node
+	flush := func() {
+		if text := strings.TrimSpace(r.inline(inline)); text != "" {
+			blocks = append(blocks, text)
+		}
+		inline = inline[:0]
+	}
+	for _, node := range nodes {
+		if !isBlock(node) {
+			if node.Type == html.TextNode || node.Type == html.ElementNode {
+				inline = append(inline, node)
+			}
+			continue
+		}
+		flush()
+		if b := r.block(node, level); b != "" {
+			blocks = append(blocks, b)
+		}
+	}
+	flush()
+	return blocks
+}
+
+// children returns the child nodes of node.
+func children(node *html.Node) []*html.Node {
+	var nodes []*html.Node
+	for c := node.FirstChild; c != nil; c = c.NextSibling {
+		nodes = append(nodes, c)
+	}
+	return nodes
+}
+
+// block renders a single block node.
+func (r Renderer) block(node *html.Node, level int) string {
+	switch node.Data {
+	case "p":
+		return strings.TrimSpace(r.inline(children(node)))
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		n := int(node.Data[1]-'0') + level - 1
+		return heading(n, strings.TrimSpace(r.inline(children(node))))
+	case "section":
+		blocks := r.blocks(children(node), level+1)
+		for _, attr := range node.Attr {
+			if attr.Key == "data-name" {
+				blocks = append([]string{heading(level, Escape(attr.Val))}, blocks...)
+				break
+			}
+		}
+		return strings.Join(blocks, "\n\n")
+	case "pre":
+		return r.pre(node)
+	case "ul", "ol":
+		return r.list(node, level)
+	case "table":
+		return r.table(node)
+	case "blockquote":
+		blocks := r.blocks(children(node), level)
+		return indent(strings.Join(blocks, "\n\n"), "> ", "> ")
+	}
+	return strings.Join(r.blocks(children(node), level), "\n\n")
+}
+
+// heading returns a heading of the given level.
+func heading(level int, text string) string {
+	if level > 6 {
+		level = 6
+	}
+	return strings.Repeat("#", level) + " " + text
+}
+
+// indent prefixes the first line of s with first, and each subsequent
+// non-empty line with rest.
+func indent(s, first, rest string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		switch {
+		case i == 0:
+			lines[i] = first + line
+		case line != "":
+			lines[i] = rest + line
+		default:
+			lines[i] = strings.TrimRight(rest, " ")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// pre renders preformatted text as a fenced code block.
+func (r Renderer) pre(node *html.Node) string {
+	var lang string
+	content := node
+	if c := node.FirstChild; c != nil && c.NextSibling == nil && isElement(c, "code") {
+		content = c
+		for _, attr := range c.Attr {
+			if attr.Key == "class" {
+				for _, class := range strings.Fields(attr.Val) {
+					if strings.HasPrefix(class, "language-") {
+						lang = strings.TrimPrefix(class, "language-")
+					}
+				}
+			}
+		}
+	}
+	text := strings.Trim(textContent(content), "\n")
+	fence := "```"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	return fence + lang + "\n" + text + "\n" + fence
+}
+
+// list renders an ordered or unordered list.
+func (r Renderer) list(node *html.Node, level int) string {
+	var items []string
+	n := 1
+	for c := node.FirstChild; c != nil; c = c.NextSibling {
+		if !isElement(c, "li") {
+			continue
+		}
+		marker := "- "
+		if node.Data == "ol" {
+			marker = itoa(n) + ". "
+			n++
+		}
+		content := strings.Join(r.blocks(children(c), level), "\n\n")
+		items = append(items, indent(content, marker, strings.Repeat(" ", len(marker))))
+	}
+	return strings.Join(items, "\n")
+}
+
+// table renders a table. The first row is used as the header.
+func (r Renderer) table(node *html.Node) string {
+	var rows [][]string
+	var walk func(node *html.Node)
+	walk = func(node *html.Node) {
+		for c := node.FirstChild; c != nil; c = c.NextSibling {
+			switch {
+			case isElement(c, "tr"):
+				var row []string
+				for cell := c.FirstChild; cell != nil; cell = cell.NextSibling {
+					if isElement(cell, "td") || isElement(cell, "th") {
+						text := strings.TrimSpace(r.inline(children(cell)))
+						row = append(row, strings.ReplaceAll(text, "|", `\|`))
+					}
+				}
+				rows = append(rows, row)
+			case c.Type == html.ElementNode:
+				walk(c)
+			}
+		}
+	}
+	walk(node)
+	if len(rows) == 0 {
+		return ""
+	}
+	var widths []int
+	for _, row := range rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 3)
+			}
+			if n := utf8.RuneCountInString(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+	var s strings.Builder
+	writeRow := func(row []string) {
+		s.WriteString("|")
+		for i, width := range widths {
+			var cell string
+			if i < len(row) {
+				cell = row[i]
+			}
+			s.WriteString(" ")
+			s.WriteString(cell)
+			s.WriteString(strings.Repeat(" ", width-utf8.RuneCountInString(cell)))
+			s.WriteString(" |")
+		}
+		s.WriteString("\n")
+	}
+	writeRow(rows[0])
+	s.WriteString("|")
+	for _, width := range widths {
+		s.WriteString(strings.Repeat("-", width+2))
+		s.WriteString("|")
+	}
+	s.WriteString("\n")
+	for _, row := range rows[1:] {
+		writeRow(row)
+	}
+	return strings.TrimSuffix(s.String(), "\n")
+}
+
+// inline renders a sequence of inline nodes, collapsing whitespace.
+func (r Renderer) inline(nodes []*html.Node) string {
+	var s strings.Builder
+	for _, node := range nodes {
+		switch node.Type {
+		case html.TextNode:
+			s.WriteString(Escape(collapse(node.Data)))
+		case html.ElementNode:
+			content := r.inline(children(node))
+			switch node.Data {
+			case "b", "strong":
+				s.WriteString(wrap(content, "**"))
+			case "i", "em":
+				s.WriteString(wrap(content, "*"))
+			case "code":
+				s.WriteString(code(collapse(textContent(node))))
+			case "br":
+				s.WriteString("<br>")
+			case "a":
+				var href string
+				for _, attr := range node.Attr {
+					if attr.Key == "href" {
+						href = attr.Val
+					}
+				}
+				if r.ResolveLink != nil {
+					href = r.ResolveLink(href)
+				}
+				if href == "" {
+					s.WriteString(content)
+				} else {
+					s.WriteString("[" + content + "](" + href + ")")
+				}
+			default:
+				if isBlock(node) {
+					s.WriteString(" " + content + " ")
+				} else {
+					s.WriteString(content)
+				}
+			}
+		}
+	}
+	return s.String()
+}
+
+// wrap encloses the non-space content of s with delim.
+func wrap(s, delim string) string {
+	t := strings.TrimSpace(s)
+	if t == "" {
+		return s
+	}
+	i := strings.Index(s, t)
+	return s[:i] + delim + t + delim + s[i+len(t):]
+}
+
+// code returns s as a code span.
+func code(s string) string {
+	if s == "" {
+		return ""
+	}
+	fence := "`"
+	for strings.Contains(s, fence) {
+		fence += "`"
+	}
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		return fence + " " + s + " " + fence
+	}
+	return fence + s + fence
+}
+
+// textContent returns the concatenated text within node.
+func textContent(node *html.Node) string {
+	if node.Type == html.TextNode {
+		return node.Data
+	}
+	var s strings.Builder
+	for c := node.FirstChild; c != nil; c = c.NextSibling {
+		s.WriteString(textContent(c))
+	}
+	return s.String()
+}
+
+// collapse replaces each sequence of whitespace in s with a single space.
+func collapse(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range s {
+		switch r {
+		case ' ', '\t', '\n', '\r', '\f':
+			if !space {
+				b.WriteByte(' ')
+			}
+			space = true
+			continue
+		}
+		space = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Escape escapes characters in s that have meaning in Markdown.
+func Escape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '\\', '*', '_', '`', '[', ']', '<':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func itoa(i int) string {
+	if i < 10 {
+		return string(rune('0' + i))
+	}
+	return itoa(i/10) + string(rune('0'+i%10))
+}
@@ -40,9 +40,15 @@ func injectSSLKeyLogFile(world *rbxmk.World, werr io.Writer) {
 	// crypto/tls.Config.KeyLogWriter for more information:
 	//
 	// https://pkg.go.dev/crypto/tls@latest#Config
-	world.Client.Client.Transport = &http.Transport{
+	transport := &http.Transport{
 		TLSClientConfig: &tls.Config{
 			KeyLogWriter: w,
 		},
 	}
+	// Preserve caching, if enabled.
+	if cache, ok := world.Client.Client.Transport.(*rbxmk.HttpCache); ok {
+		cache.Transport = transport
+		return
+	}
+	world.Client.Client.Transport = transport
 }
@@ -0,0 +1,91 @@
+local model = rbxmk.decodeFormat("model.json", [==[{
+	"Name": "Prefab",
+	"ClassName": "Model",
+	"Attributes": {
+		"Health": 100,
+		"Team": "Red",
+		"Spawn": {"Vector3": [1, 2, 3]}
+	},
+	"Children": [
+		{
+			"Name": "Block",
+			"ClassName": "Part",
+			"Properties": {
+				"Anchored": true,
+				"Size": {"Vector3": [4, 1, 2]},
+				"CFrame": {"CFrame": {"position": [0, 5, 0], "orientation": [[1, 0, 0], [0, 1, 0], [0, 0, 1]]}},
+				"Color": {"Color3": [1, 0.5, 0]},
+				"Transparency": {"Float32": 0.25},
+				"Tags": {"Tags": ["Enemy", "Spawnable"]}
+			}
+		},
+		{"Name": "Empty", "className": "Folder"}
+	]
+}]==])
+T.Pass(model.ClassName == "Model", "decodes ClassName")
+T.Pass(model.Name == "Prefab", "decodes Name")
+T.Pass(model:GetAttribute("Health") == 100, "decodes implicit number attribute")
+T.Pass(model:GetAttribute("Team") == "Red", "decodes implicit string attribute")
+T.Pass(model:GetAttribute("Spawn") == Vector3.new(1, 2, 3), "decodes explicit attribute")
+local block = model:Descend("Block")
+T.Pass(block.ClassName == "Part", "decodes child")
+T.Pass(block.Anchored == true, "decodes implicit bool property")
+T.Pass(block.Size == Vector3.new(4, 1, 2), "decodes Vector3 property")
+T.Pass(block.CFrame == CFrame.new(0, 5, 0), "decodes CFrame property")
+T.Pass(block.Color == Color3.new(1, 0.5, 0), "decodes Color3 property")
+T.Pass(block.Tags == "Enemy\0Spawnable", "decodes Tags property")
+T.Pass(model:Descend("Empty").ClassName == "Folder", "accepts lowercase field names")
+
+T.Fail(function() return rbxmk.decodeFormat("model.json", [[{"Name": "Foo"}]]) end, "requires ClassName")
+T.Fail(function() return rbxmk.decodeFormat("model.json", [[{"ClassName": "Part", "Properties": {"Size": [1, 2, 3]}}]]) end, "implicit Vector3 requires descriptor")
+
+local encoded = rbxmk.encodeFormat("model.json", model)
+local decoded = rbxmk.decodeFormat("model.json", encoded)
+T.Pass(decoded.Name == "Prefab", "round trip Name")
+T.Pass(decoded:GetAttribute("Spawn") == Vector3.new(1, 2, 3), "round trip attribute")
+T.Pass(decoded:Descend("Block").CFrame == CFrame.new(0, 5, 0), "round trip CFrame")
+T.Pass(decoded:Descend("Block").Transparency == 0.25, "round trip float")
+T.Pass(decoded:Descend("Block").Tags == "Enemy\0Spawnable", "round trip Tags")
+T.Pass(rbxmk.encodeFormat("model.json", decoded) == encoded, "encoding is stable")
+T.Pass(string.find(encoded, '"Tags": [', 1, true) ~= nil, "Tags encode as list")
+
+-- meta.json
+local meta = rbxmk.decodeFormat("meta.json", [[{"className": "Model", "properties": {"Archivable": true}, "ignoreUnknownInstances": true}]])
+T.Pass(meta.ClassName == "Model", "meta decodes className")
+T.Pass(meta.Archivable == true, "meta decodes properties")
+T.Fail(function() return rbxmk.decodeFormat("meta.json", [[{"Children": []}]]) end, "meta cannot have children")
+T.Pass(string.find(rbxmk.encodeFormat("meta.json", model), "Children", 1, true) == nil, "meta does not encode children")
+T.Fail(function() return rbxmk.decodeFormat("meta.json", [[{"properties": {"Disabled": true}}]]) end, "meta properties require class")
+T.Pass(rbxmk.decodeFormat("meta.json", [[{"attributes": {"Count": 3}}]]):GetAttribute("Count") == 3, "meta attributes do not require class")
+local meta = rbxmk.decodeFormat({Format="meta.json", ClassName="Script"}, [[{"properties": {"Disabled": true}}]])
+T.Pass(meta.ClassName == "Script", "meta class from ClassName option")
+T.Pass(meta.Disabled == true, "meta decodes properties with ClassName option")
+
+-- Directory trees
+local dir = path.join(path.expand("$tmp"), "rbxmk-model-json")
+fs.remove(dir, true)
+local root = Instance.new("Model")
+local script = Instance.new("Script", root)
+script.Name = "Main"
+script.Source = "print('hello')"
+script.Disabled = true
+local folder = Instance.new("Folder", root)
+folder.Name = "Stuff"
+folder:SetAttribute("Count", 3)
+fs.writedir(dir, root)
+T.Pass(fs.stat(path.join(dir, "init.meta.json")) ~= nil, "writes meta for non-Folder root")
+T.Pass(fs.stat(path.join(dir, "Main.meta.json")) ~= nil, "writes meta for script with properties")
+T.Pass(fs.stat(path.join(dir, "Stuff", "init.meta.json")) ~= nil, "writes meta for Folder with attributes")
+
+local tree = fs.readdir(dir)
+T.Pass(tree.ClassName == "Model", "reads class from meta")
+T.Pass(tree:Descend("Main").Disabled == true, "reads script properties from meta")
+T.Pass(tree:Descend("Main").Source == "print('hello')", "meta does not replace Source")
+T.Pass(tree:Descend("Stuff"):GetAttribute("Count") == 3, "reads attributes from meta")
+T.Pass(#tree:GetChildren() == 2, "meta files are not instances")
+
+fs.write(path.join(dir, "Main.meta.json"), [[{"properties": {"Disabled": false}}]], "bin")
+local tree = fs.readdir(dir)
+T.Pass(tree:Descend("Main").ClassName == "Script", "meta without class keeps class of instance")
+T.Pass(tree:Descend("Main").Disabled == false, "meta without class decodes properties against instance")
+fs.remove(dir, true)
@@ -0,0 +1,95 @@
+local dir = path.join(path.expand("$tmp"), "rbxmk-project")
+local desc = fs.read(path.expand("$sd/../dump.desc.json"))
+local enums = desc:EnumTypes()
+rbxmk.globalDesc = desc
+local model = rbxmk.decodeFormat("project.json", [[{"tree": {
+	"$className": "Model",
+	"$properties": {"LevelOfDetail": "Disabled"},
+	"Part": {
+		"$className": "Part",
+		"$properties": {
+			"Size": [4, 1, 2],
+			"Anchored": true
+		}
+	}
+}}]])
+T.Pass(model:Descend("Part").Size == Vector3.new(4, 1, 2), "implicit Vector3 property with desc")
+T.Pass(model:Descend("Part").Anchored == true, "implicit bool property with desc")
+T.Pass(model.LevelOfDetail == enums.ModelLevelOfDetail.Disabled, "implicit enum name property with desc")
+rbxmk.globalDesc = nil
+
+fs.remove(dir, true)
+fs.mkdir(dir)
+
+
+local src = Instance.new("Folder")
+local main = Instance.new("Script", src)
+main.Name = "main"
+main.Source = "print('main')"
+local util = Instance.new("ModuleScript", src)
+util.Name = "util"
+util.Source = "return {}"
+fs.writedir(path.join(dir, "src"), src)
+
+local part = Instance.new("Part")
+part.Name = "Block"
+fs.write(path.join(dir, "block.rbxmx"), part)
+
+local project = [==[{
+	"name": "Game",
+	"tree": {
+		"$className": "DataModel",
+		"ServerScriptService": {
+			"$className": "ServerScriptService",
+			"$ignoreUnknownInstances": true,
+			"Code": {"$path": "src"}
+		},
+		"Workspace": {
+			"$className": "Workspace",
+			"$properties": {
+				"Gravity": {"Float32": 100},
+				"FilteringEnabled": true
+			},
+			"Prefab": {"$path": "block.rbxmx"},
+			"Assets": {
+				"$className": "Model",
+				"$properties": {
+					"WorldPivotData": {"OptionalCFrame": {"position": [1, 2, 3], "orientation": [[1, 0, 0], [0, 1, 0], [0, 0, 1]]}}
+				}
+			},
+			"Marker": {
+				"$className": "Part",
+				"$properties": {
+					"Size": {"Vector3": [4, 1, 2]},
+					"Color": {"Color3uint8": [255, 0, 0]},
+					"Tags": {"Tags": ["A", "B"]}
+				}
+			}
+		}
+	}
+}]==]
+fs.write(path.join(dir, "default.project.json"), project, "txt")
+
+local game = fs.read(path.join(dir, "default.project.json"))
+T.Pass(game.ClassName == "DataModel", "tree is DataModel")
+T.Pass(game.Name == "Game", "fs.read keeps project name")
+T.Pass(game:Descend("ServerScriptService").ClassName == "ServerScriptService", "node has $className")
+T.Pass(game:Descend("ServerScriptService", "Code").ClassName == "Folder", "directory $path is Folder")
+T.Pass(game:Descend("ServerScriptService", "Code", "main").ClassName == "Script", "directory $path contains Script")
+T.Pass(game:Descend("ServerScriptService", "Code", "util").Source == "return {}", "directory $path contains ModuleScript")
+T.Pass(game:Descend("Workspace", "Prefab").ClassName == "Part", "model $path is its instance")
+T.Pass(game:Descend("Workspace").Gravity == 100, "explicit float property")
+T.Pass(game:Descend("Workspace").FilteringEnabled == true, "implicit bool property")
+T.Pass(game:Descend("Workspace", "Marker").Size == Vector3.new(4, 1, 2), "explicit Vector3 property")
+T.Pass(game:Descend("Workspace", "Marker").Tags == "A\0B", "explicit Tags property")
+T.Pass(game:Descend("Workspace", "Assets").WorldPivotData.Value.Position == Vector3.new(1, 2, 3), "explicit OptionalCFrame property")
+
+fs.write(path.join(dir, "unnamed.project.json"), [[{"tree": {"$className": "Folder"}}]], "txt")
+T.Pass(fs.read(path.join(dir, "unnamed.project.json")).Name == "unnamed", "fs.read names unnamed project after file")
+
+T.Fail(function() return rbxmk.decodeFormat("project.json", [[{"tree": {"$path": "src"}}]]) end, "$path requires file system")
+T.Fail(function() return rbxmk.decodeFormat("project.json", [[{"tree": {"Foo": {}}}]]) end, "node requires $className")
+T.Fail(function() return rbxmk.decodeFormat("project.json", [[{"tree": {"$className": "Folder", "$ignoreUnknownInstances": 1}}]]) end, "$ignoreUnknownInstances must be bool")
+T.Pass(rbxmk.decodeFormat("project.json", [[{"name": "Thing", "tree": {"$className": "Folder"}}]]).Name == "Thing", "root is named after project")
+
+fs.remove(dir, true)
@@ -0,0 +1,43 @@
+local game = Instance.new("DataModel")
+local workspace = Instance.new("Workspace", game)
+workspace.Name = "Workspace"
+local map = Instance.new("Model", workspace)
+map.Name = "Map"
+local part = Instance.new("Part", map)
+part.Name = "Part"
+local other = Instance.new("Part", workspace)
+other.Name = "Other"
+local sss = Instance.new("ServerScriptService", game)
+sss.Name = "ServerScriptService"
+local script = Instance.new("Script", sss)
+script.Name = "Main"
+script.Source = "print('hello')"
+local module = Instance.new("ModuleScript", script)
+module.Name = "Module"
+local value = Instance.new("ObjectValue", sss)
+value.Name = "Value"
+value.Value = other
+
+for _, format in ipairs({"rbxl", "rbxlx", "rbxm.txt"}) do
+	local data = rbxmk.encodeFormat(format, game)
+
+	local t = rbxmk.decodeFormat({Format=format, Include={"Workspace.Map", "ServerScriptService.Value"}}, data)
+	T.Pass(t:Descend("Workspace", "Map", "Part") ~= nil, format .. ": include keeps descendants of path")
+	T.Pass(t:Descend("Workspace", "Other") == nil, format .. ": include prunes siblings of path")
+	T.Pass(t:Descend("ServerScriptService", "Main") == nil, format .. ": include prunes other paths")
+	T.Pass(t:Descend("ServerScriptService", "Value").Value == nil, format .. ": reference to pruned instance is removed")
+
+	local t = rbxmk.decodeFormat({Format=format, Classes={"Script", "ModuleScript"}}, data)
+	T.Pass(t:Descend("ServerScriptService", "Main").Source == "print('hello')", format .. ": classes keeps matching instances")
+	T.Pass(t:Descend("ServerScriptService", "Main", "Module") ~= nil, format .. ": classes keeps matching descendants")
+	T.Pass(t:Descend("ServerScriptService", "Value") == nil, format .. ": classes prunes other classes")
+	T.Pass(t:Descend("Workspace") == nil, format .. ": classes prunes subtrees without matches")
+
+	local t = rbxmk.decodeFormat({Format=format, Include={"Workspace"}, Classes={"Part"}}, data)
+	T.Pass(t:Descend("Workspace", "Map", "Part") ~= nil and t:Descend("Workspace", "Other") ~= nil, format .. ": include and classes keep matches within path")
+	T.Pass(t:Descend("ServerScriptService") == nil, format .. ": include and classes prune outside path")
+end
+
+T.Fail(function()
+	rbxmk.decodeFormat({Format="rbxl", Include={1}}, rbxmk.encodeFormat("rbxl", game))
+end, "include must contain strings")
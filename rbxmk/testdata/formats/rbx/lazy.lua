@@ -0,0 +1,25 @@
+local game = Instance.new("DataModel")
+local workspace = Instance.new("Workspace", game)
+workspace.Name = "Workspace"
+local part = Instance.new("Part", workspace)
+part.Name = "Part"
+part.Size = Vector3.new(1, 2, 3)
+part.Anchored = true
+local value = Instance.new("ObjectValue", workspace)
+value.Name = "Value"
+value.Value = part
+
+for _, format in ipairs({"rbxl", "rbxlx", "rbxm.txt"}) do
+	local data = rbxmk.encodeFormat(format, game)
+	local eager = rbxmk.decodeFormat(format, data)
+	local lazy = rbxmk.decodeFormat({Format=format, Lazy=true}, data)
+	T.Pass(lazy:Descend("Workspace", "Part") ~= nil, format .. ": lazy tree can be traversed")
+	T.Pass(lazy:Descend("Workspace", "Part").Size == Vector3.new(1, 2, 3), format .. ": lazy property is converted on access")
+	T.Pass(lazy:Descend("Workspace", "Value").Value == lazy:Descend("Workspace", "Part"), format .. ": lazy reference is resolved")
+	T.Pass(#eager:Diff(lazy) == 0, format .. ": lazy decode matches eager decode")
+	T.Pass(#lazy:Diff(rbxmk.decodeFormat(format, rbxmk.encodeFormat(format, lazy))) == 0, format .. ": lazy tree round-trips")
+end
+
+T.Fail(function()
+	rbxmk.decodeFormat({Format="rbxl", Lazy=true, DescMode="Strict"}, rbxmk.encodeFormat("rbxl", game))
+end, "lazy cannot be combined with Strict")
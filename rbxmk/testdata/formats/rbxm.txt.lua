@@ -0,0 +1,83 @@
+rbxmk.globalDesc = nil
+
+local model = Instance.new("Model")
+model.Name = "Prefab"
+model.WorldPivotData = Optional.some(CFrame.new(1, 2, 3))
+local part = Instance.new("Part", model)
+part.Name = "Block"
+part.Anchored = true
+part.Size = Vector3.new(4, 1.5, 2)
+part.CFrame = CFrame.new(0, 5, 0)
+part.Color = Color3.new(1, 0.5, 0)
+part.CustomPhysicalProperties = PhysicalProperties.new(1, 0.3, 0.5, 1, 1)
+part.ResizeableFaces = Faces.new("Top", "Bottom")
+part.Axes = Axes.new("X", "Z")
+part.Font = Font.new("rbxasset://fonts/families/SourceSansPro.json", 700, 1)
+part.UniqueId = UniqueId.new(-42, 100, 7)
+part.Curve = NumberSequence.new(0, 1)
+part.Gradient = ColorSequence.new(Color3.new(1, 0, 0), Color3.new(0, 0, 1))
+part.Range = NumberRange.new(2, 8)
+part.Bounds = Rect.new(0, 0, 10, 20)
+part.Position2D = UDim2.new(0.5, 10, 1, -5)
+part["Odd Name"] = "quoted"
+model.PrimaryPart = part
+local script = Instance.new("Script", model)
+script.Name = "Main"
+script.Source = "print('hello')\n\tprint(\"world\")\n"
+
+local encoded = rbxmk.encodeFormat("rbxm.txt", model)
+local decoded = rbxmk.decodeFormat("rbxm.txt", encoded):GetChildren()[1]
+T.Pass(decoded.ClassName == "Model", "round trip class")
+T.Pass(decoded.Name == "Prefab", "round trip name")
+T.Pass(decoded.WorldPivotData.Value == CFrame.new(1, 2, 3), "round trip Optional CFrame")
+local block = decoded:Descend("Block")
+T.Pass(decoded.PrimaryPart == block, "round trip reference")
+T.Pass(block.Anchored == true, "round trip Bool")
+T.Pass(block.Size == Vector3.new(4, 1.5, 2), "round trip Vector3")
+T.Pass(block.CFrame == CFrame.new(0, 5, 0), "round trip CFrame")
+T.Pass(block.Color == Color3.new(1, 0.5, 0), "round trip Color3")
+T.Pass(block.CustomPhysicalProperties == PhysicalProperties.new(1, 0.3, 0.5, 1, 1), "round trip PhysicalProperties")
+T.Pass(block.ResizeableFaces == Faces.new("Top", "Bottom"), "round trip Faces")
+T.Pass(block.Axes == Axes.new("X", "Z"), "round trip Axes")
+T.Pass(block.Font == Font.new("rbxasset://fonts/families/SourceSansPro.json", 700, 1), "round trip Font")
+T.Pass(block.UniqueId == UniqueId.new(-42, 100, 7), "round trip UniqueId")
+T.Pass(block.Curve == NumberSequence.new(0, 1), "round trip NumberSequence")
+T.Pass(block.Gradient == ColorSequence.new(Color3.new(1, 0, 0), Color3.new(0, 0, 1)), "round trip ColorSequence")
+T.Pass(block.Range == NumberRange.new(2, 8), "round trip NumberRange")
+T.Pass(block.Bounds == Rect.new(0, 0, 10, 20), "round trip Rect")
+T.Pass(block.Position2D == UDim2.new(0.5, 10, 1, -5), "round trip UDim2")
+T.Pass(block["Odd Name"] == "quoted", "round trip quoted property name")
+T.Pass(decoded:Descend("Main").Source == "print('hello')\n\tprint(\"world\")\n", "round trip multi-line string")
+T.Pass(rbxmk.encodeFormat("rbxm.txt", decoded) == encoded, "encoding is stable")
+T.Pass(string.find(encoded, "\t\t|print('hello')\n", 1, true) ~= nil, "multi-line strings are written per line")
+T.Pass(string.find(encoded, "Anchored: Bool true\n\t\tAxes:", 1, true) ~= nil, "properties are sorted")
+
+-- Files decoded from other formats round trip.
+for _, file in ipairs({"rbx/types/model.rbxm", "rbx/types/model.rbxmx"}) do
+	local place = fs.read(path.expand("$sd/" .. file))
+	local text = rbxmk.encodeFormat("rbxm.txt", place)
+	T.Pass(rbxmk.encodeFormat("rbxm.txt", rbxmk.decodeFormat("rbxm.txt", text)) == text, "round trip " .. file)
+end
+
+local place = rbxmk.decodeFormat("rbxm.txt", [[
+meta "ExplicitAutoJoints" "true"
++Workspace service
+	Name: String "Workspace"
+	+Part #1
+		Name: String "Part"
+		Mesh: SharedString "\x00\x01"
+		Transparency: Float 0.25
+	+ObjectValue
+		Name: String "ObjectValue"
+		Value: Reference #1
+]])
+local workspace = place:Descend("Workspace")
+T.Pass(workspace ~= nil and workspace.ClassName == "Workspace", "decodes service")
+T.Pass(workspace:Descend("ObjectValue").Value == workspace:Descend("Part"), "decodes reference")
+T.Pass(workspace:Descend("Part").Mesh == "\0\1", "decodes SharedString")
+T.Pass(workspace:Descend("Part").Transparency == 0.25, "decodes Float")
+
+T.Fail(function() return rbxmk.decodeFormat("rbxm.txt", "+Part\n\t\tName: String \"x\"\n") end, "rejects bad indentation")
+T.Fail(function() return rbxmk.decodeFormat("rbxm.txt", "+Part\n\tName: Nope 1\n") end, "rejects unknown type")
+T.Fail(function() return rbxmk.decodeFormat("rbxm.txt", "+Part\n\tSize: Vector3 1 2\n") end, "rejects wrong component count")
+T.Fail(function() return rbxmk.decodeFormat("rbxm.txt", "+ObjectValue\n\tValue: Reference #9\n") end, "rejects unknown label")
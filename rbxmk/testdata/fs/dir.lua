@@ -0,0 +1,92 @@
+local dir = path.join(path.expand("$tmp"), "rbxmk-fs-dir")
+fs.remove(dir, true)
+
+local root = Instance.new("Folder")
+root.Name = "Root"
+
+local server = Instance.new("Script", root)
+server.Name = "Main"
+server.Source = "print('server')"
+
+local client = Instance.new("LocalScript", root)
+client.Name = "Client"
+client.Source = "print('client')"
+
+local shared = Instance.new("Folder", root)
+shared.Name = "Shared"
+
+local module = Instance.new("ModuleScript", shared)
+module.Name = "Util"
+module.Source = "return {}"
+
+local sub = Instance.new("ModuleScript", module)
+sub.Name = "Sub"
+sub.Source = "return 1"
+
+local part = Instance.new("Part", shared)
+part.Name = "Block"
+part.Anchored = true
+
+T.Pass(function() fs.writedir(dir, root) end, "write tree")
+T.Pass(fs.stat(path.join(dir, "Main.server.lua")) ~= nil, "Script is written as server.lua")
+T.Pass(fs.stat(path.join(dir, "Client.client.lua")) ~= nil, "LocalScript is written as client.lua")
+T.Pass(fs.stat(path.join(dir, "Shared", "Util", "init.lua")) ~= nil, "script with children is written as init file")
+T.Pass(fs.stat(path.join(dir, "Shared", "Util", "Sub.lua")) ~= nil, "ModuleScript is written as lua")
+T.Pass(fs.stat(path.join(dir, "Shared", "Block.rbxmx")) ~= nil, "other instance is written as rbxmx")
+
+local tree = fs.readdir(dir)
+T.Pass(tree ~= nil, "read tree")
+T.Pass(tree.ClassName == "Folder", "root is Folder")
+T.Pass(tree.Name == "rbxmk-fs-dir", "root is named after directory")
+T.Pass(tree:Descend("Main").ClassName == "Script", "server.lua is Script")
+T.Pass(tree:Descend("Main").Source == "print('server')", "Script has source")
+T.Pass(tree:Descend("Client").ClassName == "LocalScript", "client.lua is LocalScript")
+T.Pass(tree:Descend("Shared").ClassName == "Folder", "directory is Folder")
+T.Pass(tree:Descend("Shared", "Util").ClassName == "ModuleScript", "directory with init file is script")
+T.Pass(tree:Descend("Shared", "Util").Source == "return {}", "init file has source")
+T.Pass(tree:Descend("Shared", "Util", "Sub").Source == "return 1", "script child is decoded")
+T.Pass(tree:Descend("Shared", "Block").ClassName == "Part", "rbxmx contents are added")
+T.Pass(tree:Descend("Shared", "Block").Anchored == true, "rbxmx contents have properties")
+
+T.Pass(fs.readdir(path.join(dir, "nonextant")) == nil, "nonextant directory")
+
+local dup = Instance.new("Folder")
+Instance.new("Folder", dup).Name = "A"
+Instance.new("Folder", dup).Name = "A"
+T.Fail(function() fs.writedir(path.join(dir, "dup"), dup) end, "duplicate names")
+
+-- Round trip
+fs.write(path.join(dir, "README.md"), "readme", "bin")
+tree:Descend("Client").Parent = nil
+tree:Descend("Shared", "Block").Parent = nil
+tree:Descend("Shared", "Util", "Sub").Parent = nil
+T.Pass(function() fs.writedir(dir, tree) end, "rewrite tree")
+T.Pass(fs.stat(path.join(dir, "Client.client.lua")) == nil, "removed script file is deleted")
+T.Pass(fs.stat(path.join(dir, "Shared", "Block.rbxmx")) == nil, "removed instance file is deleted")
+T.Pass(fs.stat(path.join(dir, "Shared", "Util")) == nil, "script without children is no longer a directory")
+T.Pass(fs.stat(path.join(dir, "Shared", "Util.lua")) ~= nil, "script without children is written as file")
+T.Pass(fs.stat(path.join(dir, "README.md")) ~= nil, "unrelated file is kept")
+T.Pass(fs.stat(path.join(dir, "dup")) == nil, "unrelated directory is removed")
+
+local tree = fs.readdir(dir)
+T.Pass(tree:Descend("Client") == nil, "removed script is not read")
+T.Pass(tree:Descend("Shared", "Block") == nil, "removed instance is not read")
+T.Pass(tree:Descend("Shared", "Util").Source == "return {}", "rewritten script is read")
+T.Pass(#tree:Descend("Shared", "Util"):GetChildren() == 0, "removed child is not read")
+
+-- Luau extensions
+local luau = path.join(dir, "luau")
+fs.mkdir(path.join(luau, "Module"), true)
+fs.write(path.join(luau, "Main.server.luau"), "print('server')", "bin")
+fs.write(path.join(luau, "Module", "init.luau"), "return {}", "bin")
+fs.write(path.join(luau, "Module", "Sub.luau"), "return 1", "bin")
+local tree = fs.readdir(luau)
+T.Pass(function() fs.writedir(luau, tree) end, "rewrite luau tree")
+T.Pass(fs.stat(path.join(luau, "Main.server.luau")) ~= nil, "server.luau is kept")
+T.Pass(fs.stat(path.join(luau, "Main.server.lua")) == nil, "server.luau is not renamed")
+T.Pass(fs.stat(path.join(luau, "Module", "init.luau")) ~= nil, "init.luau is kept")
+T.Pass(fs.stat(path.join(luau, "Module", "init.lua")) == nil, "init.luau is not renamed")
+T.Pass(fs.stat(path.join(luau, "Module", "Sub.luau")) ~= nil, "luau is kept")
+T.Pass(fs.stat(path.join(luau, "Module", "Sub.lua")) == nil, "luau is not renamed")
+
+fs.remove(dir, true)
@@ -0,0 +1,17 @@
+-- Constructor tests
+T.Fail(function() TagConfig.new(42) end                 , "expects a string for its first argument")
+T.Pass(TagConfig.new()                                  , "can pass no value")
+T.Pass(TagConfig.new("Foobar")                          , "can pass string")
+T.Pass(typeof(TagConfig.new("Foobar")) == "TagConfig"  , "returns TagConfig")
+T.Pass(TagConfig.new().Property == ""                   , "passing no value sets Property to empty string")
+T.Pass(TagConfig.new("Foobar").Property == "Foobar"     , "passing string sets Property to string")
+
+local cfg = TagConfig.new("Foobar")
+
+-- Metamethod tests
+T.Pass(typeof(cfg) == "TagConfig"                  , "type of value")
+T.Pass(type(getmetatable(cfg)) == "string"          , "metatable of value is locked")
+T.Pass(not string.match(tostring(cfg), "^userdata") , "value converts to a string")
+T.Pass(cfg == cfg                                   , "value is equal to itself")
+T.Pass(cfg ~= TagConfig.new("Foobar")              , "value is not equal to another value of the same type")
+T.Pass(cfg ~= TagConfig.new("Fizzbuzz")            , "value is not equal to another value of the same type, different property")
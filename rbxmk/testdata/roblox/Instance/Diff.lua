@@ -0,0 +1,110 @@
+local function tree()
+	local model = Instance.new("Model")
+	model.Name = "Model"
+	local a = Instance.new("Part", model)
+	a.Name = "A"
+	a.Size = Vector3.new(1, 1, 1)
+	local b = Instance.new("Part", model)
+	b.Name = "B"
+	local folder = Instance.new("Folder", model)
+	folder.Name = "Folder"
+	local value = Instance.new("ObjectValue", folder)
+	value.Name = "Value"
+	value.Value = a
+	return model
+end
+
+local function count(actions, type)
+	local n = 0
+	for _, action in ipairs(actions) do
+		if action.Type == rbxmk.Enum.InstanceActionType[type] then
+			n = n + 1
+		end
+	end
+	return n
+end
+
+-- Diff by path.
+local prev = tree()
+local next = tree()
+T.Pass(#prev:Diff(next) == 0, "identical trees have no differences")
+
+next:Descend("A").Size = Vector3.new(2, 2, 2)
+next:Descend("B"):Destroy()
+local c = Instance.new("Part", next:Descend("Folder"))
+c.Name = "C"
+next:Descend("Folder", "Value").Value = next:Descend("Folder", "C")
+local actions = prev:Diff(next)
+T.Pass(#actions == 4, "diff by path finds each difference")
+T.Pass(count(actions, "Add") == 1 and count(actions, "Remove") == 1 and count(actions, "Change") == 2, "diff by path action types")
+T.Pass(actions[1].Type == rbxmk.Enum.InstanceActionType.Add, "adds are first")
+T.Pass(actions[1].Instance.Name == "C" and actions[1].Parent[1] == "Folder", "add has instance and parent")
+T.Pass(actions[4].Type == rbxmk.Enum.InstanceActionType.Remove and actions[4].Path[1] == "B", "removes are last")
+T.Pass(tostring(actions[2]) == "Change A.Size: 1, 1, 1 -> 2, 2, 2", "action converts to string")
+
+prev:Patch(actions)
+T.Pass(#prev:Diff(next) == 0, "patch by path produces next tree")
+T.Pass(prev:Descend("B") == nil, "patch removes instance")
+T.Pass(prev:Descend("Folder", "Value").Value == prev:Descend("Folder", "C"), "patch resolves reference into tree")
+
+-- Actions can be applied to another tree.
+local variant = tree()
+variant:Descend("A").Color = Color3.new(1, 0, 0)
+variant:Patch(actions)
+T.Pass(variant:Descend("A").Size == Vector3.new(2, 2, 2), "patch applies to variant")
+T.Pass(variant:Descend("A").Color == Color3.new(1, 0, 0), "patch retains unrelated properties")
+
+T.Fail(function() Instance.new("Model"):Patch(actions) end, "patch fails when target cannot be located")
+
+-- Siblings with the same name are distinguished by position.
+local function twins()
+	local model = Instance.new("Model")
+	for i = 1, 2 do
+		local part = Instance.new("Part", model)
+		part.Name = "Part"
+		part.Size = Vector3.new(i, i, i)
+	end
+	return model
+end
+
+local prev = twins()
+local next = twins()
+next:GetChildren()[2].Size = Vector3.new(3, 3, 3)
+prev:Patch(prev:Diff(next))
+T.Pass(prev:GetChildren()[1].Size == Vector3.new(1, 1, 1), "patch does not change first duplicate")
+T.Pass(prev:GetChildren()[2].Size == Vector3.new(3, 3, 3), "patch changes second duplicate")
+
+local prev = twins()
+local next = twins()
+next:GetChildren()[2]:Destroy()
+next:GetChildren()[1].Size = Vector3.new(4, 4, 4)
+prev:Patch(prev:Diff(next))
+T.Pass(#prev:GetChildren() == 1, "patch removes one duplicate")
+T.Pass(prev:GetChildren()[1].Size == Vector3.new(4, 4, 4), "patch keeps and changes other duplicate")
+T.Pass(#prev:Diff(next) == 0, "patch with duplicates produces next tree")
+
+local prev = twins()
+local next = twins()
+next:GetChildren()[1]:Destroy()
+Instance.new("Folder", next:GetChildren()[1]).Name = "Child"
+prev:Patch(prev:Diff(next))
+T.Pass(#prev:GetChildren() == 1 and prev:GetChildren()[1].Size == Vector3.new(2, 2, 2), "patch removes first duplicate")
+T.Pass(prev:GetChildren()[1]:FindFirstChild("Child") ~= nil, "patch adds to second duplicate")
+
+-- Diff by reference.
+local prev = tree()
+for i, inst in ipairs(prev:GetDescendants()) do
+	inst[sym.Reference] = "REF" .. i
+end
+local next = prev:Clone()
+next:Descend("A").Name = "Renamed"
+next:Descend("B").Parent = next:Descend("Folder")
+local actions = prev:Diff(next, "Reference")
+T.Pass(#actions == 2, "diff by reference finds each difference")
+T.Pass(count(actions, "Move") == 1 and count(actions, "Change") == 1, "diff by reference detects moves and renames")
+T.Pass(count(prev:Diff(next), "Remove") == 2 and count(prev:Diff(next), "Add") == 2, "diff by path treats moves and renames as removes and adds")
+prev:Patch(actions)
+T.Pass(prev:Descend("Folder", "B") ~= nil and prev:Descend("Renamed") ~= nil, "patch by reference moves and renames")
+T.Pass(#prev:Diff(next, "Reference") == 0, "patch by reference produces next tree")
+
+T.Fail(function() prev:Diff(next, "Name") end, "invalid mode")
@@ -0,0 +1,122 @@
+local function tree()
+	local model = Instance.new("Model")
+	model.Name = "Model"
+	local a = Instance.new("Part", model)
+	a.Name = "A"
+	a.Size = Vector3.new(1, 1, 1)
+	a.Transparency = 0
+	local b = Instance.new("Part", model)
+	b.Name = "B"
+	local folder = Instance.new("Folder", model)
+	folder.Name = "Folder"
+	local value = Instance.new("ObjectValue", folder)
+	value.Name = "Value"
+	return model
+end
+
+-- Changes from both sides are merged.
+local base = tree()
+local ours = tree()
+local theirs = tree()
+ours:Descend("A").Size = Vector3.new(2, 2, 2)
+theirs:Descend("A").Transparency = 0.5
+theirs:Descend("B"):Destroy()
+local c = Instance.new("Part", theirs:Descend("Folder"))
+c.Name = "C"
+theirs:Descend("Folder", "Value").Value = c
+local merged, conflicts = base:Merge(ours, theirs)
+T.Pass(#conflicts == 0, "independent changes do not conflict")
+T.Pass(merged:Descend("A").Size == Vector3.new(2, 2, 2), "merge includes change from ours")
+T.Pass(merged:Descend("A").Transparency == 0.5, "merge includes change from theirs")
+T.Pass(merged:Descend("B") == nil, "merge includes removal from theirs")
+T.Pass(merged:Descend("Folder", "Value").Value == merged:Descend("Folder", "C"), "merge includes addition from theirs")
+T.Pass(#base:Diff(tree()) == 0, "merge does not modify base")
+
+-- Identical changes are applied once.
+local base = tree()
+local ours = tree()
+local theirs = tree()
+ours:Descend("A").Size = Vector3.new(3, 3, 3)
+theirs:Descend("A").Size = Vector3.new(3, 3, 3)
+ours:Descend("B"):Destroy()
+theirs:Descend("B"):Destroy()
+Instance.new("Folder", ours).Name = "D"
+Instance.new("Folder", theirs).Name = "D"
+local merged, conflicts = base:Merge(ours, theirs)
+T.Pass(#conflicts == 0, "identical changes do not conflict")
+T.Pass(#merged:Diff(ours) == 0, "identical changes are applied once")
+
+-- Conflicting property changes keep ours.
+local base = tree()
+local ours = tree()
+local theirs = tree()
+ours:Descend("A").Size = Vector3.new(2, 2, 2)
+theirs:Descend("A").Size = Vector3.new(3, 3, 3)
+local merged, conflicts = base:Merge(ours, theirs)
+T.Pass(#conflicts == 1, "same property changed differently conflicts")
+T.Pass(conflicts[1].Path[1] == "A", "conflict has path")
+T.Pass(conflicts[1].Ours.Next == Vector3.new(2, 2, 2) and conflicts[1].Theirs.Next == Vector3.new(3, 3, 3), "conflict has both actions")
+T.Pass(merged:Descend("A").Size == Vector3.new(2, 2, 2), "conflict resolves to ours")
+T.Pass(string.find(tostring(conflicts[1]), "^Conflict A: ours: Change") ~= nil, "conflict converts to string")
+
+-- Removing an instance that is modified by the other side conflicts.
+local base = tree()
+local ours = tree()
+local theirs = tree()
+ours:Descend("Folder"):Destroy()
+theirs:Descend("Folder", "Value").Value = theirs:Descend("A")
+local merged, conflicts = base:Merge(ours, theirs)
+T.Pass(#conflicts == 1, "removed by ours, modified by theirs conflicts")
+T.Pass(conflicts[1].Ours.Type == rbxmk.Enum.InstanceActionType.Remove, "conflict has remove action")
+T.Pass(merged:Descend("Folder") == nil, "removal by ours is kept")
+
+local base = tree()
+local ours = tree()
+local theirs = tree()
+Instance.new("Part", ours:Descend("Folder")).Name = "E"
+theirs:Descend("Folder"):Destroy()
+local merged, conflicts = base:Merge(ours, theirs)
+T.Pass(#conflicts == 1, "modified by ours, removed by theirs conflicts")
+T.Pass(conflicts[1].Theirs.Type == rbxmk.Enum.InstanceActionType.Remove, "conflict has remove action")
+T.Pass(merged:Descend("Folder", "E") ~= nil, "modification by ours is kept")
+
+-- Siblings with the same name are distinguished by position.
+local function twins()
+	local model = Instance.new("Model")
+	for i = 1, 2 do
+		local part = Instance.new("Part", model)
+		part.Name = "Part"
+		part.Size = Vector3.new(i, i, i)
+	end
+	return model
+end
+
+local base = twins()
+local ours = twins()
+local theirs = twins()
+ours:GetChildren()[1].Size = Vector3.new(3, 3, 3)
+theirs:GetChildren()[2].Size = Vector3.new(4, 4, 4)
+local merged, conflicts = base:Merge(ours, theirs)
+T.Pass(#conflicts == 0, "changes to different duplicates do not conflict")
+T.Pass(merged:GetChildren()[1].Size == Vector3.new(3, 3, 3), "merge changes first duplicate from ours")
+T.Pass(merged:GetChildren()[2].Size == Vector3.new(4, 4, 4), "merge changes second duplicate from theirs")
+
+local base = twins()
+local ours = twins()
+local theirs = twins()
+ours:GetChildren()[1].Size = Vector3.new(3, 3, 3)
+theirs:GetChildren()[2]:Destroy()
+local merged, conflicts = base:Merge(ours, theirs)
+T.Pass(#conflicts == 0, "removing a duplicate does not conflict with changing another")
+T.Pass(#merged:GetChildren() == 1 and merged:GetChildren()[1].Size == Vector3.new(3, 3, 3), "merge removes correct duplicate")
+
+local base = twins()
+local ours = twins()
+local theirs = twins()
+ours:GetChildren()[2].Size = Vector3.new(3, 3, 3)
+theirs:GetChildren()[2]:Destroy()
+local merged, conflicts = base:Merge(ours, theirs)
+T.Pass(#conflicts == 1, "removing a duplicate conflicts with changing it")
+T.Pass(#merged:GetChildren() == 2 and merged:GetChildren()[2].Size == Vector3.new(3, 3, 3), "conflict on duplicate resolves to ours")
+
+T.Fail(function() tree():Merge(tree(), tree(), "Foo") end, "invalid mode")
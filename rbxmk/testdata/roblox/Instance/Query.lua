@@ -0,0 +1,83 @@
+local game = Instance.new("DataModel")
+local workspace = game:GetService("Workspace")
+local enemyA = Instance.new("Model", workspace)
+enemyA.Name = "Goblin"
+enemyA:AddTag("Enemy")
+enemyA:SetAttribute("Health", 100)
+enemyA:SetAttribute("Kind", "melee")
+local partA = Instance.new("Part", Instance.new("Folder", enemyA))
+partA.Name = "Head"
+local enemyB = Instance.new("Model", workspace)
+enemyB.Name = "Orc"
+enemyB:AddTag("Enemy")
+enemyB:AddTag("Boss")
+enemyB:SetAttribute("Health", 25)
+enemyB:SetAttribute("Flying", true)
+local partB = Instance.new("Part", enemyB)
+local storage = Instance.new("Folder", game)
+storage.Name = "Storage"
+local stored = Instance.new("Model", storage)
+stored:AddTag("Enemy")
+stored:SetAttribute("Health", 100)
+local partC = Instance.new("Part", stored)
+
+local function same(list, expected)
+	if #list ~= #expected then
+		return false
+	end
+	for i, v in ipairs(expected) do
+		if list[i] ~= v then
+			return false
+		end
+	end
+	return true
+end
+
+T.Pass(same(game:QueryDescendants("Model"), {enemyA, enemyB, stored})                           , "matches class")
+T.Pass(same(game:QueryDescendants("*"), game:GetDescendants())                                 , "universal matches all descendants")
+T.Pass(same(game:QueryDescendants("#Orc"), {enemyB})                                           , "matches name")
+T.Pass(same(game:QueryDescendants("#'Orc'"), {enemyB})                                         , "matches quoted name")
+T.Pass(same(game:QueryDescendants(".Enemy"), {enemyA, enemyB, stored})                         , "matches tag")
+T.Pass(same(game:QueryDescendants(".Enemy.Boss"), {enemyB})                                    , "matches multiple tags")
+T.Pass(same(game:QueryDescendants("[Flying]"), {enemyB})                                       , "matches attribute existence")
+T.Pass(same(game:QueryDescendants("[Health>50]"), {enemyA, stored})                            , "matches greater than")
+T.Pass(same(game:QueryDescendants("[Health <= 25]"), {enemyB})                                 , "matches less than or equal")
+T.Pass(same(game:QueryDescendants("[Health=100]"), {enemyA, stored})                           , "matches numeric equality")
+T.Pass(same(game:QueryDescendants("[Health!=100]"), {enemyB})                                  , "matches numeric inequality")
+T.Pass(same(game:QueryDescendants("[Kind=melee]"), {enemyA})                                   , "matches identifier value")
+T.Pass(same(game:QueryDescendants("[Kind=\"melee\"]"), {enemyA})                               , "matches string value")
+T.Pass(same(game:QueryDescendants("[Kind>1]"), {})                                             , "string attribute does not compare with number")
+T.Pass(same(game:QueryDescendants("[Flying=true]"), {enemyB})                                  , "matches bool value")
+T.Pass(same(game:QueryDescendants("Model[Health>50]"), {enemyA, stored})                       , "matches class and attribute")
+T.Pass(same(game:QueryDescendants("Workspace > Model.Enemy[Health>50] Part"), {partA})         , "matches combinators")
+T.Pass(same(game:QueryDescendants("Workspace > Model > Part"), {partB})                        , "child combinator requires parent")
+T.Pass(same(game:QueryDescendants("Model Part"), {partA, partB, partC})                        , "descendant combinator matches any ancestor")
+T.Pass(same(game:QueryDescendants("#Orc, #Storage"), {enemyB, storage})                        , "matches selector list in tree order")
+T.Pass(same(workspace:QueryDescendants("Workspace Model"), {})                                 , "ancestors are limited to descendants of root")
+T.Pass(same(workspace:QueryDescendants("Model"), {enemyA, enemyB})                             , "matches only descendants")
+T.Pass(game:Query(".Enemy") == enemyA                                                          , "Query returns first match")
+T.Pass(game:Query("#Nothing") == nil                                                           , "Query returns nil without match")
+
+T.Fail(function() game:QueryDescendants("") end                                                , "empty selector errors")
+T.Fail(function() game:QueryDescendants("Model >") end                                         , "trailing combinator errors")
+T.Fail(function() game:QueryDescendants("[Health") end                                         , "unterminated attribute errors")
+T.Fail(function() game:QueryDescendants("[Flying>true]") end                                   , "ordering bool errors")
+T.Fail(function() game:QueryDescendants("#'Orc") end                                           , "unterminated string errors")
+T.Fail(function() game:QueryDescendants("Model,") end                                          , "trailing comma errors")
+T.Fail(function() game:Query("Model >> Part") end                                              , "Query errors with invalid selector")
+T.Fail(function() game:Query(42) end                                                           , "Query expects a string")
+
+-- With desc
+local desc = fs.read(path.expand("$sd/../../dump.desc.json"))
+game[sym.Desc] = desc
+T.Pass(same(game:QueryDescendants("BasePart"), {partA, partB, partC})                          , "matches superclass with desc")
+T.Pass(same(game:QueryDescendants("PVInstance > BasePart"), {partB, partC})                    , "matches superclass in combinator with desc")
+
+-- Properties
+partA.CanCollide = false
+partB.CanCollide = true
+T.Pass(same(game:QueryDescendants("Part[.CanCollide=false]"), {partA})                         , "matches property value")
+T.Pass(same(game:QueryDescendants("[.CanCollide]"), {partA, partB})                            , "matches property existence")
+T.Pass(same(game:QueryDescendants("[.Name=Head]"), {partA})                                    , "matches Name property")
+T.Pass(same(game:QueryDescendants("[.Health]"), {})                                            , "attributes are not properties")
+T.Pass(same(game:QueryDescendants("[CanCollide]"), {})                                         , "properties are not attributes")
@@ -0,0 +1,99 @@
+local function near(a, b)
+	if typeof(a) == "CFrame" then
+		local ac = {a:GetComponents()}
+		local bc = {b:GetComponents()}
+		for i = 1, #ac do
+			if math.abs(ac[i] - bc[i]) > 1e-4 then
+				return false
+			end
+		end
+		return true
+	end
+	return (a - b).Magnitude < 1e-4
+end
+
+-- BasePart
+local part = Instance.new("Part")
+part.CFrame = CFrame.new(1, 2, 3)
+part.Size = Vector3.new(2, 4, 6)
+T.Pass(near(part:GetPivot(), CFrame.new(1, 2, 3))                    , "part pivot without PivotOffset is CFrame")
+part.PivotOffset = CFrame.new(0, 1, 0)
+T.Pass(near(part:GetPivot(), CFrame.new(1, 3, 3))                    , "part pivot is offset by PivotOffset")
+T.Pass(function() part:PivotTo(CFrame.new(10, 0, 0)) end             , "can pivot part")
+T.Pass(near(part.CFrame, CFrame.new(10, -1, 0))                      , "pivoting part sets CFrame")
+T.Pass(near(part:GetPivot(), CFrame.new(10, 0, 0))                   , "pivoting part moves pivot to target")
+local cf, size = part:GetBoundingBox()
+T.Pass(near(cf, part.CFrame) and near(size, part.Size)               , "part bounding box is CFrame and Size")
+T.Pass(near(part:GetExtentsSize(), Vector3.new(2, 4, 6))             , "part extents size is Size")
+T.Fail(function() part:PivotTo(Vector3.new()) end                    , "PivotTo expects a CFrame")
+part.CFrame = 42
+T.Fail(function() part:GetPivot() end                                , "errors if CFrame is not a CFrame")
+
+-- Model
+local model = Instance.new("Model")
+local a = Instance.new("Part", model)
+a.CFrame = CFrame.new(0, 0, 0)
+a.Size = Vector3.new(2, 2, 2)
+local b = Instance.new("Part", Instance.new("Folder", model))
+b.CFrame = CFrame.new(4, 0, 0)
+b.Size = Vector3.new(2, 2, 2)
+T.Pass(near(model:GetPivot(), CFrame.new(2, 0, 0))                   , "model pivot defaults to bounding box center")
+local cf, size = model:GetBoundingBox()
+T.Pass(near(cf, CFrame.new(2, 0, 0))                                 , "model bounding box contains descendant parts")
+T.Pass(near(size, Vector3.new(6, 2, 2))                              , "model bounding box size contains descendant parts")
+T.Pass(near(model:GetExtentsSize(), Vector3.new(6, 2, 2))            , "model extents size is bounding box size")
+T.Pass(function() model:PivotTo(CFrame.new(2, 10, 0)) end            , "can pivot model")
+T.Pass(near(a.CFrame, CFrame.new(0, 10, 0))                          , "pivoting model moves parts")
+T.Pass(near(b.CFrame, CFrame.new(4, 10, 0))                          , "pivoting model moves descendant parts")
+
+model.WorldPivotData = Optional.none("CFrame")
+T.Pass(near(model:GetPivot(), CFrame.new(2, 10, 0))                  , "model pivot with empty WorldPivotData is bounding box center")
+model.WorldPivotData = Optional.some(CFrame.new(0, 10, 0) * CFrame.Angles(0, math.pi/2, 0))
+T.Pass(near(model:GetPivot(), model.WorldPivotData.Value)            , "model pivot is WorldPivotData")
+T.Pass(near(model:GetExtentsSize(), Vector3.new(2, 2, 6))            , "model bounding box is oriented by pivot")
+T.Pass(function() model:PivotTo(CFrame.new(0, 0, 0)) end             , "can pivot model with WorldPivotData")
+T.Pass(typeof(model.WorldPivotData) == "Optional"                    , "pivoting model retains WorldPivotData type")
+T.Pass(near(model.WorldPivotData.Value, CFrame.new(0, 0, 0))         , "pivoting model moves WorldPivotData")
+T.Pass(near(a.CFrame, CFrame.new(0, 0, 0) * CFrame.Angles(0, -math.pi/2, 0)), "pivoting model rotates parts")
+model.WorldPivotData = CFrame.new(1, 1, 1)
+T.Pass(near(model:GetPivot(), CFrame.new(1, 1, 1))                   , "WorldPivotData can be a CFrame")
+
+model.PrimaryPart = b
+b.CFrame = CFrame.new(5, 5, 5)
+b.PivotOffset = CFrame.new(1, 0, 0)
+T.Pass(near(model:GetPivot(), CFrame.new(6, 5, 5))                   , "model pivot is pivot of PrimaryPart")
+model.PrimaryPart = Instance.new("Part")
+T.Pass(near(model:GetPivot(), CFrame.new(1, 1, 1))                   , "PrimaryPart must be a descendant")
+
+-- Nested models
+local model = Instance.new("Model")
+model.WorldPivotData = CFrame.new(0, 0, 0)
+local sub = Instance.new("Model", model)
+sub.WorldPivotData = CFrame.new(5, 0, 0)
+local c = Instance.new("Part", sub)
+c.CFrame = CFrame.new(5, 0, 0)
+local deep = Instance.new("Model", Instance.new("Folder", sub))
+deep.WorldPivotData = Optional.some(CFrame.new(0, 5, 0))
+T.Pass(function() model:PivotTo(CFrame.new(100, 0, 0)) end           , "can pivot nested models")
+T.Pass(near(c.CFrame, CFrame.new(105, 0, 0))                         , "pivoting model moves parts of nested model")
+T.Pass(near(sub:GetPivot(), CFrame.new(105, 0, 0))                   , "pivoting model moves pivot of nested model")
+T.Pass(typeof(deep.WorldPivotData) == "Optional"                     , "pivoting model retains WorldPivotData type of nested model")
+T.Pass(near(deep.WorldPivotData.Value, CFrame.new(100, 5, 0))        , "pivoting model moves pivot of descendant model")
+
+local empty = Instance.new("Model")
+local cf, size = empty:GetBoundingBox()
+T.Pass(near(cf, CFrame.new()) and near(size, Vector3.new())          , "empty model has empty bounding box")
+
+T.Fail(function() Instance.new("Folder"):GetPivot() end              , "GetPivot expects Model or BasePart")
+T.Fail(function() Instance.new("Folder"):PivotTo(CFrame.new()) end   , "PivotTo expects Model or BasePart")
+T.Fail(function() Instance.new("Folder"):GetBoundingBox() end        , "GetBoundingBox expects Model or BasePart")
+T.Fail(function() Instance.new("Folder"):GetExtentsSize() end        , "GetExtentsSize expects Model or BasePart")
+
+-- With desc
+local desc = fs.read(path.expand("$sd/../../dump.desc.json"))
+local part = Instance.new("Part")
+part[sym.Desc] = desc
+T.Pass(near(part:GetPivot(), CFrame.new())                           , "part with desc has pivot")
+local folder = Instance.new("Folder")
+folder[sym.Desc] = desc
+T.Fail(function() folder:GetPivot() end                              , "Folder with desc has no pivot")
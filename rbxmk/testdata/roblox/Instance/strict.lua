@@ -0,0 +1,45 @@
+local desc = fs.read(path.expand("$sd/../../dump.desc.json"))
+
+T.Pass(desc.Strict == false, "Strict defaults to false")
+T.Fail(function() desc.Strict = 1 end, "Strict requires bool")
+
+-- Validation that applies regardless of Strict.
+local part = Instance.new("Part", nil, desc)
+T.Fail(function() part.Anchored = "true" end, "reject string for bool")
+T.Pass(function() part.Anchored = true end, "accept bool")
+T.Fail(function() part.Bogus = 1 end, "reject unknown property")
+
+-- Non-strict allows read-only, non-scriptable, and lossy assignments.
+local value = Instance.new("IntValue", nil, desc)
+T.Pass(function() part.AssemblyMass = 1 end, "non-strict allows read-only")
+T.Pass(function() part["Pivot Offset Position"] = Vector3.new() end, "non-strict allows not scriptable")
+T.Pass(function() value.Value = 1.5 end, "non-strict allows fractional int")
+T.Pass(value.Value == 1, "non-strict truncates fractional int")
+
+-- Strict.
+desc.Strict = true
+T.Pass(desc.Strict == true, "set Strict")
+T.Pass(desc:Copy().Strict == true, "Copy retains Strict")
+T.Fail(function() part.Anchored = "true" end, "strict rejects string for bool")
+T.Fail(function() part.AssemblyMass = 1 end, "strict rejects read-only")
+T.Fail(function() part["Pivot Offset Position"] = Vector3.new() end, "strict rejects not scriptable")
+T.Pass(function() part.Transparency = 0.5 end, "strict accepts float")
+T.Pass(function() value.Value = 2 end, "strict accepts integral number")
+T.Pass(value.Value == 2, "strict converts integral number")
+T.Fail(function() value.Value = 2.5 end, "strict rejects fractional int")
+T.Fail(function() value.Value = 2^64 end, "strict rejects int out of range")
+T.Fail(function() value.Value = 0/0 end, "strict rejects NaN")
+T.Pass(value.Value == 2, "failed assignment leaves value unchanged")
+T.Fail(function() part[sym.Properties] = {AssemblyMass = 1} end, "strict applies to sym.Properties")
+
+-- Strict applies through the global descriptor.
+desc.Strict = false
+local global = desc:Copy()
+global.Strict = true
+rbxmk.globalDesc = global
+local part = Instance.new("Part")
+T.Fail(function() part.AssemblyMass = 1 end, "global strict rejects read-only")
+T.Pass(function() part.Anchored = true end, "global strict accepts bool")
+part[sym.Desc] = desc
+T.Pass(function() part.AssemblyMass = 1 end, "instance descriptor overrides global")
+rbxmk.globalDesc = nil
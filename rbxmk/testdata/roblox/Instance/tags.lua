@@ -0,0 +1,51 @@
+local instance = Instance.new("Folder")
+T.Pass(#instance:GetTags() == 0                       , "initializes with no tags")
+T.Pass(instance:HasTag("Foo") == false                , "does not have unadded tag")
+T.Pass(function() instance:AddTag("Foo") end          , "can add tag")
+T.Pass(function() instance:AddTag("Bar") end          , "can add another tag")
+T.Pass(function() instance:AddTag("Foo") end          , "can add existing tag")
+T.Pass(instance:HasTag("Foo")                         , "has added tag")
+T.Pass(#instance:GetTags() == 2                       , "adding existing tag does not duplicate")
+T.Pass(instance:GetTags()[1] == "Foo"                 , "tags retain order")
+T.Pass(instance.Tags == "Foo\0Bar"                    , "tags are serialized as NUL-separated names")
+T.Pass(function() instance:RemoveTag("Foo") end       , "can remove tag")
+T.Pass(function() instance:RemoveTag("Fizz") end      , "can remove nonexistent tag")
+T.Pass(instance:HasTag("Foo") == false                , "does not have removed tag")
+T.Pass(instance.Tags == "Bar"                         , "removed tag is serialized")
+T.Fail(function() instance:AddTag(42) end             , "expects a string for tag")
+
+instance[sym.TagConfig] = TagConfig.new("CustomTags")
+T.Pass(#instance:GetTags() == 0                       , "TagConfig selects property")
+T.Pass(function() instance:AddTag("Baz") end          , "can add tag to custom property")
+T.Pass(instance.CustomTags == "Baz"                   , "tag is serialized to custom property")
+T.Pass(instance.Tags == "Bar"                         , "default property is unchanged")
+instance[sym.TagConfig] = nil
+
+local child = Instance.new("Folder", instance)
+child[sym.RawTagConfig] = TagConfig.new("")
+T.Pass(function() child:AddTag("Bar") end             , "empty Property defaults to Tags")
+T.Pass(child.Tags == "Bar"                            , "tag is serialized to Tags")
+instance.Tags = "\0Foo\0\0Bar\0"
+T.Pass(#instance:GetTags() == 2                       , "empty names are ignored")
+instance.Tags = 42
+T.Fail(function() instance:GetTags() end              , "errors if property is not string-like")
+instance.Tags = nil
+
+local game = Instance.new("DataModel")
+local a = Instance.new("Folder", game)
+local b = Instance.new("Folder", a)
+local c = Instance.new("Folder", game)
+a:AddTag("Foo")
+b:AddTag("Foo")
+c:AddTag("Bar")
+T.Pass(#game:GetTagged("Foo") == 2                    , "GetTagged returns tagged descendants")
+T.Pass(game:GetTagged("Foo")[1] == a                  , "GetTagged returns descendants in order")
+T.Pass(#game:GetTagged("Fizz") == 0                   , "GetTagged returns empty list for unused tag")
+T.Fail(function() a:GetTagged("Foo") end              , "GetTagged exists only on DataModel")
+
+rbxmk.globalTagConfig = TagConfig.new("GlobalTags")
+T.Pass(function() c:AddTag("Baz") end                 , "can add tag with global TagConfig")
+T.Pass(c.GlobalTags == "Baz"                          , "global TagConfig selects property")
+T.Pass(rbxmk.globalTagConfig.Property == "GlobalTags" , "can get global TagConfig")
+rbxmk.globalTagConfig = nil
+T.Pass(rbxmk.globalTagConfig == nil                   , "can set global TagConfig to nil")
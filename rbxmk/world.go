@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"net/http"
 	"os"
 	"sort"
 	"strconv"
@@ -42,6 +43,7 @@ type WorldFlags struct {
 	InsecurePaths bool
 	Debug         bool
 	Libraries     []string
+	HttpCache     string
 }
 
 func (f *WorldFlags) SetFlags(flags *pflag.FlagSet) {
@@ -66,6 +68,12 @@ func (f *WorldFlags) SetFlags(flags *pflag.FlagSet) {
 	Register.NewFlag(dump.Flag{
 		Description: "Flags/world:Flags/debug",
 	}, flags, "debug")
+
+	flags.StringVar(&f.HttpCache, "http-cache", "", "")
+	Register.NewFlag(dump.Flag{
+		Type:        "path",
+		Description: "Flags/world:Flags/http-cache",
+	}, flags, "http-cache")
 }
 
 // WorldOpt are options to InitWorld.
@@ -88,6 +96,11 @@ func InitWorld(opt WorldOpt) (world *rbxmk.World, err error) {
 		IncludeGoStackTrace: opt.Debug,
 	}))
 	world.EnvHook = opt.EventHook
+	if opt.HttpCache != "" {
+		world.Client = rbxmk.NewClient(&http.Client{
+			Transport: rbxmk.NewHttpCache(opt.HttpCache, nil),
+		})
+	}
 	if !opt.ExcludeRoots {
 		if opt.InsecurePaths {
 			world.FS.SetSecured(false)
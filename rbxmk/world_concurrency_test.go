@@ -0,0 +1,79 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	lua "github.com/anaminus/gopher-lua"
+	"github.com/anaminus/rbxmk/library"
+)
+
+// TestHttpConcurrency verifies that requests resolved together run
+// concurrently, up to the global concurrency limit.
+func TestHttpConcurrency(t *testing.T) {
+	var active, peak int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&active, 1)
+		defer atomic.AddInt32(&active, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+		io.WriteString(w, r.URL.Path)
+	}))
+	defer server.Close()
+
+	world, err := InitWorld(WorldOpt{
+		IncludeLibraries: library.All(),
+		ExcludeRoots:     true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	world.LuaState().SetGlobal("URL", lua.LString(server.URL))
+
+	script := `
+	rbxmk.globalHttpConcurrency = 3
+	assert(rbxmk.globalHttpConcurrency == 3)
+	local requests = {}
+	for i = 1, 10 do
+		requests[i] = http.request({URL=URL.."/"..i, ResponseFormat="bin"})
+	end
+	local resps = http.all(requests)
+	assert(#resps == 10, "expected 10 responses")
+	for i, resp in ipairs(resps) do
+		assert(resp.Body == "/"..i, "unexpected body "..resp.Body)
+	end
+
+	local ok, err = pcall(http.all, {
+		http.request({URL=URL.."/a", ResponseFormat="bin"}),
+		http.request({URL="http://localhost:0", ResponseFormat="bin"}),
+		http.request({URL=URL.."/c", ResponseFormat="bin"}),
+	})
+	assert(not ok and string.find(err, "request #2", 1, true), "expected error for request #2")
+
+	local resp, i = http.any({
+		http.request({URL="http://localhost:0"}),
+		http.request({URL=URL.."/b", ResponseFormat="bin"}),
+	})
+	assert(i == 2 and resp.Body == "/b", "expected second response")
+	assert(not pcall(http.any, {http.request({URL="http://localhost:0"})}))
+	`
+	if err := world.DoString(script, "test", 0); err != nil {
+		t.Fatal(err)
+	}
+	if p := atomic.LoadInt32(&peak); p > 3 || p < 2 {
+		t.Errorf("expected between 2 and 3 concurrent requests, got %d", p)
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	lua "github.com/anaminus/gopher-lua"
+	"github.com/anaminus/rbxmk/library"
+)
+
+// TestHttpRetry verifies that requests are retried according to the global
+// retry policy, and the policy specified by HttpOptions.
+func TestHttpRetry(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Fail the first two requests of every three.
+		switch atomic.AddInt32(&requests, 1) % 3 {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			io.WriteString(w, "ok")
+		}
+	}))
+	defer server.Close()
+
+	world, err := InitWorld(WorldOpt{
+		IncludeLibraries: library.All(),
+		ExcludeRoots:     true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	world.LuaState().SetGlobal("URL", lua.LString(server.URL))
+
+	tests := []struct {
+		script   string
+		requests int32
+	}{
+		// Not retried by default.
+		{`assert(http.request({URL=URL}):Resolve().StatusCode == 429)`, 1},
+		{`assert(http.request({URL=URL}):Resolve().StatusCode == 503)`, 1},
+		{`assert(http.request({URL=URL}):Resolve().StatusCode == 200)`, 1},
+		// Retried by options.
+		{`assert(http.request({URL=URL, Retries=1, RetryDelay=0}):Resolve().StatusCode == 503)`, 2},
+		{`assert(http.request({URL=URL, Retries=0}):Resolve().StatusCode == 200)`, 1},
+		{`assert(http.request({URL=URL, Retries=2, RetryDelay=0}):Resolve().StatusCode == 200)`, 3},
+		// Retried by global setting.
+		{`rbxmk.globalHttpRetries = 5; rbxmk.globalHttpRetryDelay = 0`, 0},
+		{`assert(http.request({URL=URL}):Resolve().StatusCode == 200)`, 3},
+		{`assert(http.request({URL=URL, Retries=0}):Resolve().StatusCode == 429)`, 1},
+		{`assert(rbxmk.globalHttpRetries == 5 and rbxmk.globalHttpRetryDelay == 0)`, 0},
+		{`assert(not pcall(function() rbxmk.globalHttpRetries = -1 end))`, 0},
+	}
+	for _, test := range tests {
+		start := atomic.LoadInt32(&requests)
+		if err := world.DoString(test.script, "test", 0); err != nil {
+			t.Errorf("%s: %s", test.script, err)
+			continue
+		}
+		if n := atomic.LoadInt32(&requests) - start; n != test.requests {
+			t.Errorf("%s: expected %d requests, got %d", test.script, test.requests, n)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	lua "github.com/anaminus/gopher-lua"
+	"github.com/anaminus/rbxmk"
+	"github.com/anaminus/rbxmk/library"
+	"github.com/anaminus/rbxmk/rtypes"
+)
+
+// TestHttpTimeout verifies that requests time out according to the global
+// timeout and HttpOptions, and that progress is reported while resolving.
+func TestHttpTimeout(t *testing.T) {
+	const size = 1 << 16
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/hang":
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+		case "/slow":
+			// Write body in chunks, so that progress is reported more than
+			// once.
+			w.Header().Set("Content-Length", strconv.Itoa(size))
+			chunk := bytes.Repeat([]byte{'a'}, size/4)
+			for i := 0; i < 4; i++ {
+				w.Write(chunk)
+				w.(http.Flusher).Flush()
+				time.Sleep(150 * time.Millisecond)
+			}
+		}
+	}))
+	defer server.Close()
+
+	world, err := InitWorld(WorldOpt{
+		IncludeLibraries: library.All(),
+		ExcludeRoots:     true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	world.LuaState().SetGlobal("URL", lua.LString(server.URL))
+	world.LuaState().SetGlobal("SIZE", lua.LNumber(size))
+
+	script := `
+	local ok, err = pcall(function()
+		return http.request({URL=URL.."/hang", Timeout=0.1}):Resolve()
+	end)
+	assert(not ok and string.find(err, "timed out", 1, true), "expected timeout from options")
+
+	rbxmk.globalHttpTimeout = 0.1
+	assert(rbxmk.globalHttpTimeout == 0.1)
+	local ok, err = pcall(function()
+		return http.request({URL=URL.."/hang"}):Resolve()
+	end)
+	assert(not ok and string.find(err, "timed out", 1, true), "expected timeout from global")
+	rbxmk.globalHttpTimeout = 0
+
+	local calls, last, lastTotal = 0, 0, nil
+	local resp = http.request({
+		URL = URL.."/slow",
+		Method = "POST",
+		RequestFormat = "bin",
+		ResponseFormat = "bin",
+		Body = "body",
+		Progress = function(sent, sendTotal, received, receiveTotal)
+			assert(sent == 4 and sendTotal == 4, "unexpected sent bytes")
+			assert(received >= last, "received decreased")
+			calls, last, lastTotal = calls + 1, received, receiveTotal
+		end,
+	}):Resolve()
+	assert(#resp.Body == SIZE, "unexpected body size")
+	assert(calls > 1, "expected multiple progress reports, got "..calls)
+	assert(last == SIZE and lastTotal == SIZE, "expected final report of entire body")
+	`
+	if err := world.DoString(script, "test", 0); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestHttpProgress verifies that progress is reported by http.all, http.any,
+// and DoHttpRequest.
+func TestHttpProgress(t *testing.T) {
+	const size = 1 << 10
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", strconv.Itoa(size))
+		w.Write(bytes.Repeat([]byte{'a'}, size))
+	}))
+	defer server.Close()
+
+	world, err := InitWorld(WorldOpt{
+		IncludeLibraries: library.All(),
+		ExcludeRoots:     true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	world.LuaState().SetGlobal("URL", lua.LString(server.URL))
+	world.LuaState().SetGlobal("SIZE", lua.LNumber(size))
+
+	script := `
+	local received = {}
+	local function request(i)
+		return http.request({
+			URL = URL,
+			ResponseFormat = "bin",
+			Progress = function(sent, sendTotal, n, total)
+				received[i] = n
+			end,
+		})
+	end
+
+	http.all({request(1), request(2)})
+	assert(received[1] == SIZE and received[2] == SIZE, "expected progress from http.all")
+
+	received = {}
+	local resp, i = http.any({request(1)})
+	assert(received[i] == SIZE, "expected progress from http.any")
+
+	PROGRESS = function(sent, sendTotal, n, total)
+		RECEIVED = n
+	end
+	`
+	if err := world.DoString(script, "test", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = rbxmk.DoHttpRequest(world, rtypes.HttpOptions{
+		URL:            server.URL,
+		Method:         "GET",
+		ResponseFormat: rtypes.FormatSelector{Format: "bin"},
+		Progress:       world.LuaState().GetGlobal("PROGRESS").(*lua.LFunction),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if received := world.LuaState().GetGlobal("RECEIVED"); received != lua.LNumber(size) {
+		t.Errorf("expected progress from DoHttpRequest, got %v", received)
+	}
+}
@@ -53,7 +53,7 @@ func AttrConfig() rbxmk.Reflector {
 					return dump.MultiFunction{
 						dump.Function{
 							Parameters: dump.Parameters{
-								{Name: "property", Type: dt.Prim(rtypes.T_String)},
+								{Name: "property", Type: dt.Optional(dt.Prim(rtypes.T_String))},
 							},
 							Returns: dump.Parameters{
 								{Type: dt.Prim(rtypes.T_AttrConfig)},
@@ -27,6 +27,23 @@ func Desc() rbxmk.Reflector {
 			}
 			return nil
 		},
+		Properties: rbxmk.Properties{
+			"Strict": {
+				Get: func(s rbxmk.State, v types.Value) int {
+					return s.Push(types.Bool(v.(*rtypes.Desc).Strict))
+				},
+				Set: func(s rbxmk.State, v types.Value) {
+					v.(*rtypes.Desc).Strict = bool(s.Pull(3, rtypes.T_Bool).(types.Bool))
+				},
+				Dump: func() dump.Property {
+					return dump.Property{
+						ValueType:   dt.Prim(rtypes.T_Bool),
+						Summary:     "Types/Desc:Properties/Strict/Summary",
+						Description: "Types/Desc:Properties/Strict/Description",
+					}
+				},
+			},
+		},
 		Methods: rbxmk.Methods{
 			"Copy": {
 				Func: func(s rbxmk.State, v types.Value) int {
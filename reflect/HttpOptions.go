@@ -1,6 +1,8 @@
 package reflect
 
 import (
+	"time"
+
 	lua "github.com/anaminus/gopher-lua"
 	"github.com/anaminus/rbxmk"
 	"github.com/anaminus/rbxmk/dump"
@@ -18,7 +20,7 @@ func HttpOptions() rbxmk.Reflector {
 			if !ok {
 				return nil, rbxmk.TypeError{Want: rtypes.T_HttpOptions, Got: v.Type()}
 			}
-			table := c.CreateTable(0, 7)
+			table := c.CreateTable(0, 11)
 			if err := c.PushToDictionary(table, "URL", types.String(options.URL)); err != nil {
 				return nil, err
 			}
@@ -40,6 +42,24 @@ func HttpOptions() rbxmk.Reflector {
 			if err := c.PushToDictionary(table, "Body", options.Body); err != nil {
 				return nil, err
 			}
+			if options.Retries != nil {
+				if err := c.PushToDictionary(table, "Retries", types.Int(*options.Retries)); err != nil {
+					return nil, err
+				}
+			}
+			if options.RetryDelay != nil {
+				if err := c.PushToDictionary(table, "RetryDelay", types.Double(options.RetryDelay.Seconds())); err != nil {
+					return nil, err
+				}
+			}
+			if options.Timeout != nil {
+				if err := c.PushToDictionary(table, "Timeout", types.Double(options.Timeout.Seconds())); err != nil {
+					return nil, err
+				}
+			}
+			if options.Progress != nil {
+				table.RawSetString("Progress", options.Progress)
+			}
 			return table, nil
 		},
 		PullFrom: func(c rbxmk.Context, lv lua.LValue) (v types.Value, err error) {
@@ -83,6 +103,37 @@ func HttpOptions() rbxmk.Reflector {
 			if err != nil {
 				return nil, err
 			}
+			retries, err := c.PullFromDictionaryOpt(table, "Retries", nil, rtypes.T_Int)
+			if err != nil {
+				return nil, err
+			}
+			if retries, ok := retries.(types.Int); ok {
+				n := int(retries)
+				options.Retries = &n
+			}
+			retryDelay, err := c.PullFromDictionaryOpt(table, "RetryDelay", nil, rtypes.T_Double)
+			if err != nil {
+				return nil, err
+			}
+			if retryDelay, ok := retryDelay.(types.Double); ok {
+				d := time.Duration(float64(retryDelay) * float64(time.Second))
+				options.RetryDelay = &d
+			}
+			timeout, err := c.PullFromDictionaryOpt(table, "Timeout", nil, rtypes.T_Double)
+			if err != nil {
+				return nil, err
+			}
+			if timeout, ok := timeout.(types.Double); ok {
+				d := time.Duration(float64(timeout) * float64(time.Second))
+				options.Timeout = &d
+			}
+			switch progress := table.RawGetString("Progress").(type) {
+			case *lua.LNilType:
+			case *lua.LFunction:
+				options.Progress = progress
+			default:
+				return nil, rbxmk.TypeError{Want: "function", Got: progress.Type().String()}
+			}
 			return options, nil
 		},
 		SetTo: func(p interface{}, v types.Value) error {
@@ -105,6 +156,17 @@ func HttpOptions() rbxmk.Reflector {
 					"Headers":        dt.Optional(dt.Prim(rtypes.T_HttpHeaders)),
 					"Cookies":        dt.Optional(dt.Prim(rtypes.T_Cookies)),
 					"Body":           dt.Optional(dt.Prim(rtypes.T_Any)),
+					"Retries":        dt.Optional(dt.Prim(rtypes.T_Int)),
+					"RetryDelay":     dt.Optional(dt.Prim(rtypes.T_Double)),
+					"Timeout":        dt.Optional(dt.Prim(rtypes.T_Double)),
+					"Progress": dt.Optional(dt.Function(dt.KindFunction{
+						Parameters: dt.Parameters{
+							{Name: "sent", Type: dt.Prim(rtypes.T_Int64)},
+							{Name: "sendTotal", Type: dt.Optional(dt.Prim(rtypes.T_Int64))},
+							{Name: "received", Type: dt.Prim(rtypes.T_Int64)},
+							{Name: "receiveTotal", Type: dt.Optional(dt.Prim(rtypes.T_Int64))},
+						},
+					})),
 				})),
 				Summary:     "Types/HttpOptions:Summary",
 				Description: "Types/HttpOptions:Description",
@@ -112,8 +174,10 @@ func HttpOptions() rbxmk.Reflector {
 		},
 		Types: []func() rbxmk.Reflector{
 			Cookies,
+			Double,
 			FormatSelector,
 			HttpHeaders,
+			Int,
 			String,
 			Variant,
 		},
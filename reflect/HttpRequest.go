@@ -27,7 +27,7 @@ func HttpRequest() rbxmk.Reflector {
 			"Resolve": {
 				Func: func(s rbxmk.State, v types.Value) int {
 					req := v.(*rbxmk.HttpRequest)
-					resp, err := req.Resolve()
+					resp, err := req.ResolveProgress(req.LuaProgress(s.L))
 					if err != nil {
 						return s.RaiseError("%s", err)
 					}
@@ -3,6 +3,7 @@ package reflect
 import (
 	"bytes"
 	"fmt"
+	"math"
 	"strings"
 
 	lua "github.com/anaminus/gopher-lua"
@@ -89,6 +90,219 @@ func setAttributes(s rbxmk.State, inst *rtypes.Instance, dict rtypes.Dictionary)
 	inst.Set(attrcfg.Property, types.BinaryString(w.Bytes()))
 }
 
+func defaultTagConfig(s rbxmk.State, inst *rtypes.Instance) rtypes.TagConfig {
+	tagcfg := s.TagConfig.Of(inst)
+	if tagcfg != nil && tagcfg.Property != "" {
+		return *tagcfg
+	}
+	return rtypes.TagConfig{Property: "Tags"}
+}
+
+func getTags(s rbxmk.State, inst *rtypes.Instance) []string {
+	tagcfg := defaultTagConfig(s, inst)
+	v := inst.Get(tagcfg.Property)
+	if v == nil {
+		return []string{}
+	}
+	sv, ok := v.(types.Stringlike)
+	if !ok {
+		s.RaiseError("property %q is not string-like", tagcfg.Property)
+		return nil
+	}
+	return rtypes.DecodeTags(sv.Stringlike())
+}
+
+func setTags(s rbxmk.State, inst *rtypes.Instance, tags []string) {
+	tagcfg := defaultTagConfig(s, inst)
+	inst.Set(tagcfg.Property, types.BinaryString(rtypes.EncodeTags(tags)))
+}
+
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// pivotClasses maps classes that inherit from Model or BasePart to the base
+// class. Used to determine the kind of an instance that has no descriptor.
+var pivotClasses = map[string]string{
+	"Actor":              "Model",
+	"Model":              "Model",
+	"Workspace":          "Model",
+	"WorldModel":         "Model",
+	"CornerWedgePart":    "BasePart",
+	"IntersectOperation": "BasePart",
+	"MeshPart":           "BasePart",
+	"NegateOperation":    "BasePart",
+	"Part":               "BasePart",
+	"PartOperation":      "BasePart",
+	"Seat":               "BasePart",
+	"SkateboardPlatform": "BasePart",
+	"SpawnLocation":      "BasePart",
+	"TrussPart":          "BasePart",
+	"UnionOperation":     "BasePart",
+	"VehicleSeat":        "BasePart",
+	"WedgePart":          "BasePart",
+}
+
+// pivotKind returns "Model" or "BasePart" if inst inherits from the respective
+// class, or an empty string otherwise.
+func pivotKind(s rbxmk.State, inst *rtypes.Instance) string {
+	if s.Desc.Of(inst) == nil {
+		return pivotClasses[inst.ClassName]
+	}
+	switch {
+	case inst.WithDescIsA(s.Desc, "BasePart"):
+		return "BasePart"
+	case inst.WithDescIsA(s.Desc, "Model"):
+		return "Model"
+	}
+	return ""
+}
+
+// checkPivotKind returns the kind of inst, throwing an error if inst is not a
+// Model or BasePart.
+func checkPivotKind(s rbxmk.State, inst *rtypes.Instance) string {
+	kind := pivotKind(s, inst)
+	if kind == "" {
+		s.RaiseError("Model or BasePart expected, got %s", inst.ClassName)
+	}
+	return kind
+}
+
+// getCFrameProperty returns the CFrame value of property name. The property
+// may also be an Optional CFrame. ok is false if the property is unset or
+// empty, in which case the identity CFrame is returned.
+func getCFrameProperty(s rbxmk.State, inst *rtypes.Instance, name string) (cf types.CFrame, ok bool) {
+	switch v := inst.Get(name).(type) {
+	case nil:
+		return types.NewCFrame(), false
+	case types.CFrame:
+		return v, true
+	case rtypes.Optional:
+		switch v := v.Value().(type) {
+		case nil:
+			return types.NewCFrame(), false
+		case types.CFrame:
+			return v, true
+		}
+	}
+	s.RaiseError("property %q is not a CFrame", name)
+	return types.NewCFrame(), false
+}
+
+// getPartSize returns the Size property of part.
+func getPartSize(s rbxmk.State, part *rtypes.Instance) types.Vector3 {
+	switch v := part.Get("Size").(type) {
+	case nil:
+		return types.Vector3{}
+	case types.Vector3:
+		return v
+	}
+	s.RaiseError("property %q is not a Vector3", "Size")
+	return types.Vector3{}
+}
+
+// getPartPivot returns the pivot of part, which is the CFrame of the part
+// offset by PivotOffset.
+func getPartPivot(s rbxmk.State, part *rtypes.Instance) types.CFrame {
+	cf, _ := getCFrameProperty(s, part, "CFrame")
+	offset, _ := getCFrameProperty(s, part, "PivotOffset")
+	return cf.Mul(offset)
+}
+
+// getModelParts returns the descendants of model that are BaseParts.
+func getModelParts(s rbxmk.State, model *rtypes.Instance) []*rtypes.Instance {
+	var parts []*rtypes.Instance
+	for _, inst := range model.Descendants() {
+		if pivotKind(s, inst) == "BasePart" {
+			parts = append(parts, inst)
+		}
+	}
+	return parts
+}
+
+// getModelPivot returns the pivot of model. This is the pivot of the
+// PrimaryPart if it is a descendant BasePart, or else WorldPivotData if it has
+// a value, or else the center of the bounding box of the model's parts.
+func getModelPivot(s rbxmk.State, model *rtypes.Instance) types.CFrame {
+	if primary, ok := model.Get("PrimaryPart").(*rtypes.Instance); ok && primary != nil {
+		if primary.IsDescendantOf(model) && pivotKind(s, primary) == "BasePart" {
+			return getPartPivot(s, primary)
+		}
+	}
+	if cf, ok := getCFrameProperty(s, model, "WorldPivotData"); ok {
+		return cf
+	}
+	cf, _ := getBoundingBox(s, getModelParts(s, model), types.NewCFrame())
+	return cf
+}
+
+// getBoundingBox returns the smallest box that contains each part, oriented by
+// the rotation of orientation. If there are no parts, then the box is located
+// at orientation with a size of zero.
+func getBoundingBox(s rbxmk.State, parts []*rtypes.Instance, orientation types.CFrame) (cf types.CFrame, size types.Vector3) {
+	if len(parts) == 0 {
+		return orientation, types.Vector3{}
+	}
+	min := types.NewVector3(math.Inf(1), math.Inf(1), math.Inf(1))
+	max := types.NewVector3(math.Inf(-1), math.Inf(-1), math.Inf(-1))
+	for _, part := range parts {
+		partCFrame, _ := getCFrameProperty(s, part, "CFrame")
+		half := getPartSize(s, part).DivN(2)
+		for i := 0; i < 8; i++ {
+			corner := half
+			if i&1 != 0 {
+				corner.X = -corner.X
+			}
+			if i&2 != 0 {
+				corner.Y = -corner.Y
+			}
+			if i&4 != 0 {
+				corner.Z = -corner.Z
+			}
+			p := orientation.PointToObjectSpace(partCFrame.PointToWorldSpace(corner))
+			min = vector3Min(min, p)
+			max = vector3Max(max, p)
+		}
+	}
+	center := min.Add(max).DivN(2)
+	return orientation.Mul(types.NewCFrameFromVector3(center)), max.Sub(min)
+}
+
+// vector3Min returns the component-wise minimum of a and b.
+func vector3Min(a, b types.Vector3) types.Vector3 {
+	return types.NewVector3(
+		math.Min(float64(a.X), float64(b.X)),
+		math.Min(float64(a.Y), float64(b.Y)),
+		math.Min(float64(a.Z), float64(b.Z)),
+	)
+}
+
+// vector3Max returns the component-wise maximum of a and b.
+func vector3Max(a, b types.Vector3) types.Vector3 {
+	return types.NewVector3(
+		math.Max(float64(a.X), float64(b.X)),
+		math.Max(float64(a.Y), float64(b.Y)),
+		math.Max(float64(a.Z), float64(b.Z)),
+	)
+}
+
+// getInstanceBoundingBox returns the bounding box of a Model or BasePart. The
+// bounding box of a Model is oriented by the model's pivot.
+func getInstanceBoundingBox(s rbxmk.State, inst *rtypes.Instance) (cf types.CFrame, size types.Vector3) {
+	if checkPivotKind(s, inst) == "BasePart" {
+		cf, _ := getCFrameProperty(s, inst, "CFrame")
+		return cf, getPartSize(s, inst)
+	}
+	orientation := getModelPivot(s, inst)
+	orientation.Position = types.Vector3{}
+	return getBoundingBox(s, getModelParts(s, inst), orientation)
+}
+
 func reflectOne(s rbxmk.State, value types.Value) (lv lua.LValue, err error) {
 	rfl := s.MustReflector(value.Type())
 	lv, err = rfl.PushTo(s.Context(), value)
@@ -197,6 +411,25 @@ func GetProperty(s rbxmk.State, inst *rtypes.Instance, name string, desc *rtypes
 	return PushVariantTo(s.Context(), value)
 }
 
+// checkIntegral returns an error if n cannot be converted to integer type t
+// without loss. Returns nil if t is not an integer type.
+func checkIntegral(n float64, t string) error {
+	// Range is [min, max).
+	var min, max float64
+	switch t {
+	case rtypes.T_Int:
+		min, max = math.MinInt32, math.MaxInt32+1
+	case rtypes.T_Int64:
+		min, max = math.MinInt64, -math.MinInt64
+	default:
+		return nil
+	}
+	if n != math.Trunc(n) || n < min || n >= max {
+		return fmt.Errorf("%s cannot be converted to %s without loss", lua.LNumber(n), t)
+	}
+	return nil
+}
+
 func canSetProperty(s rbxmk.State, inst *rtypes.Instance, name string, lvalue lua.LValue, desc *rtypes.Desc, fallback rbxmk.Reflector) (pvalue types.PropValue, err error) {
 	var classDesc *rbxdump.Class
 	if desc != nil {
@@ -229,6 +462,14 @@ func canSetProperty(s rbxmk.State, inst *rtypes.Instance, name string, lvalue lu
 	if propDesc == nil {
 		return nil, fmt.Errorf("%s is not a valid member", name)
 	}
+	if desc.Strict {
+		if propDesc.GetTag("ReadOnly") {
+			return nil, fmt.Errorf("%s is a read-only property", name)
+		}
+		if propDesc.GetTag("NotScriptable") {
+			return nil, fmt.Errorf("%s is not a scriptable property", name)
+		}
+	}
 	switch propDesc.ValueType.Category {
 	case "Class":
 		switch lvalue := lvalue.(type) {
@@ -300,6 +541,11 @@ func canSetProperty(s rbxmk.State, inst *rtypes.Instance, name string, lvalue lu
 			if rfl.PullFrom == nil {
 				return nil, fmt.Errorf("cannot set type %s", pt)
 			}
+			if n, ok := lvalue.(lua.LNumber); ok && desc.Strict {
+				if err := checkIntegral(float64(n), pt); err != nil {
+					return nil, err
+				}
+			}
 			value, err = rfl.PullFrom(s.Context(), lvalue)
 			if err != nil {
 				return nil, err
@@ -315,6 +561,11 @@ func canSetProperty(s rbxmk.State, inst *rtypes.Instance, name string, lvalue lu
 			if rfl.Name == "" || rfl.ConvertFrom == nil {
 				return nil, rbxmk.TypeError{Want: pt, Got: vt}
 			}
+			if n, ok := value.(types.Numberlike); ok && desc.Strict {
+				if err := checkIntegral(n.Numberlike(), pt); err != nil {
+					return nil, err
+				}
+			}
 			if value = rfl.ConvertFrom(value); value == nil {
 				return nil, rbxmk.TypeError{Want: pt, Got: vt}
 			}
@@ -366,6 +617,19 @@ func getService(s rbxmk.State) int {
 	return s.Push(service)
 }
 
+// pullDiffMode pulls an optional mode of matching instances between trees.
+func pullDiffMode(s rbxmk.State, n int) rtypes.InstanceDiffMode {
+	switch m := string(s.PullOpt(n, types.String("Path"), rtypes.T_String).(types.String)); m {
+	case "Path":
+		return rtypes.DiffByPath
+	case "Reference":
+		return rtypes.DiffByReference
+	default:
+		s.ArgError(n, "invalid mode %q (expected Path or Reference)", m)
+		return 0
+	}
+}
+
 func init() { register(Instance) }
 func Instance() rbxmk.Reflector {
 	return rbxmk.Reflector{
@@ -707,6 +971,85 @@ func Instance() rbxmk.Reflector {
 					}
 				},
 			},
+			rtypes.Symbol{Name: rtypes.T_TagConfig}: {
+				Get: func(s rbxmk.State, v types.Value) int {
+					inst := v.(*rtypes.Instance)
+					tagcfg := inst.TagConfig()
+					if tagcfg == nil {
+						return s.Push(rtypes.Nil)
+					}
+					return s.Push(tagcfg)
+				},
+				Set: func(s rbxmk.State, v types.Value) {
+					inst := v.(*rtypes.Instance)
+					switch v := s.PullAnyOf(3, rtypes.T_TagConfig, rtypes.T_Bool, rtypes.T_Nil).(type) {
+					case *rtypes.TagConfig:
+						inst.SetTagConfig(v, false)
+					case types.Bool:
+						if v {
+							s.RaiseError("TagConfig cannot be true")
+							return
+						}
+						inst.SetTagConfig(nil, true)
+					case rtypes.NilType:
+						inst.SetTagConfig(nil, false)
+					default:
+						s.ReflectorError(3)
+					}
+				},
+				Dump: func() dump.Property {
+					return dump.Property{
+						ValueType: dt.Or(
+							dt.Prim(rtypes.T_TagConfig),
+							dt.Prim(rtypes.T_Bool),
+							dt.Prim(rtypes.T_Nil),
+						),
+						Summary:     "Types/Instance:Symbols/TagConfig/Summary",
+						Description: "Types/Instance:Symbols/TagConfig/Description",
+					}
+				},
+			},
+			rtypes.Symbol{Name: "Raw" + rtypes.T_TagConfig}: {
+				Get: func(s rbxmk.State, v types.Value) int {
+					inst := v.(*rtypes.Instance)
+					tagcfg, blocked := inst.RawTagConfig()
+					if blocked {
+						return s.Push(types.False)
+					}
+					if tagcfg == nil {
+						return s.Push(rtypes.Nil)
+					}
+					return s.Push(tagcfg)
+				},
+				Set: func(s rbxmk.State, v types.Value) {
+					inst := v.(*rtypes.Instance)
+					switch v := s.PullAnyOf(3, rtypes.T_TagConfig, rtypes.T_Bool, rtypes.T_Nil).(type) {
+					case *rtypes.TagConfig:
+						inst.SetTagConfig(v, false)
+					case types.Bool:
+						if v {
+							s.RaiseError("TagConfig cannot be true")
+							return
+						}
+						inst.SetTagConfig(nil, true)
+					case rtypes.NilType:
+						inst.SetTagConfig(nil, false)
+					default:
+						s.ReflectorError(3)
+					}
+				},
+				Dump: func() dump.Property {
+					return dump.Property{
+						ValueType: dt.Or(
+							dt.Prim(rtypes.T_TagConfig),
+							dt.Prim(rtypes.T_Bool),
+							dt.Prim(rtypes.T_Nil),
+						),
+						Summary:     "Types/Instance:Symbols/RawTagConfig/Summary",
+						Description: "Types/Instance:Symbols/RawTagConfig/Description",
+					}
+				},
+			},
 			rtypes.Symbol{Name: "Properties"}: {
 				Get: func(s rbxmk.State, v types.Value) int {
 					inst := v.(*rtypes.Instance)
@@ -821,6 +1164,26 @@ func Instance() rbxmk.Reflector {
 					}
 				},
 			},
+			"AddTag": {
+				Func: func(s rbxmk.State, v types.Value) int {
+					inst := v.(*rtypes.Instance)
+					tag := string(s.Pull(2, rtypes.T_String).(types.String))
+					tags := getTags(s, inst)
+					if !hasTag(tags, tag) {
+						setTags(s, inst, append(tags, tag))
+					}
+					return 0
+				},
+				Dump: func() dump.Function {
+					return dump.Function{
+						Parameters: dump.Parameters{
+							{Name: "tag", Type: dt.Prim(rtypes.T_String)},
+						},
+						Summary:     "Types/Instance:Methods/AddTag/Summary",
+						Description: "Types/Instance:Methods/AddTag/Description",
+					}
+				},
+			},
 			"ClearAllChildren": {
 				Func: func(s rbxmk.State, v types.Value) int {
 					v.(*rtypes.Instance).RemoveAll()
@@ -859,6 +1222,26 @@ func Instance() rbxmk.Reflector {
 					}
 				},
 			},
+			"Diff": {
+				Func: func(s rbxmk.State, v types.Value) int {
+					next := s.Pull(2, rtypes.T_Instance).(*rtypes.Instance)
+					mode := pullDiffMode(s, 3)
+					return s.Push(v.(*rtypes.Instance).Diff(next, mode))
+				},
+				Dump: func() dump.Function {
+					return dump.Function{
+						Parameters: dump.Parameters{
+							{Name: "next", Type: dt.Prim(rtypes.T_Instance)},
+							{Name: "mode", Type: dt.Optional(dt.Prim(rtypes.T_String)), Default: `"Path"`},
+						},
+						Returns: dump.Parameters{
+							{Name: "diff", Type: dt.Prim(rtypes.T_InstanceActions)},
+						},
+						Summary:     "Types/Instance:Methods/Diff/Summary",
+						Description: "Types/Instance:Methods/Diff/Description",
+					}
+				},
+			},
 			"FindFirstAncestor": {
 				Func: func(s rbxmk.State, v types.Value) int {
 					name := string(s.Pull(2, rtypes.T_String).(types.String))
@@ -1027,6 +1410,23 @@ func Instance() rbxmk.Reflector {
 					}
 				},
 			},
+			"GetBoundingBox": {
+				Func: func(s rbxmk.State, v types.Value) int {
+					cf, size := getInstanceBoundingBox(s, v.(*rtypes.Instance))
+					return s.PushTuple(cf, size)
+				},
+				Dump: func() dump.Function {
+					return dump.Function{
+						Returns: dump.Parameters{
+							{Name: "orientation", Type: dt.Prim(rtypes.T_CFrame)},
+							{Name: "size", Type: dt.Prim(rtypes.T_Vector3)},
+						},
+						CanError:    true,
+						Summary:     "Types/Instance:Methods/GetBoundingBox/Summary",
+						Description: "Types/Instance:Methods/GetBoundingBox/Description",
+					}
+				},
+			},
 			"GetChildren": {
 				Func: func(s rbxmk.State, v types.Value) int {
 					t := v.(*rtypes.Instance).Children()
@@ -1056,6 +1456,22 @@ func Instance() rbxmk.Reflector {
 					}
 				},
 			},
+			"GetExtentsSize": {
+				Func: func(s rbxmk.State, v types.Value) int {
+					_, size := getInstanceBoundingBox(s, v.(*rtypes.Instance))
+					return s.Push(size)
+				},
+				Dump: func() dump.Function {
+					return dump.Function{
+						Returns: dump.Parameters{
+							{Type: dt.Prim(rtypes.T_Vector3)},
+						},
+						CanError:    true,
+						Summary:     "Types/Instance:Methods/GetExtentsSize/Summary",
+						Description: "Types/Instance:Methods/GetExtentsSize/Description",
+					}
+				},
+			},
 			"GetFullName": {
 				Func: func(s rbxmk.State, v types.Value) int {
 					return s.Push(types.String(v.(*rtypes.Instance).GetFullName()))
@@ -1070,6 +1486,25 @@ func Instance() rbxmk.Reflector {
 					}
 				},
 			},
+			"GetPivot": {
+				Func: func(s rbxmk.State, v types.Value) int {
+					inst := v.(*rtypes.Instance)
+					if checkPivotKind(s, inst) == "BasePart" {
+						return s.Push(getPartPivot(s, inst))
+					}
+					return s.Push(getModelPivot(s, inst))
+				},
+				Dump: func() dump.Function {
+					return dump.Function{
+						Returns: dump.Parameters{
+							{Type: dt.Prim(rtypes.T_CFrame)},
+						},
+						CanError:    true,
+						Summary:     "Types/Instance:Methods/GetPivot/Summary",
+						Description: "Types/Instance:Methods/GetPivot/Description",
+					}
+				},
+			},
 			"GetService": {
 				Cond: func(v types.Value) bool {
 					return v.(*rtypes.Instance).IsDataModel()
@@ -1090,6 +1525,70 @@ func Instance() rbxmk.Reflector {
 					}
 				},
 			},
+			"GetTagged": {
+				Cond: func(v types.Value) bool {
+					return v.(*rtypes.Instance).IsDataModel()
+				},
+				Func: func(s rbxmk.State, v types.Value) int {
+					tag := string(s.Pull(2, rtypes.T_String).(types.String))
+					tagged := rtypes.Objects{}
+					for _, inst := range v.(*rtypes.Instance).Descendants() {
+						if hasTag(getTags(s, inst), tag) {
+							tagged = append(tagged, inst)
+						}
+					}
+					return s.Push(tagged)
+				},
+				Dump: func() dump.Function {
+					return dump.Function{
+						Parameters: dump.Parameters{
+							{Name: "tag", Type: dt.Prim(rtypes.T_String)},
+						},
+						Returns: dump.Parameters{
+							{Type: dt.Prim(rtypes.T_Objects)},
+						},
+						Summary:     "Types/Instance:Methods/GetTagged/Summary",
+						Description: "Types/Instance:Methods/GetTagged/Description",
+					}
+				},
+			},
+			"GetTags": {
+				Func: func(s rbxmk.State, v types.Value) int {
+					tags := getTags(s, v.(*rtypes.Instance))
+					array := make(rtypes.Array, len(tags))
+					for i, tag := range tags {
+						array[i] = types.String(tag)
+					}
+					return s.PushArrayOf(array, rtypes.T_String)
+				},
+				Dump: func() dump.Function {
+					return dump.Function{
+						Returns: dump.Parameters{
+							{Type: dt.Array(dt.Prim(rtypes.T_String))},
+						},
+						Summary:     "Types/Instance:Methods/GetTags/Summary",
+						Description: "Types/Instance:Methods/GetTags/Description",
+					}
+				},
+			},
+			"HasTag": {
+				Func: func(s rbxmk.State, v types.Value) int {
+					tag := string(s.Pull(2, rtypes.T_String).(types.String))
+					return s.Push(types.Bool(hasTag(getTags(s, v.(*rtypes.Instance)), tag)))
+				},
+				Dump: func() dump.Function {
+					return dump.Function{
+						Parameters: dump.Parameters{
+							{Name: "tag", Type: dt.Prim(rtypes.T_String)},
+						},
+						Returns: dump.Parameters{
+							{Type: dt.Prim(rtypes.T_Bool)},
+						},
+						Summary:     "Types/Instance:Methods/HasTag/Summary",
+						Description: "Types/Instance:Methods/HasTag/Description",
+					}
+				},
+			},
 			"IsA": {
 				Func: func(s rbxmk.State, v types.Value) int {
 					className := string(s.Pull(2, rtypes.T_String).(types.String))
@@ -1144,6 +1643,173 @@ func Instance() rbxmk.Reflector {
 					}
 				},
 			},
+			"Merge": {
+				Func: func(s rbxmk.State, v types.Value) int {
+					ours := s.Pull(2, rtypes.T_Instance).(*rtypes.Instance)
+					theirs := s.Pull(3, rtypes.T_Instance).(*rtypes.Instance)
+					mode := pullDiffMode(s, 4)
+					merged, conflicts, err := v.(*rtypes.Instance).Merge(ours, theirs, mode)
+					if err != nil {
+						return s.RaiseError("%s", err)
+					}
+					if conflicts == nil {
+						conflicts = rtypes.InstanceConflicts{}
+					}
+					return s.Push(merged) + s.Push(conflicts)
+				},
+				Dump: func() dump.Function {
+					return dump.Function{
+						Parameters: dump.Parameters{
+							{Name: "ours", Type: dt.Prim(rtypes.T_Instance)},
+							{Name: "theirs", Type: dt.Prim(rtypes.T_Instance)},
+							{Name: "mode", Type: dt.Optional(dt.Prim(rtypes.T_String)), Default: `"Path"`},
+						},
+						Returns: dump.Parameters{
+							{Name: "merged", Type: dt.Prim(rtypes.T_Instance)},
+							{Name: "conflicts", Type: dt.Prim(rtypes.T_InstanceConflicts)},
+						},
+						CanError:    true,
+						Summary:     "Types/Instance:Methods/Merge/Summary",
+						Description: "Types/Instance:Methods/Merge/Description",
+					}
+				},
+			},
+			"Patch": {
+				Func: func(s rbxmk.State, v types.Value) int {
+					actions := s.Pull(2, rtypes.T_InstanceActions).(rtypes.InstanceActions)
+					if err := v.(*rtypes.Instance).Patch(actions); err != nil {
+						return s.RaiseError("%s", err)
+					}
+					return 0
+				},
+				Dump: func() dump.Function {
+					return dump.Function{
+						Parameters: dump.Parameters{
+							{Name: "actions", Type: dt.Prim(rtypes.T_InstanceActions)},
+						},
+						CanError:    true,
+						Summary:     "Types/Instance:Methods/Patch/Summary",
+						Description: "Types/Instance:Methods/Patch/Description",
+					}
+				},
+			},
+			"PivotTo": {
+				Func: func(s rbxmk.State, v types.Value) int {
+					inst := v.(*rtypes.Instance)
+					target := s.Pull(2, rtypes.T_CFrame).(types.CFrame)
+					if checkPivotKind(s, inst) == "BasePart" {
+						offset, _ := getCFrameProperty(s, inst, "PivotOffset")
+						inst.Set("CFrame", target.Mul(offset.Inverse()))
+						return 0
+					}
+					// Move each part relative to the pivot.
+					transform := target.Mul(getModelPivot(s, inst).Inverse())
+					for _, part := range getModelParts(s, inst) {
+						cf, _ := getCFrameProperty(s, part, "CFrame")
+						part.Set("CFrame", transform.Mul(cf))
+					}
+					// Move the pivot of the model and each model within it.
+					models := []*rtypes.Instance{inst}
+					for _, desc := range inst.Descendants() {
+						if pivotKind(s, desc) == "Model" {
+							models = append(models, desc)
+						}
+					}
+					for _, model := range models {
+						switch pivot := model.Get("WorldPivotData").(type) {
+						case types.CFrame:
+							model.Set("WorldPivotData", transform.Mul(pivot))
+						case rtypes.Optional:
+							if cf, ok := pivot.Value().(types.CFrame); ok {
+								model.Set("WorldPivotData", rtypes.Some(transform.Mul(cf)))
+							}
+						}
+					}
+					return 0
+				},
+				Dump: func() dump.Function {
+					return dump.Function{
+						Parameters: dump.Parameters{
+							{Name: "targetCFrame", Type: dt.Prim(rtypes.T_CFrame)},
+						},
+						CanError:    true,
+						Summary:     "Types/Instance:Methods/PivotTo/Summary",
+						Description: "Types/Instance:Methods/PivotTo/Description",
+					}
+				},
+			},
+			"Query": {
+				Func: func(s rbxmk.State, v types.Value) int {
+					selector := string(s.Pull(2, rtypes.T_String).(types.String))
+					sel, err := rtypes.ParseSelector(selector)
+					if err != nil {
+						return s.ArgError(2, err.Error())
+					}
+					if inst := sel.Query(s.Global, v.(*rtypes.Instance)); inst != nil {
+						return s.Push(inst)
+					}
+					return s.Push(rtypes.Nil)
+				},
+				Dump: func() dump.Function {
+					return dump.Function{
+						Parameters: dump.Parameters{
+							{Name: "selector", Type: dt.Prim(rtypes.T_String)},
+						},
+						Returns: dump.Parameters{
+							{Type: dt.Optional(dt.Prim(rtypes.T_Instance))},
+						},
+						CanError:    true,
+						Summary:     "Types/Instance:Methods/Query/Summary",
+						Description: "Types/Instance:Methods/Query/Description",
+					}
+				},
+			},
+			"QueryDescendants": {
+				Func: func(s rbxmk.State, v types.Value) int {
+					selector := string(s.Pull(2, rtypes.T_String).(types.String))
+					sel, err := rtypes.ParseSelector(selector)
+					if err != nil {
+						return s.ArgError(2, err.Error())
+					}
+					return s.Push(rtypes.Objects(sel.QueryAll(s.Global, v.(*rtypes.Instance))))
+				},
+				Dump: func() dump.Function {
+					return dump.Function{
+						Parameters: dump.Parameters{
+							{Name: "selector", Type: dt.Prim(rtypes.T_String)},
+						},
+						Returns: dump.Parameters{
+							{Type: dt.Prim(rtypes.T_Objects)},
+						},
+						CanError:    true,
+						Summary:     "Types/Instance:Methods/QueryDescendants/Summary",
+						Description: "Types/Instance:Methods/QueryDescendants/Description",
+					}
+				},
+			},
+			"RemoveTag": {
+				Func: func(s rbxmk.State, v types.Value) int {
+					inst := v.(*rtypes.Instance)
+					tag := string(s.Pull(2, rtypes.T_String).(types.String))
+					tags := getTags(s, inst)
+					for i, t := range tags {
+						if t == tag {
+							setTags(s, inst, append(tags[:i], tags[i+1:]...))
+							break
+						}
+					}
+					return 0
+				},
+				Dump: func() dump.Function {
+					return dump.Function{
+						Parameters: dump.Parameters{
+							{Name: "tag", Type: dt.Prim(rtypes.T_String)},
+						},
+						Summary:     "Types/Instance:Methods/RemoveTag/Summary",
+						Description: "Types/Instance:Methods/RemoveTag/Description",
+					}
+				},
+			},
 			"SetAttribute": {
 				Func: func(s rbxmk.State, v types.Value) int {
 					inst := v.(*rtypes.Instance)
@@ -1284,14 +1950,19 @@ func Instance() rbxmk.Reflector {
 		Types: []func() rbxmk.Reflector{
 			AttrConfig,
 			Bool,
+			CFrame,
 			Dictionary,
+			InstanceActions,
+			InstanceConflicts,
 			Nil,
 			Objects,
 			Optional,
 			Desc,
 			String,
 			Symbol,
+			TagConfig,
 			Variant,
+			Vector3,
 		},
 	}
 }
@@ -0,0 +1,210 @@
+package reflect
+
+import (
+	lua "github.com/anaminus/gopher-lua"
+	"github.com/anaminus/rbxmk"
+	"github.com/anaminus/rbxmk/dump"
+	"github.com/anaminus/rbxmk/dump/dt"
+	"github.com/anaminus/rbxmk/rtypes"
+	"github.com/robloxapi/types"
+)
+
+// pushPath pushes a path of an InstanceAction as an array of strings.
+func pushPath(s rbxmk.State, path []string) int {
+	a := make(rtypes.Array, len(path))
+	for i, name := range path {
+		a[i] = types.String(name)
+	}
+	return s.Push(a)
+}
+
+// pushPropValue pushes a property value of an InstanceAction, or nil.
+func pushPropValue(s rbxmk.State, v types.PropValue) int {
+	if v == nil {
+		return s.Push(rtypes.Nil)
+	}
+	return s.Push(v)
+}
+
+func init() { register(InstanceAction) }
+func InstanceAction() rbxmk.Reflector {
+	return rbxmk.Reflector{
+		Name:     rtypes.T_InstanceAction,
+		PushTo:   rbxmk.PushPtrTypeTo(rtypes.T_InstanceAction),
+		PullFrom: rbxmk.PullTypeFrom(rtypes.T_InstanceAction),
+		SetTo: func(p interface{}, v types.Value) error {
+			switch p := p.(type) {
+			case **rtypes.InstanceAction:
+				*p = v.(*rtypes.InstanceAction)
+			default:
+				return setPtrErr(p, v)
+			}
+			return nil
+		},
+		Metatable: rbxmk.Metatable{
+			"__tostring": func(s rbxmk.State) int {
+				v := s.Pull(1, rtypes.T_InstanceAction).(*rtypes.InstanceAction)
+				s.L.Push(lua.LString(v.String()))
+				return 1
+			},
+		},
+		Properties: rbxmk.Properties{
+			"Type": {
+				Get: func(s rbxmk.State, v types.Value) int {
+					action := v.(*rtypes.InstanceAction)
+					enum := s.MustEnum("InstanceActionType")
+					value := int(action.Action)
+					item := enum.Value(value)
+					if item == nil {
+						s.RaiseError("invalid value %d for %s", value, enum.Name())
+					}
+					return s.Push(item)
+				},
+				Dump: func() dump.Property {
+					return dump.Property{
+						ValueType:   dt.Prim("Enum.InstanceActionType"),
+						ReadOnly:    true,
+						Summary:     "Types/InstanceAction:Properties/Type/Summary",
+						Description: "Types/InstanceAction:Properties/Type/Description",
+					}
+				},
+			},
+			"Path": {
+				Get: func(s rbxmk.State, v types.Value) int {
+					return pushPath(s, v.(*rtypes.InstanceAction).Path)
+				},
+				Dump: func() dump.Property {
+					return dump.Property{
+						ValueType:   dt.Array(dt.Prim(rtypes.T_String)),
+						ReadOnly:    true,
+						Summary:     "Types/InstanceAction:Properties/Path/Summary",
+						Description: "Types/InstanceAction:Properties/Path/Description",
+					}
+				},
+			},
+			"Reference": {
+				Get: func(s rbxmk.State, v types.Value) int {
+					return s.Push(types.String(v.(*rtypes.InstanceAction).Reference))
+				},
+				Dump: func() dump.Property {
+					return dump.Property{
+						ValueType:   dt.Prim(rtypes.T_String),
+						ReadOnly:    true,
+						Summary:     "Types/InstanceAction:Properties/Reference/Summary",
+						Description: "Types/InstanceAction:Properties/Reference/Description",
+					}
+				},
+			},
+			"Property": {
+				Get: func(s rbxmk.State, v types.Value) int {
+					return s.Push(types.String(v.(*rtypes.InstanceAction).Property))
+				},
+				Dump: func() dump.Property {
+					return dump.Property{
+						ValueType:   dt.Prim(rtypes.T_String),
+						ReadOnly:    true,
+						Summary:     "Types/InstanceAction:Properties/Property/Summary",
+						Description: "Types/InstanceAction:Properties/Property/Description",
+					}
+				},
+			},
+			"Prev": {
+				Get: func(s rbxmk.State, v types.Value) int {
+					return pushPropValue(s, v.(*rtypes.InstanceAction).Prev)
+				},
+				Dump: func() dump.Property {
+					return dump.Property{
+						ValueType:   dt.Optional(dt.Prim(rtypes.T_Any)),
+						ReadOnly:    true,
+						Summary:     "Types/InstanceAction:Properties/Prev/Summary",
+						Description: "Types/InstanceAction:Properties/Prev/Description",
+					}
+				},
+			},
+			"Next": {
+				Get: func(s rbxmk.State, v types.Value) int {
+					return pushPropValue(s, v.(*rtypes.InstanceAction).Next)
+				},
+				Dump: func() dump.Property {
+					return dump.Property{
+						ValueType:   dt.Optional(dt.Prim(rtypes.T_Any)),
+						ReadOnly:    true,
+						Summary:     "Types/InstanceAction:Properties/Next/Summary",
+						Description: "Types/InstanceAction:Properties/Next/Description",
+					}
+				},
+			},
+			"Instance": {
+				Get: func(s rbxmk.State, v types.Value) int {
+					if inst := v.(*rtypes.InstanceAction).Instance; inst != nil {
+						return s.Push(inst)
+					}
+					return s.Push(rtypes.Nil)
+				},
+				Dump: func() dump.Property {
+					return dump.Property{
+						ValueType:   dt.Optional(dt.Prim(rtypes.T_Instance)),
+						ReadOnly:    true,
+						Summary:     "Types/InstanceAction:Properties/Instance/Summary",
+						Description: "Types/InstanceAction:Properties/Instance/Description",
+					}
+				},
+			},
+			"Parent": {
+				Get: func(s rbxmk.State, v types.Value) int {
+					return pushPath(s, v.(*rtypes.InstanceAction).Parent)
+				},
+				Dump: func() dump.Property {
+					return dump.Property{
+						ValueType:   dt.Array(dt.Prim(rtypes.T_String)),
+						ReadOnly:    true,
+						Summary:     "Types/InstanceAction:Properties/Parent/Summary",
+						Description: "Types/InstanceAction:Properties/Parent/Description",
+					}
+				},
+			},
+			"ParentReference": {
+				Get: func(s rbxmk.State, v types.Value) int {
+					return s.Push(types.String(v.(*rtypes.InstanceAction).ParentReference))
+				},
+				Dump: func() dump.Property {
+					return dump.Property{
+						ValueType:   dt.Prim(rtypes.T_String),
+						ReadOnly:    true,
+						Summary:     "Types/InstanceAction:Properties/ParentReference/Summary",
+						Description: "Types/InstanceAction:Properties/ParentReference/Description",
+					}
+				},
+			},
+		},
+		Dump: func() dump.TypeDef {
+			return dump.TypeDef{
+				Category:    "rbxmk",
+				Summary:     "Types/InstanceAction:Summary",
+				Description: "Types/InstanceAction:Description",
+			}
+		},
+		Enums: rbxmk.Enums{
+			"InstanceActionType": func() dump.Enum {
+				return dump.Enum{
+					Items: dump.EnumItems{
+						rtypes.InstanceRemove.String(): {Value: int(rtypes.InstanceRemove)},
+						rtypes.InstanceChange.String(): {Value: int(rtypes.InstanceChange)},
+						rtypes.InstanceAdd.String():    {Value: int(rtypes.InstanceAdd)},
+						rtypes.InstanceMove.String():   {Value: int(rtypes.InstanceMove)},
+					},
+					Summary:     "Types/InstanceAction:Enums/InstanceActionType/Summary",
+					Description: "Types/InstanceAction:Enums/InstanceActionType/Description",
+				}
+			},
+		},
+		Types: []func() rbxmk.Reflector{
+			Array,
+			Enum,
+			Instance,
+			Nil,
+			String,
+			Variant,
+		},
+	}
+}
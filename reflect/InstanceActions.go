@@ -0,0 +1,70 @@
+package reflect
+
+import (
+	lua "github.com/anaminus/gopher-lua"
+	"github.com/anaminus/rbxmk"
+	"github.com/anaminus/rbxmk/dump"
+	"github.com/anaminus/rbxmk/dump/dt"
+	"github.com/anaminus/rbxmk/rtypes"
+	"github.com/robloxapi/types"
+)
+
+func init() { register(InstanceActions) }
+func InstanceActions() rbxmk.Reflector {
+	return rbxmk.Reflector{
+		Name: rtypes.T_InstanceActions,
+		PushTo: func(c rbxmk.Context, v types.Value) (lv lua.LValue, err error) {
+			actions, ok := v.(rtypes.InstanceActions)
+			if !ok {
+				return nil, rbxmk.TypeError{Want: rtypes.T_InstanceActions, Got: v.Type()}
+			}
+			actionRfl := c.MustReflector(rtypes.T_InstanceAction)
+			table := c.CreateTable(len(actions), 0)
+			for i, v := range actions {
+				lv, err := actionRfl.PushTo(c, v)
+				if err != nil {
+					return nil, err
+				}
+				table.RawSetInt(i+1, lv)
+			}
+			return table, nil
+		},
+		PullFrom: func(c rbxmk.Context, lv lua.LValue) (v types.Value, err error) {
+			table, ok := lv.(*lua.LTable)
+			if !ok {
+				return nil, rbxmk.TypeError{Want: rtypes.T_Table, Got: lv.Type().String()}
+			}
+			actionRfl := c.MustReflector(rtypes.T_InstanceAction)
+			n := table.Len()
+			actions := make(rtypes.InstanceActions, n)
+			for i := 1; i <= n; i++ {
+				v, err := actionRfl.PullFrom(c, table.RawGetInt(i))
+				if err != nil {
+					return nil, err
+				}
+				actions[i-1] = v.(*rtypes.InstanceAction)
+			}
+			return actions, nil
+		},
+		SetTo: func(p interface{}, v types.Value) error {
+			switch p := p.(type) {
+			case *rtypes.InstanceActions:
+				*p = v.(rtypes.InstanceActions)
+			default:
+				return setPtrErr(p, v)
+			}
+			return nil
+		},
+		Dump: func() dump.TypeDef {
+			return dump.TypeDef{
+				Category:    "rbxmk",
+				Underlying:  dt.P(dt.Array(dt.Prim(rtypes.T_InstanceAction))),
+				Summary:     "Types/InstanceActions:Summary",
+				Description: "Types/InstanceActions:Description",
+			}
+		},
+		Types: []func() rbxmk.Reflector{
+			InstanceAction,
+		},
+	}
+}
@@ -0,0 +1,88 @@
+package reflect
+
+import (
+	lua "github.com/anaminus/gopher-lua"
+	"github.com/anaminus/rbxmk"
+	"github.com/anaminus/rbxmk/dump"
+	"github.com/anaminus/rbxmk/dump/dt"
+	"github.com/anaminus/rbxmk/rtypes"
+	"github.com/robloxapi/types"
+)
+
+func init() { register(InstanceConflict) }
+func InstanceConflict() rbxmk.Reflector {
+	return rbxmk.Reflector{
+		Name:     rtypes.T_InstanceConflict,
+		PushTo:   rbxmk.PushPtrTypeTo(rtypes.T_InstanceConflict),
+		PullFrom: rbxmk.PullTypeFrom(rtypes.T_InstanceConflict),
+		SetTo: func(p interface{}, v types.Value) error {
+			switch p := p.(type) {
+			case **rtypes.InstanceConflict:
+				*p = v.(*rtypes.InstanceConflict)
+			default:
+				return setPtrErr(p, v)
+			}
+			return nil
+		},
+		Metatable: rbxmk.Metatable{
+			"__tostring": func(s rbxmk.State) int {
+				v := s.Pull(1, rtypes.T_InstanceConflict).(*rtypes.InstanceConflict)
+				s.L.Push(lua.LString(v.String()))
+				return 1
+			},
+		},
+		Properties: rbxmk.Properties{
+			"Path": {
+				Get: func(s rbxmk.State, v types.Value) int {
+					return pushPath(s, v.(*rtypes.InstanceConflict).Path)
+				},
+				Dump: func() dump.Property {
+					return dump.Property{
+						ValueType:   dt.Array(dt.Prim(rtypes.T_String)),
+						ReadOnly:    true,
+						Summary:     "Types/InstanceConflict:Properties/Path/Summary",
+						Description: "Types/InstanceConflict:Properties/Path/Description",
+					}
+				},
+			},
+			"Ours": {
+				Get: func(s rbxmk.State, v types.Value) int {
+					return s.Push(v.(*rtypes.InstanceConflict).Ours)
+				},
+				Dump: func() dump.Property {
+					return dump.Property{
+						ValueType:   dt.Prim(rtypes.T_InstanceAction),
+						ReadOnly:    true,
+						Summary:     "Types/InstanceConflict:Properties/Ours/Summary",
+						Description: "Types/InstanceConflict:Properties/Ours/Description",
+					}
+				},
+			},
+			"Theirs": {
+				Get: func(s rbxmk.State, v types.Value) int {
+					return s.Push(v.(*rtypes.InstanceConflict).Theirs)
+				},
+				Dump: func() dump.Property {
+					return dump.Property{
+						ValueType:   dt.Prim(rtypes.T_InstanceAction),
+						ReadOnly:    true,
+						Summary:     "Types/InstanceConflict:Properties/Theirs/Summary",
+						Description: "Types/InstanceConflict:Properties/Theirs/Description",
+					}
+				},
+			},
+		},
+		Dump: func() dump.TypeDef {
+			return dump.TypeDef{
+				Category:    "rbxmk",
+				Summary:     "Types/InstanceConflict:Summary",
+				Description: "Types/InstanceConflict:Description",
+			}
+		},
+		Types: []func() rbxmk.Reflector{
+			Array,
+			InstanceAction,
+			String,
+		},
+	}
+}
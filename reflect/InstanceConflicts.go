@@ -0,0 +1,70 @@
+package reflect
+
+import (
+	lua "github.com/anaminus/gopher-lua"
+	"github.com/anaminus/rbxmk"
+	"github.com/anaminus/rbxmk/dump"
+	"github.com/anaminus/rbxmk/dump/dt"
+	"github.com/anaminus/rbxmk/rtypes"
+	"github.com/robloxapi/types"
+)
+
+func init() { register(InstanceConflicts) }
+func InstanceConflicts() rbxmk.Reflector {
+	return rbxmk.Reflector{
+		Name: rtypes.T_InstanceConflicts,
+		PushTo: func(c rbxmk.Context, v types.Value) (lv lua.LValue, err error) {
+			conflicts, ok := v.(rtypes.InstanceConflicts)
+			if !ok {
+				return nil, rbxmk.TypeError{Want: rtypes.T_InstanceConflicts, Got: v.Type()}
+			}
+			conflictRfl := c.MustReflector(rtypes.T_InstanceConflict)
+			table := c.CreateTable(len(conflicts), 0)
+			for i, v := range conflicts {
+				lv, err := conflictRfl.PushTo(c, v)
+				if err != nil {
+					return nil, err
+				}
+				table.RawSetInt(i+1, lv)
+			}
+			return table, nil
+		},
+		PullFrom: func(c rbxmk.Context, lv lua.LValue) (v types.Value, err error) {
+			table, ok := lv.(*lua.LTable)
+			if !ok {
+				return nil, rbxmk.TypeError{Want: rtypes.T_Table, Got: lv.Type().String()}
+			}
+			conflictRfl := c.MustReflector(rtypes.T_InstanceConflict)
+			n := table.Len()
+			conflicts := make(rtypes.InstanceConflicts, n)
+			for i := 1; i <= n; i++ {
+				v, err := conflictRfl.PullFrom(c, table.RawGetInt(i))
+				if err != nil {
+					return nil, err
+				}
+				conflicts[i-1] = v.(*rtypes.InstanceConflict)
+			}
+			return conflicts, nil
+		},
+		SetTo: func(p interface{}, v types.Value) error {
+			switch p := p.(type) {
+			case *rtypes.InstanceConflicts:
+				*p = v.(rtypes.InstanceConflicts)
+			default:
+				return setPtrErr(p, v)
+			}
+			return nil
+		},
+		Dump: func() dump.TypeDef {
+			return dump.TypeDef{
+				Category:    "rbxmk",
+				Underlying:  dt.P(dt.Array(dt.Prim(rtypes.T_InstanceConflict))),
+				Summary:     "Types/InstanceConflicts:Summary",
+				Description: "Types/InstanceConflicts:Description",
+			}
+		},
+		Types: []func() rbxmk.Reflector{
+			InstanceConflict,
+		},
+	}
+}
@@ -23,7 +23,7 @@ func RbxAssetOptions() rbxmk.Reflector {
 			if options.AssetId <= 0 {
 				return nil, fmt.Errorf("field AssetId (%d) must be greater than 0", options.AssetId)
 			}
-			table := c.CreateTable(0, 4)
+			table := c.CreateTable(0, 5)
 			if err := c.PushToDictionary(table, "AssetId", types.Int64(options.AssetId)); err != nil {
 				return nil, err
 			}
@@ -36,6 +36,9 @@ func RbxAssetOptions() rbxmk.Reflector {
 			if err := c.PushToDictionary(table, "Body", options.Body); err != nil {
 				return nil, err
 			}
+			if options.Progress != nil {
+				table.RawSetString("Progress", options.Progress)
+			}
 			return table, nil
 		},
 		PullFrom: func(c rbxmk.Context, lv lua.LValue) (v types.Value, err error) {
@@ -64,6 +67,13 @@ func RbxAssetOptions() rbxmk.Reflector {
 			if err != nil {
 				return nil, err
 			}
+			switch progress := table.RawGetString("Progress").(type) {
+			case *lua.LNilType:
+			case *lua.LFunction:
+				options.Progress = progress
+			default:
+				return nil, rbxmk.TypeError{Want: "function", Got: progress.Type().String()}
+			}
 			if options.AssetId <= 0 {
 				return nil, fmt.Errorf("field AssetId (%d) must be greater than 0", options.AssetId)
 			}
@@ -86,6 +96,14 @@ func RbxAssetOptions() rbxmk.Reflector {
 					"Cookies": dt.Optional(dt.Prim(rtypes.T_Cookies)),
 					"Format":  dt.Prim(rtypes.T_FormatSelector),
 					"Body":    dt.Optional(dt.Prim(rtypes.T_Any)),
+					"Progress": dt.Optional(dt.Function(dt.KindFunction{
+						Parameters: dt.Parameters{
+							{Name: "sent", Type: dt.Prim(rtypes.T_Int64)},
+							{Name: "sendTotal", Type: dt.Optional(dt.Prim(rtypes.T_Int64))},
+							{Name: "received", Type: dt.Prim(rtypes.T_Int64)},
+							{Name: "receiveTotal", Type: dt.Optional(dt.Prim(rtypes.T_Int64))},
+						},
+					})),
 				})),
 				Summary:     "Types/RbxAssetOptions:Summary",
 				Description: "Types/RbxAssetOptions:Description",
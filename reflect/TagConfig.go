@@ -0,0 +1,79 @@
+package reflect
+
+import (
+	"github.com/anaminus/rbxmk"
+	"github.com/anaminus/rbxmk/dump"
+	"github.com/anaminus/rbxmk/dump/dt"
+	"github.com/anaminus/rbxmk/rtypes"
+	"github.com/robloxapi/types"
+)
+
+func init() { register(TagConfig) }
+func TagConfig() rbxmk.Reflector {
+	return rbxmk.Reflector{
+		Name:     rtypes.T_TagConfig,
+		PushTo:   rbxmk.PushPtrTypeTo(rtypes.T_TagConfig),
+		PullFrom: rbxmk.PullTypeFrom(rtypes.T_TagConfig),
+		SetTo: func(p interface{}, v types.Value) error {
+			switch p := p.(type) {
+			case **rtypes.TagConfig:
+				*p = v.(*rtypes.TagConfig)
+			default:
+				return setPtrErr(p, v)
+			}
+			return nil
+		},
+		Properties: rbxmk.Properties{
+			"Property": {
+				Get: func(s rbxmk.State, v types.Value) int {
+					tagConfig := v.(*rtypes.TagConfig)
+					return s.Push(types.String(tagConfig.Property))
+				},
+				Set: func(s rbxmk.State, v types.Value) {
+					tagConfig := v.(*rtypes.TagConfig)
+					tagConfig.Property = string(s.Pull(3, rtypes.T_String).(types.String))
+				},
+				Dump: func() dump.Property {
+					return dump.Property{
+						ValueType:   dt.Prim(rtypes.T_String),
+						Summary:     "Types/TagConfig:Properties/Property/Summary",
+						Description: "Types/TagConfig:Properties/Property/Description",
+					}
+				},
+			},
+		},
+		Constructors: rbxmk.Constructors{
+			"new": rbxmk.Constructor{
+				Func: func(s rbxmk.State) int {
+					var v rtypes.TagConfig
+					v.Property = string(s.PullOpt(1, types.String(""), rtypes.T_String).(types.String))
+					return s.Push(&v)
+				},
+				Dump: func() dump.MultiFunction {
+					return dump.MultiFunction{
+						dump.Function{
+							Parameters: dump.Parameters{
+								{Name: "property", Type: dt.Optional(dt.Prim(rtypes.T_String))},
+							},
+							Returns: dump.Parameters{
+								{Type: dt.Prim(rtypes.T_TagConfig)},
+							},
+							Summary:     "Types/TagConfig:Constructors/new/Summary",
+							Description: "Types/TagConfig:Constructors/new/Description",
+						},
+					}
+				},
+			},
+		},
+		Dump: func() dump.TypeDef {
+			return dump.TypeDef{
+				Category:    "rbxmk",
+				Summary:     "Types/TagConfig:Summary",
+				Description: "Types/TagConfig:Description",
+			}
+		},
+		Types: []func() rbxmk.Reflector{
+			String,
+		},
+	}
+}
@@ -13,6 +13,10 @@ const T_Desc = "Desc"
 type Desc struct {
 	*rbxdump.Root
 	EnumTypes *Enums
+	// Strict causes property assignments checked against the descriptor to
+	// also reject read-only and non-scriptable properties, and values that
+	// cannot be converted to the property type without loss.
+	Strict bool
 }
 
 // Type returns a string identifying the type of the value.
@@ -26,7 +30,7 @@ func (d *Desc) String() string {
 }
 
 func (d *Desc) Copy() *Desc {
-	c := &Desc{Root: d.Root.Copy()}
+	c := &Desc{Root: d.Root.Copy(), Strict: d.Strict}
 	if d.EnumTypes != nil {
 		c.GenerateEnumTypes()
 	}
@@ -4,4 +4,5 @@ package rtypes
 type Global struct {
 	Desc       *Desc
 	AttrConfig *AttrConfig
+	TagConfig  *TagConfig
 }
@@ -2,7 +2,9 @@ package rtypes
 
 import (
 	"net/http"
+	"time"
 
+	lua "github.com/anaminus/gopher-lua"
 	"github.com/robloxapi/types"
 )
 
@@ -19,6 +21,22 @@ type HttpOptions struct {
 	Headers        HttpHeaders
 	Cookies        Cookies
 	Body           types.Value
+
+	// Retries is the maximum number of times the request is retried after
+	// receiving a response that indicates a rate limit or server error. If
+	// nil, the default of the client is used.
+	Retries *int
+	// RetryDelay is the duration waited before the first retry. If nil, the
+	// default of the client is used.
+	RetryDelay *time.Duration
+
+	// Timeout is the maximum duration of the request, including reading the
+	// response body. If nil, the default of the client is used. If zero, the
+	// duration is unlimited.
+	Timeout *time.Duration
+	// Progress is a function called while the request is being resolved, which
+	// receives the number of bytes transferred.
+	Progress *lua.LFunction
 }
 
 // Type returns a string identifying the type of the value.
@@ -95,6 +113,9 @@ type RbxAssetOptions struct {
 	Cookies Cookies
 	Format  FormatSelector
 	Body    types.Value
+	// Progress is a function called while the asset is being transferred,
+	// which receives the number of bytes transferred.
+	Progress *lua.LFunction
 }
 
 // Type returns a string identifying the type of the value.
@@ -3,6 +3,7 @@ package rtypes
 import (
 	"errors"
 	"sort"
+	"sync"
 
 	"github.com/robloxapi/types"
 )
@@ -17,12 +18,15 @@ type Instance struct {
 	IsService bool
 
 	properties     map[string]types.PropValue
+	lazy           *lazyProperties
 	children       []*Instance
 	parent         *Instance
 	desc           *Desc
 	descBlocked    bool
 	attrcfg        *AttrConfig
 	attrcfgBlocked bool
+	tagcfg         *TagConfig
+	tagcfgBlocked  bool
 
 	// Contains model metadata. Non-nil also signals that Instance is a
 	// DataModel.
@@ -60,8 +64,8 @@ func (*Instance) Type() string {
 // String returns a string representation of the instance by returning the Name,
 // or the ClassName if Name isn't defined.
 func (inst *Instance) String() string {
-	if v, ok := (inst.properties["Name"]).(types.Stringlike); ok {
-		return v.Stringlike()
+	if v, ok := inst.nameValue(); ok {
+		return v
 	}
 	return inst.ClassName
 }
@@ -82,6 +86,69 @@ func (inst *Instance) Metadata() map[string]string {
 	return inst.metadata
 }
 
+// PropertyLoader loads the properties of an instance on demand.
+type PropertyLoader interface {
+	// LoadProperties returns the properties of inst.
+	LoadProperties(inst *Instance) map[string]types.PropValue
+}
+
+// lazyProperties holds the loader of an instance whose properties have not yet
+// been loaded.
+type lazyProperties struct {
+	mu     sync.Mutex
+	loader PropertyLoader
+}
+
+// SetPropertyLoader sets a loader that provides the properties of the
+// instance. The loader is called once, the first time the properties of the
+// instance are accessed, and is released afterwards. Properties already set on
+// the instance are retained.
+func (inst *Instance) SetPropertyLoader(loader PropertyLoader) {
+	if loader == nil {
+		inst.lazy = nil
+		return
+	}
+	inst.lazy = &lazyProperties{loader: loader}
+}
+
+// props returns the properties of the instance, loading them if needed.
+// Loading is synchronized so that concurrent reads of an instance remain safe.
+func (inst *Instance) props() map[string]types.PropValue {
+	if lazy := inst.lazy; lazy != nil {
+		lazy.mu.Lock()
+		if loader := lazy.loader; loader != nil {
+			lazy.loader = nil
+			for name, value := range loader.LoadProperties(inst) {
+				if _, ok := inst.properties[name]; !ok && value != nil {
+					inst.properties[name] = value
+				}
+			}
+		}
+		lazy.mu.Unlock()
+	}
+	return inst.properties
+}
+
+// nameValue returns the Name property of the instance. The properties of the
+// instance are not loaded if the Name property is already set.
+func (inst *Instance) nameValue() (name string, ok bool) {
+	var v types.PropValue
+	if lazy := inst.lazy; lazy != nil {
+		lazy.mu.Lock()
+		v, ok = inst.properties["Name"]
+		lazy.mu.Unlock()
+	} else {
+		v, ok = inst.properties["Name"]
+	}
+	if !ok {
+		v = inst.props()["Name"]
+	}
+	if v, ok := v.(types.Stringlike); ok {
+		return v.Stringlike(), true
+	}
+	return "", false
+}
+
 // propRef holds the value of an instance property to be resolved later.
 type propRef struct {
 	Instance *Instance
@@ -94,7 +161,8 @@ func (inst *Instance) clone(refs map[*Instance]*Instance, propRefs *[]propRef) *
 	c := *inst
 	clone := &c
 	clone.children = make([]*Instance, len(inst.children))
-	clone.properties = make(map[string]types.PropValue, len(inst.properties))
+	clone.properties = make(map[string]types.PropValue, len(inst.props()))
+	clone.lazy = nil
 	clone.parent = nil
 	refs[inst] = clone
 	for name, v := range inst.properties {
@@ -441,7 +509,7 @@ func (inst *Instance) Get(property string) (value types.PropValue) {
 	case "Parent":
 		return inst.Parent()
 	}
-	return inst.properties[property]
+	return inst.props()[property]
 }
 
 // Set sets the value of a property in the instance. If value is nil, then the
@@ -474,9 +542,9 @@ func (inst *Instance) Set(property string, value types.PropValue) {
 		panic("value of Parent must be *Instance or nil")
 	}
 	if value == nil {
-		delete(inst.properties, property)
+		delete(inst.props(), property)
 	} else {
-		inst.properties[property] = value
+		inst.props()[property] = value
 	}
 }
 
@@ -484,7 +552,7 @@ func (inst *Instance) Set(property string, value types.PropValue) {
 //
 // ClassName and Parent are not included.
 func (inst *Instance) Properties() map[string]types.PropValue {
-	props := make(map[string]types.PropValue, len(inst.properties))
+	props := make(map[string]types.PropValue, len(inst.props()))
 	for name, value := range inst.properties {
 		props[name] = value
 	}
@@ -493,7 +561,7 @@ func (inst *Instance) Properties() map[string]types.PropValue {
 
 // PropertyNames returns a list of names of properties set on the instance.
 func (inst *Instance) PropertyNames() []string {
-	props := make([]string, 0, len(inst.properties))
+	props := make([]string, 0, len(inst.props()))
 	for name := range inst.properties {
 		props = append(props, name)
 	}
@@ -509,6 +577,7 @@ func (inst *Instance) PropertyNames() []string {
 // removed. If false, such properties are retained. If props is nil or empty,
 // and replace is true, then all properties are removed from the instance.
 func (inst *Instance) SetProperties(props map[string]types.PropValue, replace bool) {
+	inst.props()
 	if replace {
 		for name := range inst.properties {
 			if props[name] == nil {
@@ -528,15 +597,13 @@ func (inst *Instance) SetProperties(props map[string]types.PropValue, replace bo
 // Name returns the Name property of the instance, or an empty string if it is
 // invalid or not defined.
 func (inst *Instance) Name() string {
-	if v, ok := inst.properties["Name"].(types.Stringlike); ok {
-		return v.Stringlike()
-	}
-	return ""
+	v, _ := inst.nameValue()
+	return v
 }
 
 // SetName sets the Name property of the instance.
 func (inst *Instance) SetName(name string) {
-	inst.properties["Name"] = types.String(name)
+	inst.props()["Name"] = types.String(name)
 }
 
 // GetFullName returns the "full" name of the instance, which is the combined
@@ -731,6 +798,43 @@ func (inst *Instance) SetAttrConfig(attrcfg *AttrConfig, blocked bool) {
 	inst.attrcfgBlocked = false
 }
 
+// TagConfig returns the nearest TagConfig for the instance. If the TagConfig of
+// current instance is nil, then the parent is searched, and so on, until a
+// non-nil or blocked TagConfig is found. Nil is returned if no TagConfigs are
+// found.
+func (inst *Instance) TagConfig() *TagConfig {
+	parent := inst
+	for parent != nil {
+		if parent.tagcfgBlocked {
+			return nil
+		}
+		if parent.tagcfg != nil {
+			return parent.tagcfg
+		}
+		parent = parent.parent
+	}
+	return nil
+}
+
+// RawTagConfig returns the TagConfig for the instance, and whether it is
+// blocked.
+func (inst *Instance) RawTagConfig() (tagcfg *TagConfig, blocked bool) {
+	return inst.tagcfg, inst.tagcfgBlocked
+}
+
+// SetTagConfig sets tagcfg as the TagConfig for the instance. If blocked is
+// true, then the TagConfig is set to nil, and TagConfig will return nil if it
+// reaches the instance.
+func (inst *Instance) SetTagConfig(tagcfg *TagConfig, blocked bool) {
+	if blocked {
+		inst.tagcfg = nil
+		inst.tagcfgBlocked = true
+		return
+	}
+	inst.tagcfg = tagcfg
+	inst.tagcfgBlocked = false
+}
+
 // ForEachChild iterates over each child of the instance.
 //
 // If cb returns an error, iteration stops, and the error is returned.
@@ -776,7 +880,7 @@ func (inst *Instance) ForEachDescendant(cb func(desc *Instance) error) error {
 //
 // If cb returns an error, iteration stops, and the error is returned.
 func (inst *Instance) ForEachProperty(cb func(name string, value types.PropValue) error) error {
-	for name, value := range inst.properties {
+	for name, value := range inst.props() {
 		if err := cb(name, value); err != nil {
 			return err
 		}
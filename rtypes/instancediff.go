@@ -0,0 +1,615 @@
+package rtypes
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/robloxapi/types"
+)
+
+const T_InstanceActions = "InstanceActions"
+
+// InstanceActions is a list of InstanceAction values that implements
+// types.Value.
+type InstanceActions []*InstanceAction
+
+// Type returns a string identifying the type of the value.
+func (InstanceActions) Type() string {
+	return T_InstanceActions
+}
+
+// InstanceActionType indicates the kind of change made by an InstanceAction.
+type InstanceActionType int
+
+const (
+	// InstanceRemove removes the target instance from its parent.
+	InstanceRemove InstanceActionType = -1
+	// InstanceChange sets a property of the target instance.
+	InstanceChange InstanceActionType = 0
+	// InstanceAdd adds a new instance to a parent.
+	InstanceAdd InstanceActionType = 1
+	// InstanceMove moves the target instance to a new parent.
+	InstanceMove InstanceActionType = 2
+)
+
+// String returns a string representation of the action type.
+func (t InstanceActionType) String() string {
+	switch t {
+	case InstanceRemove:
+		return "Remove"
+	case InstanceChange:
+		return "Change"
+	case InstanceAdd:
+		return "Add"
+	case InstanceMove:
+		return "Move"
+	}
+	return "Invalid"
+}
+
+const T_InstanceAction = "InstanceAction"
+
+// InstanceAction describes a single difference between two trees of
+// instances.
+//
+// An instance within a tree is located by its Reference, or, if the Reference
+// is empty or not found, by its path. A path is the list of names of each
+// ancestor, from the root of the tree to the instance, excluding the root
+// itself. An empty path refers to the root.
+//
+// Because siblings may have the same name, a path is accompanied by an index.
+// Each element of an index is the position of the corresponding instance of
+// the path among its siblings that have the same name. A missing element is
+// treated as 0.
+type InstanceAction struct {
+	Action InstanceActionType
+
+	// Path, Index, and Reference locate the target of a Remove, Change, or
+	// Move action, within the previous tree.
+	Path      []string
+	Index     []int
+	Reference string
+
+	// Property is the name of the property set by a Change action. Prev is
+	// the previous value, and Next is the new value. A nil value indicates
+	// that the property is not set.
+	Property string
+	Prev     types.PropValue
+	Next     types.PropValue
+
+	// Instance is the instance added by an Add action.
+	Instance *Instance
+
+	// Parent, ParentIndex, and ParentReference locate the parent of an Add or
+	// Move action. When instances are matched by path, the parent is always an
+	// instance of the previous tree, and is located within the previous tree.
+	// Otherwise, it is located within the next tree.
+	Parent          []string
+	ParentIndex     []int
+	ParentReference string
+}
+
+// Type returns a string identifying the type of the value.
+func (*InstanceAction) Type() string {
+	return T_InstanceAction
+}
+
+// Target returns the instance targeted by a Remove, Change, or Move action,
+// located by Path and Index within root, the root of the previous tree.
+// Returns nil if the action has no target, or if the target was not found.
+func (a *InstanceAction) Target(root *Instance) *Instance {
+	switch a.Action {
+	case InstanceRemove, InstanceChange, InstanceMove:
+		return descendPath(root, a.Path, a.Index)
+	}
+	return nil
+}
+
+// formatPath returns a string representation of a path.
+func formatPath(path []string) string {
+	if len(path) == 0 {
+		return "<root>"
+	}
+	return strings.Join(path, ".")
+}
+
+// formatPropValue returns a string representation of a property value.
+func formatPropValue(v types.PropValue) string {
+	switch v := v.(type) {
+	case nil:
+		return "nil"
+	case *Instance:
+		return v.GetFullName()
+	case types.Stringlike:
+		return fmt.Sprintf("%q", v.Stringlike())
+	}
+	return fmt.Sprint(v)
+}
+
+// String returns a string representation of the value.
+func (a *InstanceAction) String() string {
+	switch a.Action {
+	case InstanceRemove:
+		return fmt.Sprintf("Remove %s", formatPath(a.Path))
+	case InstanceChange:
+		return fmt.Sprintf("Change %s.%s: %s -> %s", formatPath(a.Path), a.Property, formatPropValue(a.Prev), formatPropValue(a.Next))
+	case InstanceAdd:
+		return fmt.Sprintf("Add %s %s to %s", a.Instance.ClassName, a.Instance.Name(), formatPath(a.Parent))
+	case InstanceMove:
+		return fmt.Sprintf("Move %s to %s", formatPath(a.Path), formatPath(a.Parent))
+	}
+	return "Invalid"
+}
+
+// pathOf returns the path and index of inst relative to root. Returns false if
+// inst is not root or a descendant of root.
+func pathOf(root, inst *Instance) (path []string, index []int, ok bool) {
+	for ; inst != nil; inst = inst.parent {
+		if inst == root {
+			// Reverse.
+			for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+				path[i], path[j] = path[j], path[i]
+				index[i], index[j] = index[j], index[i]
+			}
+			return path, index, true
+		}
+		path = append(path, inst.Name())
+		index = append(index, siblingIndex(inst))
+	}
+	return nil, nil, false
+}
+
+// siblingIndex returns the position of inst among the children of its parent
+// that have the same name.
+func siblingIndex(inst *Instance) int {
+	if inst.parent == nil {
+		return 0
+	}
+	name := inst.Name()
+	n := 0
+	for _, sibling := range inst.parent.children {
+		if sibling == inst {
+			break
+		}
+		if sibling.Name() == name {
+			n++
+		}
+	}
+	return n
+}
+
+// descendPath returns the descendant of root located by path and index, or nil
+// if no such descendant exists.
+func descendPath(root *Instance, path []string, index []int) *Instance {
+	inst := root
+	for i, name := range path {
+		n := 0
+		if i < len(index) {
+			n = index[i]
+		}
+		var next *Instance
+		for _, child := range inst.children {
+			if child.Name() == name {
+				if n == 0 {
+					next = child
+					break
+				}
+				n--
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		inst = next
+	}
+	return inst
+}
+
+// InstanceDiffMode determines how Instance.Diff matches instances between two
+// trees.
+type InstanceDiffMode int
+
+const (
+	// DiffByPath matches instances that have the same name and class under
+	// matching parents. Siblings that have the same name and class are matched
+	// in order.
+	DiffByPath InstanceDiffMode = iota
+	// DiffByReference matches instances that have the same non-empty
+	// Reference and class, anywhere in the tree.
+	DiffByReference
+)
+
+// instanceDiffer holds the state of a diff between two trees.
+type instanceDiffer struct {
+	mode       InstanceDiffMode
+	prev, next *Instance
+	// Maps instances in prev to matching instances in next, and vice versa.
+	toNext map[*Instance]*Instance
+	toPrev map[*Instance]*Instance
+
+	removes []*InstanceAction
+	adds    []*InstanceAction
+	moves   []*InstanceAction
+	changes []*InstanceAction
+}
+
+// Diff returns a list of actions that transform the tree of inst into the
+// tree of next. The root instances are always compared with each other.
+// Instances are matched according to mode.
+//
+// Actions are ordered so that applying them with Patch is well-defined: adds,
+// then moves, then changes, then removes.
+func (inst *Instance) Diff(next *Instance, mode InstanceDiffMode) InstanceActions {
+	d := instanceDiffer{
+		mode:   mode,
+		prev:   inst,
+		next:   next,
+		toNext: map[*Instance]*Instance{},
+		toPrev: map[*Instance]*Instance{},
+	}
+	d.match(inst, next)
+	switch mode {
+	case DiffByReference:
+		d.matchReferences()
+		d.diffReferences(next)
+	default:
+		d.matchPaths(inst, next)
+		d.diffPaths(inst, next)
+	}
+	actions := make(InstanceActions, 0, len(d.adds)+len(d.moves)+len(d.changes)+len(d.removes))
+	actions = append(actions, d.adds...)
+	actions = append(actions, d.moves...)
+	actions = append(actions, d.changes...)
+	actions = append(actions, d.removes...)
+	return actions
+}
+
+// match marks p and n as matching.
+func (d *instanceDiffer) match(p, n *Instance) {
+	d.toNext[p] = n
+	d.toPrev[n] = p
+}
+
+// prevPath returns the path and index of p within the previous tree.
+func (d *instanceDiffer) prevPath(p *Instance) ([]string, []int) {
+	path, index, _ := pathOf(d.prev, p)
+	return path, index
+}
+
+// nextPath returns the path and index of n within the next tree.
+func (d *instanceDiffer) nextPath(n *Instance) ([]string, []int) {
+	path, index, _ := pathOf(d.next, n)
+	return path, index
+}
+
+// ref returns the Reference of inst if instances are matched by reference.
+func (d *instanceDiffer) ref(inst *Instance) string {
+	if d.mode == DiffByReference {
+		return inst.Reference
+	}
+	return ""
+}
+
+// matchPaths matches the descendants of p and n by name and class.
+func (d *instanceDiffer) matchPaths(p, n *Instance) {
+	type key struct{ name, class string }
+	unmatched := map[key][]*Instance{}
+	for _, pc := range p.children {
+		k := key{pc.Name(), pc.ClassName}
+		unmatched[k] = append(unmatched[k], pc)
+	}
+	for _, nc := range n.children {
+		k := key{nc.Name(), nc.ClassName}
+		if list := unmatched[k]; len(list) > 0 {
+			unmatched[k] = list[1:]
+			d.match(list[0], nc)
+			d.matchPaths(list[0], nc)
+		}
+	}
+}
+
+// diffPaths compares instances p and n, which were matched by path.
+func (d *instanceDiffer) diffPaths(p, n *Instance) {
+	for _, pc := range p.children {
+		if _, ok := d.toNext[pc]; !ok {
+			path, index := d.prevPath(pc)
+			d.removes = append(d.removes, &InstanceAction{
+				Action: InstanceRemove,
+				Path:   path,
+				Index:  index,
+			})
+		}
+	}
+	d.diffProperties(p, n)
+	for _, nc := range n.children {
+		if pc, ok := d.toPrev[nc]; ok {
+			d.diffPaths(pc, nc)
+			continue
+		}
+		// Added instances are appended to the parent, so the index of the
+		// parent within the previous tree remains valid while patching.
+		parent, parentIndex := d.prevPath(p)
+		d.adds = append(d.adds, &InstanceAction{
+			Action:      InstanceAdd,
+			Instance:    nc.Clone(),
+			Parent:      parent,
+			ParentIndex: parentIndex,
+		})
+	}
+}
+
+// matchReferences matches each descendant of the next tree with the
+// descendant of the previous tree that has the same Reference and class.
+func (d *instanceDiffer) matchReferences() {
+	refs := map[string]*Instance{}
+	d.prev.ForEachDescendant(func(p *Instance) error {
+		if _, ok := refs[p.Reference]; !ok && p.Reference != "" {
+			refs[p.Reference] = p
+		}
+		return nil
+	})
+	d.next.ForEachDescendant(func(n *Instance) error {
+		if p, ok := refs[n.Reference]; ok && p.ClassName == n.ClassName {
+			if _, ok := d.toNext[p]; !ok {
+				d.match(p, n)
+			}
+		}
+		return nil
+	})
+}
+
+// diffReferences compares the instances of the next tree, starting at n, with
+// the instances they were matched with by reference.
+func (d *instanceDiffer) diffReferences(n *Instance) {
+	if p, ok := d.toPrev[n]; ok {
+		if n != d.next && d.toPrev[n.parent] != p.parent {
+			path, index := d.prevPath(p)
+			parent, parentIndex := d.nextPath(n.parent)
+			d.moves = append(d.moves, &InstanceAction{
+				Action:          InstanceMove,
+				Path:            path,
+				Index:           index,
+				Reference:       p.Reference,
+				Parent:          parent,
+				ParentIndex:     parentIndex,
+				ParentReference: n.parent.Reference,
+			})
+		}
+		d.diffProperties(p, n)
+		for _, p := range p.children {
+			d.diffRemoved(p)
+		}
+		for _, nc := range n.children {
+			d.diffReferences(nc)
+		}
+		return
+	}
+	parent, parentIndex := d.nextPath(n.parent)
+	d.adds = append(d.adds, &InstanceAction{
+		Action:          InstanceAdd,
+		Instance:        d.cloneUnmatched(n),
+		Parent:          parent,
+		ParentIndex:     parentIndex,
+		ParentReference: n.parent.Reference,
+	})
+	// Matched descendants are moved out of the added instance.
+	n.ForEachDescendant(func(nc *Instance) error {
+		if _, ok := d.toPrev[nc]; ok {
+			d.diffReferences(nc)
+			return SkipChildren
+		}
+		return nil
+	})
+}
+
+// diffRemoved adds a Remove action for p if it was not matched.
+func (d *instanceDiffer) diffRemoved(p *Instance) {
+	if _, ok := d.toNext[p]; ok {
+		return
+	}
+	path, index := d.prevPath(p)
+	d.removes = append(d.removes, &InstanceAction{
+		Action:    InstanceRemove,
+		Path:      path,
+		Index:     index,
+		Reference: p.Reference,
+	})
+}
+
+// cloneUnmatched returns a copy of n and each descendant that was not matched
+// with an instance in the previous tree.
+func (d *instanceDiffer) cloneUnmatched(n *Instance) *Instance {
+	clone := n.Clone()
+	refs := map[*Instance]*Instance{}
+	var walk func(n, c *Instance)
+	walk = func(n, c *Instance) {
+		refs[c] = n
+		for i, nc := range n.children {
+			walk(nc, c.children[i])
+		}
+	}
+	walk(n, clone)
+	var matched []*Instance
+	clone.ForEachDescendant(func(c *Instance) error {
+		if _, ok := d.toPrev[refs[c]]; ok {
+			matched = append(matched, c)
+			return SkipChildren
+		}
+		return nil
+	})
+	for _, c := range matched {
+		c.SetParent(nil)
+	}
+	return clone
+}
+
+// valuesEqual returns whether the property values p and n are equal.
+// References are equal if they refer to matching instances.
+func (d *instanceDiffer) valuesEqual(p, n types.PropValue) bool {
+	switch p := p.(type) {
+	case nil:
+		return n == nil
+	case *Instance:
+		n, ok := n.(*Instance)
+		if !ok {
+			return false
+		}
+		if m, ok := d.toNext[p]; ok {
+			return m == n
+		}
+		return p == n
+	}
+	return reflect.DeepEqual(p, n)
+}
+
+// diffProperties adds a Change action for each property that differs between
+// matching instances p and n.
+func (d *instanceDiffer) diffProperties(p, n *Instance) {
+	names := map[string]struct{}{}
+	for name := range p.props() {
+		names[name] = struct{}{}
+	}
+	for name := range n.props() {
+		names[name] = struct{}{}
+	}
+	path, index := d.prevPath(p)
+	for _, name := range sortedKeys(names) {
+		pv, nv := p.properties[name], n.properties[name]
+		if d.valuesEqual(pv, nv) {
+			continue
+		}
+		d.changes = append(d.changes, &InstanceAction{
+			Action:    InstanceChange,
+			Path:      path,
+			Index:     index,
+			Reference: d.ref(p),
+			Property:  name,
+			Prev:      pv,
+			Next:      nv,
+		})
+	}
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys(m map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// locate finds an instance within the tree of root by reference, or by path and
+// index if the reference is empty or not found.
+func locate(root *Instance, refs map[string]*Instance, ref string, path []string, index []int) *Instance {
+	if inst, ok := refs[ref]; ok && ref != "" {
+		return inst
+	}
+	return descendPath(root, path, index)
+}
+
+// references returns a map of each non-empty Reference within the tree of
+// root.
+func references(root *Instance) map[string]*Instance {
+	refs := map[string]*Instance{}
+	if root.Reference != "" {
+		refs[root.Reference] = root
+	}
+	root.ForEachDescendant(func(inst *Instance) error {
+		if _, ok := refs[inst.Reference]; !ok && inst.Reference != "" {
+			refs[inst.Reference] = inst
+		}
+		return nil
+	})
+	return refs
+}
+
+// Patch applies actions to the tree of inst. The target of each Remove,
+// Change, and Move action is located before any action is applied, while the
+// parent of each Add and Move action is located when the action is applied.
+//
+// A reference property value that refers to an instance outside of the tree
+// is resolved to the instance within the tree with the same Reference or path.
+// If no such instance exists, then the property is removed.
+//
+// Returns an error if an instance could not be located.
+func (inst *Instance) Patch(actions InstanceActions) error {
+	refs := references(inst)
+	targets := make([]*Instance, len(actions))
+	for i, action := range actions {
+		switch action.Action {
+		case InstanceRemove, InstanceChange, InstanceMove:
+			if targets[i] = locate(inst, refs, action.Reference, action.Path, action.Index); targets[i] == nil {
+				return fmt.Errorf("%s: cannot locate %s", action, formatPath(action.Path))
+			}
+			if targets[i] == inst && action.Action != InstanceChange {
+				return fmt.Errorf("%s: cannot %s root", action, strings.ToLower(action.Action.String()))
+			}
+		case InstanceAdd:
+			if action.Instance == nil {
+				return fmt.Errorf("%s action has no instance", action.Action)
+			}
+		default:
+			return fmt.Errorf("invalid action type %d", action.Action)
+		}
+	}
+	for i, action := range actions {
+		target := targets[i]
+		switch action.Action {
+		case InstanceRemove:
+			target.SetParent(nil)
+		case InstanceChange:
+			target.Set(action.Property, action.Next)
+		case InstanceAdd, InstanceMove:
+			parent := locate(inst, references(inst), action.ParentReference, action.Parent, action.ParentIndex)
+			if parent == nil {
+				return fmt.Errorf("%s: cannot locate %s", action, formatPath(action.Parent))
+			}
+			if action.Action == InstanceAdd {
+				target = action.Instance.Clone()
+			}
+			if err := target.SetParent(parent); err != nil {
+				return fmt.Errorf("%s: %w", action, err)
+			}
+		}
+	}
+	resolveReferences(inst)
+	return nil
+}
+
+// resolveReferences resolves each reference property within the tree of root
+// that refers to an instance outside of the tree.
+func resolveReferences(root *Instance) {
+	refs := references(root)
+	resolve := func(inst *Instance) error {
+		for name, value := range inst.props() {
+			v, ok := value.(*Instance)
+			if !ok || v == root || root.IsAncestorOf(v) {
+				continue
+			}
+			var r *Instance
+			if v.Reference != "" {
+				r = refs[v.Reference]
+			}
+			if r == nil {
+				top := v
+				for top.parent != nil {
+					top = top.parent
+				}
+				path, index, _ := pathOf(top, v)
+				r = descendPath(root, path, index)
+			}
+			if r == nil {
+				delete(inst.properties, name)
+				continue
+			}
+			inst.properties[name] = r
+		}
+		return nil
+	}
+	resolve(root)
+	root.ForEachDescendant(resolve)
+}
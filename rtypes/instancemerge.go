@@ -0,0 +1,284 @@
+package rtypes
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+const T_InstanceConflicts = "InstanceConflicts"
+
+// InstanceConflicts is a list of InstanceConflict values that implements
+// types.Value.
+type InstanceConflicts []*InstanceConflict
+
+// Type returns a string identifying the type of the value.
+func (InstanceConflicts) Type() string {
+	return T_InstanceConflicts
+}
+
+const T_InstanceConflict = "InstanceConflict"
+
+// InstanceConflict describes a pair of actions from each side of a three-way
+// merge that cannot both be applied. Either both actions change the same
+// property to different values, both actions move the same instance to
+// different parents, or one action removes an instance that the other action
+// modifies.
+type InstanceConflict struct {
+	// Path is the path of the instance in conflict, within the base tree.
+	Path []string
+	// Ours and Theirs are the conflicting actions, produced by diffing the base
+	// tree with each side.
+	Ours   *InstanceAction
+	Theirs *InstanceAction
+}
+
+// Type returns a string identifying the type of the value.
+func (*InstanceConflict) Type() string {
+	return T_InstanceConflict
+}
+
+// String returns a string representation of the value.
+func (c *InstanceConflict) String() string {
+	return fmt.Sprintf("Conflict %s: ours: %s; theirs: %s", formatPath(c.Path), c.Ours, c.Theirs)
+}
+
+// instanceSide holds the actions of one side of a merge, indexed by the
+// instances of the base tree that they affect.
+type instanceSide struct {
+	actions InstanceActions
+	// Remove actions by target.
+	removes map[*Instance]*InstanceAction
+	// Move actions by target.
+	moves map[*Instance]*InstanceAction
+	// Change actions by target and property.
+	changes map[*Instance]map[string]*InstanceAction
+	// Actions that modify an instance, by target. Includes changes, moves, and
+	// actions that add or move an instance into the target.
+	modifies map[*Instance]*InstanceAction
+	// Add actions by parent.
+	adds map[*Instance][]*InstanceAction
+}
+
+// newInstanceSide indexes actions against the tree of base.
+func newInstanceSide(base *Instance, refs map[string]*Instance, actions InstanceActions) *instanceSide {
+	side := &instanceSide{
+		actions:  actions,
+		removes:  map[*Instance]*InstanceAction{},
+		moves:    map[*Instance]*InstanceAction{},
+		changes:  map[*Instance]map[string]*InstanceAction{},
+		modifies: map[*Instance]*InstanceAction{},
+		adds:     map[*Instance][]*InstanceAction{},
+	}
+	modify := func(inst *Instance, action *InstanceAction) {
+		if _, ok := side.modifies[inst]; !ok && inst != nil {
+			side.modifies[inst] = action
+		}
+	}
+	for _, action := range actions {
+		switch action.Action {
+		case InstanceRemove:
+			if target := locate(base, refs, action.Reference, action.Path, action.Index); target != nil {
+				side.removes[target] = action
+			}
+		case InstanceChange:
+			if target := locate(base, refs, action.Reference, action.Path, action.Index); target != nil {
+				if side.changes[target] == nil {
+					side.changes[target] = map[string]*InstanceAction{}
+				}
+				side.changes[target][action.Property] = action
+				modify(target, action)
+			}
+		case InstanceMove:
+			if target := locate(base, refs, action.Reference, action.Path, action.Index); target != nil {
+				side.moves[target] = action
+				modify(target, action)
+			}
+			modify(locate(base, refs, action.ParentReference, action.Parent, action.ParentIndex), action)
+		case InstanceAdd:
+			if parent := locate(base, refs, action.ParentReference, action.Parent, action.ParentIndex); parent != nil {
+				side.adds[parent] = append(side.adds[parent], action)
+				modify(parent, action)
+			}
+		}
+	}
+	return side
+}
+
+// modified returns an action of the side that modifies inst or one of its
+// descendants, or nil if there is no such action.
+func (side *instanceSide) modified(inst *Instance) *InstanceAction {
+	if inst == nil {
+		return nil
+	}
+	if action, ok := side.modifies[inst]; ok {
+		return action
+	}
+	for _, child := range inst.children {
+		if action := side.modified(child); action != nil {
+			return action
+		}
+	}
+	return nil
+}
+
+// removed returns the Remove action of the side that removes inst or one of its
+// ancestors, or nil if there is no such action.
+func (side *instanceSide) removed(inst *Instance) *InstanceAction {
+	for ; inst != nil; inst = inst.parent {
+		if action, ok := side.removes[inst]; ok {
+			return action
+		}
+	}
+	return nil
+}
+
+// mergeValuesEqual returns whether the property values a and b, which belong to
+// different trees, are equal. References are equal if they refer to instances
+// with the same path, index, and class within their respective trees.
+func mergeValuesEqual(a, b interface{}) bool {
+	switch a := a.(type) {
+	case nil:
+		return b == nil
+	case *Instance:
+		b, ok := b.(*Instance)
+		if !ok || a.ClassName != b.ClassName {
+			return false
+		}
+		pa, ia := instanceTreePath(a)
+		pb, ib := instanceTreePath(b)
+		return reflect.DeepEqual(pa, pb) && reflect.DeepEqual(ia, ib)
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// instanceTreePath returns the path and index of inst from the root of its
+// tree.
+func instanceTreePath(inst *Instance) ([]string, []int) {
+	top := inst
+	for top.parent != nil {
+		top = top.parent
+	}
+	path, index, _ := pathOf(top, inst)
+	return path, index
+}
+
+// instancesEqual returns whether a and b are equivalent trees.
+func instancesEqual(a, b *Instance) bool {
+	return a.ClassName == b.ClassName &&
+		a.Name() == b.Name() &&
+		len(a.Diff(b, DiffByPath)) == 0
+}
+
+// Merge performs a three-way merge, where inst is the base tree, and ours and
+// theirs are trees derived from the base. Instances are matched according to
+// mode. Returns a new tree that includes the changes made by both sides, and a
+// list of conflicts between changes that could not both be applied.
+//
+// Changes made by both sides are applied only once. A conflict is resolved by
+// keeping the change made by ours.
+func (inst *Instance) Merge(ours, theirs *Instance, mode InstanceDiffMode) (merged *Instance, conflicts InstanceConflicts, err error) {
+	refs := references(inst)
+	o := newInstanceSide(inst, refs, inst.Diff(ours, mode))
+	t := newInstanceSide(inst, refs, inst.Diff(theirs, mode))
+
+	conflict := func(target *Instance, ours, theirs *InstanceAction) {
+		path, _, _ := pathOf(inst, target)
+		conflicts = append(conflicts, &InstanceConflict{
+			Path:   path,
+			Ours:   ours,
+			Theirs: theirs,
+		})
+	}
+
+	// Select the actions of theirs that can be applied with the actions of
+	// ours.
+	actions := append(InstanceActions{}, o.actions...)
+	for _, action := range t.actions {
+		switch action.Action {
+		case InstanceRemove:
+			target := locate(inst, refs, action.Reference, action.Path, action.Index)
+			if o.removed(target) != nil {
+				// Already removed.
+				continue
+			}
+			if a := o.modified(target); a != nil {
+				conflict(target, a, action)
+				continue
+			}
+		case InstanceChange:
+			target := locate(inst, refs, action.Reference, action.Path, action.Index)
+			if a := o.removed(target); a != nil {
+				conflict(target, a, action)
+				continue
+			}
+			if a, ok := o.changes[target][action.Property]; ok {
+				if !mergeValuesEqual(a.Next, action.Next) {
+					conflict(target, a, action)
+				}
+				continue
+			}
+		case InstanceMove:
+			target := locate(inst, refs, action.Reference, action.Path, action.Index)
+			if a := o.removed(target); a != nil {
+				conflict(target, a, action)
+				continue
+			}
+			if parent := locate(inst, refs, action.ParentReference, action.Parent, action.ParentIndex); parent != nil {
+				if a := o.removed(parent); a != nil {
+					conflict(parent, a, action)
+					continue
+				}
+			}
+			if a, ok := o.moves[target]; ok {
+				if !reflect.DeepEqual(a.Parent, action.Parent) || !reflect.DeepEqual(a.ParentIndex, action.ParentIndex) {
+					conflict(target, a, action)
+				}
+				continue
+			}
+		case InstanceAdd:
+			parent := locate(inst, refs, action.ParentReference, action.Parent, action.ParentIndex)
+			if parent == nil {
+				break
+			}
+			if a := o.removed(parent); a != nil {
+				conflict(parent, a, action)
+				continue
+			}
+			duplicate := false
+			for _, a := range o.adds[parent] {
+				if instancesEqual(a.Instance, action.Instance) {
+					duplicate = true
+					break
+				}
+			}
+			if duplicate {
+				continue
+			}
+		}
+		actions = append(actions, action)
+	}
+
+	// Order actions as produced by Diff.
+	order := func(t InstanceActionType) int {
+		switch t {
+		case InstanceAdd:
+			return 0
+		case InstanceMove:
+			return 1
+		case InstanceChange:
+			return 2
+		default:
+			return 3
+		}
+	}
+	sort.SliceStable(actions, func(i, j int) bool {
+		return order(actions[i].Action) < order(actions[j].Action)
+	})
+
+	merged = inst.Clone()
+	if err := merged.Patch(actions); err != nil {
+		return nil, nil, err
+	}
+	return merged, conflicts, nil
+}
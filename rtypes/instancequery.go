@@ -0,0 +1,497 @@
+package rtypes
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/robloxapi/types"
+)
+
+// Selector matches instances according to a CSS-like selector. A selector is
+// parsed by ParseSelector, and has the following grammar:
+//
+//	selector   = chain { "," chain }
+//	chain      = compound { [ ">" ] compound }
+//	compound   = ( class | "*" ) { filter } | filter { filter }
+//	filter     = "#" name | "." tag | "[" [ "." ] attribute [ op value ] "]"
+//	op         = "=" | "!=" | "<" | "<=" | ">" | ">="
+//
+// A class matches instances that inherit from the class, according to the
+// descriptor of the instance. A name matches the Name property of the instance,
+// and a tag matches a tag of the instance. An attribute with no operator
+// matches instances that have the attribute. An attribute preceded by "."
+// instead matches a property of the instance.
+//
+// Compounds separated by whitespace match descendants, while compounds
+// separated by ">" match children. Names, tags, attributes, and values are
+// identifiers, or strings quoted with " or '. A value may also be a number, or
+// true or false.
+type Selector []selectorChain
+
+// selectorChain is a sequence of compound selectors, where the last compound
+// matches the target instance.
+type selectorChain []selectorCompound
+
+// selectorCompound matches a single instance.
+type selectorCompound struct {
+	// Relation to the previous compound: '>' for child, ' ' for descendant,
+	// or 0 for the first compound.
+	combinator byte
+	// Class to match, or empty to match any class.
+	class string
+	names []string
+	tags  []string
+	attrs []selectorAttr
+}
+
+// selectorAttr matches the value of an attribute or property.
+type selectorAttr struct {
+	name string
+	// Whether name refers to a property instead of an attribute.
+	property bool
+	// Comparison operator, or empty to match the existence of the attribute.
+	op    string
+	value types.Value
+}
+
+// ParseSelector parses s into a Selector.
+func ParseSelector(s string) (sel Selector, err error) {
+	p := selectorParser{s: s}
+	for {
+		chain, err := p.chain()
+		if err != nil {
+			return nil, err
+		}
+		sel = append(sel, chain)
+		p.space()
+		if p.eof() {
+			return sel, nil
+		}
+		if !p.accept(",") {
+			return nil, p.errorf("unexpected %q", p.peek())
+		}
+	}
+}
+
+// QueryAll returns each descendant of root that matches the selector, in the
+// same order as Descendants. Only ancestors that are descendants of root are
+// considered by combinators. g provides the fallback descriptor, AttrConfig,
+// and TagConfig.
+func (sel Selector) QueryAll(g Global, root *Instance) []*Instance {
+	matches := []*Instance{}
+	for _, inst := range root.Descendants() {
+		if sel.Match(g, root, inst) {
+			matches = append(matches, inst)
+		}
+	}
+	return matches
+}
+
+// Query returns the first descendant of root that matches the selector, or nil
+// if no descendant matches.
+func (sel Selector) Query(g Global, root *Instance) *Instance {
+	var match *Instance
+	root.ForEachDescendant(func(inst *Instance) error {
+		if sel.Match(g, root, inst) {
+			match = inst
+			return errStop
+		}
+		return nil
+	})
+	return match
+}
+
+// errStop stops iteration without indicating an error.
+var errStop = errors.New("stop")
+
+// Match returns whether inst, a descendant of root, matches the selector.
+func (sel Selector) Match(g Global, root, inst *Instance) bool {
+	for _, chain := range sel {
+		if chain.match(g, root, inst, len(chain)-1) {
+			return true
+		}
+	}
+	return false
+}
+
+// match returns whether inst matches compound i of the chain, and whether
+// inst's ancestors match the preceding compounds.
+func (c selectorChain) match(g Global, root, inst *Instance, i int) bool {
+	if !c[i].match(g, inst) {
+		return false
+	}
+	if i == 0 {
+		return true
+	}
+	switch c[i].combinator {
+	case '>':
+		parent := inst.Parent()
+		return parent != nil && parent != root && c.match(g, root, parent, i-1)
+	default:
+		for parent := inst.Parent(); parent != nil && parent != root; parent = parent.Parent() {
+			if c.match(g, root, parent, i-1) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// match returns whether inst matches the compound.
+func (c selectorCompound) match(g Global, inst *Instance) bool {
+	if c.class != "" && !inst.WithDescIsA(g.Desc, c.class) {
+		return false
+	}
+	for _, name := range c.names {
+		if inst.Name() != name {
+			return false
+		}
+	}
+	if len(c.tags) > 0 {
+		tags := selectorTags(g, inst)
+	loop:
+		for _, tag := range c.tags {
+			for _, t := range tags {
+				if t == tag {
+					continue loop
+				}
+			}
+			return false
+		}
+	}
+	var attrs Dictionary
+	for _, attr := range c.attrs {
+		var v types.Value
+		if attr.property {
+			if pv := inst.Get(attr.name); pv != nil {
+				v = pv
+			}
+		} else {
+			if attrs == nil {
+				attrs = selectorAttributes(g, inst)
+			}
+			v = attrs[attr.name]
+		}
+		if !attr.match(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// selectorTags returns the tags of inst, or nil if the tags could not be
+// decoded.
+func selectorTags(g Global, inst *Instance) []string {
+	property := "Tags"
+	if tagcfg := g.TagConfig.Of(inst); tagcfg != nil && tagcfg.Property != "" {
+		property = tagcfg.Property
+	}
+	if v, ok := inst.Get(property).(types.Stringlike); ok {
+		return DecodeTags(v.Stringlike())
+	}
+	return nil
+}
+
+// selectorAttributes returns the attributes of inst, or an empty Dictionary if
+// the attributes could not be decoded.
+func selectorAttributes(g Global, inst *Instance) Dictionary {
+	property := "AttributesSerialize"
+	if attrcfg := g.AttrConfig.Of(inst); attrcfg != nil && attrcfg.Property != "" {
+		property = attrcfg.Property
+	}
+	v, ok := inst.Get(property).(types.Stringlike)
+	if !ok {
+		return Dictionary{}
+	}
+	dict, err := DecodeAttributes(strings.NewReader(v.Stringlike()))
+	if err != nil {
+		return Dictionary{}
+	}
+	return dict.(Dictionary)
+}
+
+// match returns whether v, the value of the attribute or property, matches.
+// v is nil if the attribute or property is not set.
+func (a selectorAttr) match(v types.Value) bool {
+	if v == nil {
+		return false
+	}
+	if a.op == "" {
+		return true
+	}
+	var cmp int
+	switch want := a.value.(type) {
+	case types.Double:
+		n, ok := v.(types.Numberlike)
+		if !ok {
+			return a.op == "!="
+		}
+		switch x, y := n.Numberlike(), float64(want); {
+		case x < y:
+			cmp = -1
+		case x > y:
+			cmp = 1
+		}
+	case types.String:
+		s, ok := v.(types.Stringlike)
+		if !ok {
+			return a.op == "!="
+		}
+		cmp = strings.Compare(s.Stringlike(), string(want))
+	case types.Bool:
+		b, ok := v.(types.Bool)
+		if !ok {
+			return a.op == "!="
+		}
+		if b != want {
+			cmp = 1
+		}
+	}
+	switch a.op {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+// selectorParser parses a selector string.
+type selectorParser struct {
+	s string
+	i int
+}
+
+func (p *selectorParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("selector: offset %d: %s", p.i, fmt.Sprintf(format, args...))
+}
+
+func (p *selectorParser) eof() bool {
+	return p.i >= len(p.s)
+}
+
+// peek returns the next rune.
+func (p *selectorParser) peek() rune {
+	r, _ := utf8.DecodeRuneInString(p.s[p.i:])
+	return r
+}
+
+// accept consumes s if it is next, returning whether it was consumed.
+func (p *selectorParser) accept(s string) bool {
+	if strings.HasPrefix(p.s[p.i:], s) {
+		p.i += len(s)
+		return true
+	}
+	return false
+}
+
+// space consumes whitespace, returning whether any was consumed.
+func (p *selectorParser) space() bool {
+	start := p.i
+	for !p.eof() {
+		r, n := utf8.DecodeRuneInString(p.s[p.i:])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		p.i += n
+	}
+	return p.i > start
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// ident consumes an identifier, returning an empty string if there is none.
+func (p *selectorParser) ident() string {
+	start := p.i
+	for !p.eof() {
+		r, n := utf8.DecodeRuneInString(p.s[p.i:])
+		if !isIdentRune(r) {
+			break
+		}
+		p.i += n
+	}
+	return p.s[start:p.i]
+}
+
+// quoted consumes a quoted string, in which a backslash escapes the next
+// character.
+func (p *selectorParser) quoted() (string, error) {
+	quote := p.s[p.i]
+	p.i++
+	var b strings.Builder
+	for !p.eof() {
+		c := p.s[p.i]
+		p.i++
+		switch c {
+		case quote:
+			return b.String(), nil
+		case '\\':
+			if p.eof() {
+				return "", p.errorf("unterminated string")
+			}
+			b.WriteByte(p.s[p.i])
+			p.i++
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+// name consumes an identifier or quoted string.
+func (p *selectorParser) name(what string) (string, error) {
+	if !p.eof() && (p.s[p.i] == '"' || p.s[p.i] == '\'') {
+		return p.quoted()
+	}
+	if s := p.ident(); s != "" {
+		return s, nil
+	}
+	if p.eof() {
+		return "", p.errorf("expected %s", what)
+	}
+	return "", p.errorf("expected %s, got %q", what, p.peek())
+}
+
+// value consumes an attribute value.
+func (p *selectorParser) value() (types.Value, error) {
+	if p.eof() {
+		return nil, p.errorf("expected value")
+	}
+	switch c := p.s[p.i]; {
+	case c == '"' || c == '\'':
+		s, err := p.quoted()
+		return types.String(s), err
+	case c == '-' || c == '+' || c == '.' || '0' <= c && c <= '9':
+		start := p.i
+		for !p.eof() && strings.IndexByte("0123456789.eE+-", p.s[p.i]) >= 0 {
+			p.i++
+		}
+		n, err := strconv.ParseFloat(p.s[start:p.i], 64)
+		if err != nil {
+			return nil, p.errorf("invalid number %q", p.s[start:p.i])
+		}
+		return types.Double(n), nil
+	}
+	switch s, err := p.name("value"); {
+	case err != nil:
+		return nil, err
+	case s == "true":
+		return types.True, nil
+	case s == "false":
+		return types.False, nil
+	default:
+		return types.String(s), nil
+	}
+}
+
+// attr consumes an attribute filter, after the opening bracket.
+func (p *selectorParser) attr() (attr selectorAttr, err error) {
+	p.space()
+	attr.property = p.accept(".")
+	if attr.name, err = p.name("attribute"); err != nil {
+		return attr, err
+	}
+	p.space()
+	for _, op := range []string{"!=", "<=", ">=", "=", "<", ">"} {
+		if p.accept(op) {
+			attr.op = op
+			break
+		}
+	}
+	if attr.op != "" {
+		p.space()
+		if attr.value, err = p.value(); err != nil {
+			return attr, err
+		}
+		if _, ok := attr.value.(types.Bool); ok && attr.op != "=" && attr.op != "!=" {
+			return attr, p.errorf("cannot compare bool with %s", attr.op)
+		}
+		p.space()
+	}
+	if !p.accept("]") {
+		return attr, p.errorf("expected ']'")
+	}
+	return attr, nil
+}
+
+// compound consumes a compound selector.
+func (p *selectorParser) compound() (c selectorCompound, err error) {
+	start := p.i
+	if !p.accept("*") && !p.eof() && isIdentRune(p.peek()) {
+		c.class = p.ident()
+	}
+	for !p.eof() {
+		switch p.s[p.i] {
+		case '#':
+			p.i++
+			name, err := p.name("name")
+			if err != nil {
+				return c, err
+			}
+			c.names = append(c.names, name)
+		case '.':
+			p.i++
+			tag, err := p.name("tag")
+			if err != nil {
+				return c, err
+			}
+			c.tags = append(c.tags, tag)
+		case '[':
+			p.i++
+			attr, err := p.attr()
+			if err != nil {
+				return c, err
+			}
+			c.attrs = append(c.attrs, attr)
+		default:
+			if p.i == start {
+				return c, p.errorf("expected selector, got %q", p.peek())
+			}
+			return c, nil
+		}
+	}
+	if p.i == start {
+		return c, p.errorf("expected selector")
+	}
+	return c, nil
+}
+
+// chain consumes a chain of compound selectors.
+func (p *selectorParser) chain() (chain selectorChain, err error) {
+	p.space()
+	var combinator byte
+	for {
+		c, err := p.compound()
+		if err != nil {
+			return nil, err
+		}
+		c.combinator = combinator
+		chain = append(chain, c)
+		space := p.space()
+		switch {
+		case p.eof() || p.peek() == ',':
+			return chain, nil
+		case p.accept(">"):
+			combinator = '>'
+			p.space()
+		case space:
+			combinator = ' '
+		default:
+			return nil, p.errorf("unexpected %q", p.peek())
+		}
+	}
+}
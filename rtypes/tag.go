@@ -0,0 +1,52 @@
+package rtypes
+
+import (
+	"strings"
+)
+
+const T_TagConfig = "TagConfig"
+
+// TagConfig configures an Instance's tags API.
+type TagConfig struct {
+	// Property is the name of the property to which tags will be serialized.
+	// An empty string defaults to "Tags".
+	Property string
+}
+
+// Type returns a string identifying the type of the value.
+func (*TagConfig) Type() string {
+	return T_TagConfig
+}
+
+// String returns a string representation of the value.
+func (*TagConfig) String() string {
+	return "Tag"
+}
+
+// Of returns the TagConfig of an instance. If inst is nil, t is returned.
+func (t *TagConfig) Of(inst *Instance) *TagConfig {
+	if inst != nil {
+		if tagcfg := inst.TagConfig(); tagcfg != nil {
+			return tagcfg
+		}
+	}
+	return t
+}
+
+// DecodeTags decodes a list of tags from s, which contains tag names separated
+// by NUL characters. Empty names are ignored.
+func DecodeTags(s string) []string {
+	tags := []string{}
+	for _, tag := range strings.Split(s, "\x00") {
+		if tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// EncodeTags encodes a list of tags by separating each tag name with a NUL
+// character.
+func EncodeTags(tags []string) string {
+	return strings.Join(tags, "\x00")
+}